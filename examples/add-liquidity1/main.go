@@ -98,7 +98,7 @@ func main() {
 	if algoAmountsIn.Cmp(big.NewInt(1_000_000)) < 0 {
 
 		// Prepare the mint transactions from the quote and sign them
-		transactionGroup, err := pool.PrepareMintTransactionsFromQuote(quote, "")
+		transactionGroup, err := pool.PrepareMintTransactionsFromQuote(quote, "", nil)
 		if err != nil {
 			fmt.Printf("error preparing mint transactions from quote: %s\n", err)
 			return
@@ -130,7 +130,7 @@ func main() {
 					Amount: amount,
 				}
 
-				transactionGroup, err := pool.PrepareRedeemTransactions(assetAmount, "")
+				transactionGroup, err := pool.PrepareRedeemTransactions(assetAmount, "", nil)
 				if err != nil {
 					fmt.Printf("error preparing redeem transactions: %s\n", err)
 					return
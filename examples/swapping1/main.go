@@ -70,7 +70,7 @@ func main() {
 
 		fmt.Println("Account not opted into app, opting in now...")
 
-		transactionGroup, err := client.PrepareAppOptinTransactions("")
+		transactionGroup, err := client.PrepareAppOptinTransactions("", nil)
 		if err != nil {
 			fmt.Printf("error preparing app optin transactions: %s\n", err)
 			return
@@ -139,8 +139,11 @@ func main() {
 
 		fmt.Printf("Swapping %v to %v\n", quote.AmountIn, amountOutWithSlippage)
 
-		// Prepare a transaction group
-		transactionGroup, err := pool.PrepareSwapTransactionsFromQuote(quote, "")
+		// Prepare a transaction group that is only valid for the next 20 rounds
+		options := types.NewTxnOptions()
+		options.ValidityWindow = 20
+
+		transactionGroup, err := pool.PrepareSwapTransactionsFromQuote(quote, "", options)
 		if err != nil {
 			fmt.Printf("error preparing swap transactions from quote: %s\n", err)
 			return
@@ -182,7 +185,7 @@ func main() {
 					Amount: excess,
 				}
 
-				transactionGroup, err := pool.PrepareRedeemTransactions(assetAmount, "")
+				transactionGroup, err := pool.PrepareRedeemTransactions(assetAmount, "", nil)
 				if err != nil {
 					fmt.Printf("error preparing redeem transactions: %s\n", err)
 					return
@@ -2,21 +2,25 @@ module github.com/soheil555/tinyman-mobile-sdk
 
 go 1.18
 
+require (
+	github.com/algorand/go-algorand-sdk v1.14.0
+	github.com/algorand/go-codec/codec v1.1.8
+	github.com/joho/godotenv v1.4.0
+	github.com/kr/pretty v0.3.0
+	github.com/stretchr/testify v1.7.1
+	gopkg.in/h2non/gock.v1 v1.1.2
+)
+
 require (
 	github.com/algorand/go-algorand v0.0.0-20220323144801-17c0feef002f // indirect
-	github.com/algorand/go-algorand-sdk v1.14.0 // indirect
-	github.com/algorand/go-codec/codec v1.1.8 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/go-querystring v1.0.0 // indirect
 	github.com/h2non/parth v0.0.0-20190131123155-b4df798d6542 // indirect
-	github.com/joho/godotenv v1.4.0 // indirect
-	github.com/kr/pretty v0.3.0 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.6.1 // indirect
 	github.com/stretchr/objx v0.1.0 // indirect
-	github.com/stretchr/testify v1.7.1 // indirect
 	github.com/vmihailenco/msgpack v4.0.4+incompatible // indirect
 	golang.org/x/crypto v0.0.0-20210921155107-089bfa567519 // indirect
 	golang.org/x/mobile v0.0.0-20220414153400-ce6a79cf6a13 // indirect
@@ -27,6 +31,5 @@ require (
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/protobuf v1.28.0 // indirect
-	gopkg.in/h2non/gock.v1 v1.1.2 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776 // indirect
 )
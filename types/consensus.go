@@ -0,0 +1,74 @@
+package types
+
+// ConsensusParams holds the consensus parameters that drive minimum balance and fee calculations.
+type ConsensusParams struct {
+	MinBalance               int `json:"min-balance"`
+	MinTxnFee                int `json:"min-txn-fee"`
+	MaxTxGroupSize           int `json:"max-tx-group-size"`
+	AppFlatParamsMinBalance  int `json:"app-flat-params-min-balance"`
+	AppFlatOptInMinBalance   int `json:"app-flat-optin-min-balance"`
+	SchemaMinBalancePerEntry int `json:"schema-min-balance-per-entry"`
+	SchemaUintMinBalance     int `json:"schema-uint-min-balance"`
+	SchemaBytesMinBalance    int `json:"schema-bytes-min-balance"`
+}
+
+// CurrentConsensusParams are the parameters of the current Algorand protocol.
+// They are used for consensus versions that were not registered with RegisterConsensusParams.
+var CurrentConsensusParams = ConsensusParams{
+	MinBalance:               100000,
+	MinTxnFee:                1000,
+	MaxTxGroupSize:           16,
+	AppFlatParamsMinBalance:  100000,
+	AppFlatOptInMinBalance:   100000,
+	SchemaMinBalancePerEntry: 25000,
+	SchemaUintMinBalance:     3500,
+	SchemaBytesMinBalance:    25000,
+}
+
+var consensusParams = map[string]*ConsensusParams{}
+
+// RegisterConsensusParams sets the parameters used for consensusVersion.
+func RegisterConsensusParams(consensusVersion string, params *ConsensusParams) {
+	consensusParams[consensusVersion] = params
+}
+
+// GetConsensusParams returns the parameters registered for consensusVersion, or the current ones.
+func GetConsensusParams(consensusVersion string) *ConsensusParams {
+
+	if params, ok := consensusParams[consensusVersion]; ok {
+		return params
+	}
+
+	params := CurrentConsensusParams
+	return &params
+
+}
+
+// AssetMinBalance is the minimum balance increase caused by holding one asset.
+func (s *ConsensusParams) AssetMinBalance() int {
+	return s.MinBalance
+}
+
+// AppOptinMinBalance is the minimum balance increase caused by opting into an app with the given local schema.
+func (s *ConsensusParams) AppOptinMinBalance(numUints, numByteSlices int) int {
+	return s.AppFlatOptInMinBalance + s.schemaMinBalance(numUints, numByteSlices)
+}
+
+// AccountMinBalance is the minimum balance of an account. numUints and numByteSlices are the total
+// schema of the apps the account created and opted into, extraPages the total extra program pages
+// of the apps it created.
+func (s *ConsensusParams) AccountMinBalance(numAssets, numAppsOptedIn, numCreatedApps, numUints, numByteSlices, extraPages int) int {
+
+	minBalance := s.MinBalance
+	minBalance += s.MinBalance * numAssets
+	minBalance += s.AppFlatOptInMinBalance * numAppsOptedIn
+	minBalance += s.AppFlatParamsMinBalance * (numCreatedApps + extraPages)
+	minBalance += s.schemaMinBalance(numUints, numByteSlices)
+
+	return minBalance
+
+}
+
+func (s *ConsensusParams) schemaMinBalance(numUints, numByteSlices int) int {
+	return (s.SchemaMinBalancePerEntry+s.SchemaUintMinBalance)*numUints + (s.SchemaMinBalancePerEntry+s.SchemaBytesMinBalance)*numByteSlices
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+const (
+	INTEGRATOR_FEE_INPUT  = "input"
+	INTEGRATOR_FEE_OUTPUT = "output"
+
+	MAX_INTEGRATOR_FEE_BASIS_POINTS = 1000
+
+	// INTEGRATOR_FEE_NOTE is the note of the integrator fee transfer.
+	INTEGRATOR_FEE_NOTE = "integrator-fee"
+)
+
+// IntegratorFee is a platform fee in basis points of the input or output asset of a swap, paid to Address.
+type IntegratorFee struct {
+	Address     string `json:"address"`
+	BasisPoints int    `json:"basis-points"`
+	Side        string `json:"side"`
+}
+
+func NewIntegratorFee(address string, basisPoints int, side string) (integratorFee *IntegratorFee, err error) {
+
+	receiver, err := algoTypes.DecodeAddress(address)
+	if err != nil {
+		return
+	}
+
+	if basisPoints < 0 || basisPoints > MAX_INTEGRATOR_FEE_BASIS_POINTS {
+		err = fmt.Errorf("integrator fee must be between 0 and %d basis points", MAX_INTEGRATOR_FEE_BASIS_POINTS)
+		return
+	}
+
+	if side != INTEGRATOR_FEE_INPUT && side != INTEGRATOR_FEE_OUTPUT {
+		err = fmt.Errorf("unsupported integrator fee side %s", side)
+		return
+	}
+
+	integratorFee = &IntegratorFee{receiver.String(), basisPoints, side}
+	return
+
+}
+
+// Of returns the fee for amount, rounded down.
+func (s *IntegratorFee) Of(amount *AssetAmount) *AssetAmount {
+
+	fee := newBigIntString(amount.Amount)
+	fee.Mul(fee, big.NewInt(int64(s.BasisPoints)))
+	fee.Quo(fee, big.NewInt(10000))
+
+	return &AssetAmount{amount.Asset, fee.String()}
+
+}
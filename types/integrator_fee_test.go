@@ -0,0 +1,28 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegratorFee(t *testing.T) {
+
+	treasury := crypto.GenerateAccount()
+
+	integratorFee, err := NewIntegratorFee(treasury.Address.String(), 25, INTEGRATOR_FEE_OUTPUT)
+	assert.Nil(t, err)
+
+	asset := &Asset{Id: 1, Name: "Test", UnitName: "TEST", Decimals: 6}
+
+	assert.Equal(t, "2500", integratorFee.Of(asset.Call("1000000")).Amount)
+	assert.Equal(t, "0", integratorFee.Of(asset.Call("399")).Amount)
+
+	_, err = NewIntegratorFee(treasury.Address.String(), MAX_INTEGRATOR_FEE_BASIS_POINTS+1, INTEGRATOR_FEE_INPUT)
+	assert.NotNil(t, err)
+
+	_, err = NewIntegratorFee(treasury.Address.String(), 25, "both")
+	assert.NotNil(t, err)
+
+}
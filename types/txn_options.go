@@ -0,0 +1,39 @@
+package types
+
+// DEFAULT_MAX_QUOTE_AGE is the number of rounds after the round of its pool state that a quote can still be prepared,
+// unless TxnOptions.MaxQuoteAge is set.
+const DEFAULT_MAX_QUOTE_AGE = 30
+
+// TxnOptions controls how the transactions of an operation are built.
+// A nil *TxnOptions keeps the defaults: suggested fees, the suggested validity window, no note and no lease.
+//
+// FlatFee and FeeMultiplier only apply to the transactions sent by the user,
+// the pool transactions keep the fee covered by the Tinyman fee payment.
+type TxnOptions struct {
+	// FlatFee is the fee in microAlgos of each user transaction. It takes precedence over FeeMultiplier.
+	FlatFee int `json:"flat-fee"`
+	// FeeMultiplier scales the suggested fee of each user transaction, e.g. 2 during congestion.
+	FeeMultiplier float64 `json:"fee-multiplier"`
+	// ValidityWindow is the number of rounds the group stays valid for, starting at the first valid round.
+	ValidityWindow int `json:"validity-window"`
+	// Note is set on the user transactions that have no note of their own.
+	Note []byte `json:"note"`
+	// Lease is a 32 bytes lease set on the first user transaction, so the same group can not be submitted twice.
+	Lease []byte `json:"lease"`
+	// FeePayer is an optional sponsor address that sends the Tinyman fee payment and covers the fees of the
+	// user transactions through fee pooling. The group then has to be signed by both the user and the fee payer.
+	FeePayer string `json:"fee-payer"`
+	// SuggestedParams is a recent snapshot of the suggested params used instead of fetching them from algod.
+	SuggestedParams *SuggestedParams `json:"suggested-params"`
+	// MaxQuoteAge is the number of rounds after the round of its pool state that a quote can still be prepared,
+	// and the last valid round of the prepared group. 0 is DEFAULT_MAX_QUOTE_AGE, a negative value disables the check.
+	MaxQuoteAge int `json:"max-quote-age"`
+}
+
+func NewTxnOptions() *TxnOptions {
+	return &TxnOptions{}
+}
+
+func (s *TxnOptions) SetNoteStr(note string) {
+	s.Note = []byte(note)
+}
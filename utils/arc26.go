@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+const arc26Scheme = "algorand"
+
+// PaymentRequest is an ARC-26 payment request, e.g. algorand://ADDRESS?amount=1000000&asset=31566704&xnote=order-42
+// Amount is in the base units of the asset, AssetId is 0 for ALGO.
+type PaymentRequest struct {
+	Address string `json:"address"`
+	Amount  string `json:"amount"`
+	AssetId int    `json:"asset-id"`
+	Label   string `json:"label,omitempty"`
+	Note    string `json:"note,omitempty"`
+	// XNote is a note the payer must not modify, it takes precedence over Note.
+	XNote string `json:"xnote,omitempty"`
+}
+
+func ParsePaymentRequestURI(uri string) (paymentRequest *PaymentRequest, err error) {
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return
+	}
+
+	if u.Scheme != arc26Scheme {
+		err = fmt.Errorf("unsupported payment request scheme %s", u.Scheme)
+		return
+	}
+
+	// algorand://ADDRESS is parsed with the address as host, algorand:ADDRESS as opaque
+	address := u.Host
+	if len(address) == 0 {
+		address = strings.TrimPrefix(u.Opaque, "//")
+	}
+
+	receiver, err := algoTypes.DecodeAddress(address)
+	if err != nil {
+		return
+	}
+
+	query := u.Query()
+
+	paymentRequest = &PaymentRequest{
+		Address: receiver.String(),
+		Amount:  "0",
+		Label:   query.Get("label"),
+		Note:    query.Get("note"),
+		XNote:   query.Get("xnote"),
+	}
+
+	if amount := query.Get("amount"); len(amount) > 0 {
+
+		if _, err = strconv.ParseUint(amount, 10, 64); err != nil {
+			err = fmt.Errorf("invalid payment request amount %s", amount)
+			return
+		}
+
+		paymentRequest.Amount = amount
+
+	}
+
+	if asset := query.Get("asset"); len(asset) > 0 {
+
+		paymentRequest.AssetId, err = strconv.Atoi(asset)
+		if err != nil || paymentRequest.AssetId < 0 {
+			err = fmt.Errorf("invalid payment request asset %s", asset)
+			return
+		}
+
+	}
+
+	return
+
+}
+
+// GetNote returns the note the payment must carry, xnote if set and note otherwise.
+func (s *PaymentRequest) GetNote() string {
+
+	if len(s.XNote) > 0 {
+		return s.XNote
+	}
+
+	return s.Note
+
+}
@@ -0,0 +1,429 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// AssetFetcher resolves asset details (unit name, decimals) by ID.
+// TinymanClient implements it through its assets cache.
+type AssetFetcher interface {
+	FetchAsset(assetID int) (*types.Asset, error)
+}
+
+type TransactionDescription struct {
+	Index         int    `json:"index"`
+	Type          string `json:"type"`
+	Sender        string `json:"sender"`
+	Receiver      string `json:"receiver,omitempty"`
+	AssetId       int    `json:"asset-id"`
+	AssetUnitName string `json:"asset-unit-name,omitempty"`
+	AssetDecimals int    `json:"asset-decimals"`
+	Amount        string `json:"amount"`
+	Fee           string `json:"fee"`
+	AppId         int    `json:"app-id,omitempty"`
+	Operation     string `json:"operation,omitempty"`
+	Signer        string `json:"signer"`
+	Summary       string `json:"summary"`
+}
+
+type BalanceChange struct {
+	AssetId       int    `json:"asset-id"`
+	AssetUnitName string `json:"asset-unit-name"`
+	AssetDecimals int    `json:"asset-decimals"`
+	Amount        string `json:"amount"`
+}
+
+type TransactionGroupDescription struct {
+	UserAddress    string                    `json:"user-address"`
+	Transactions   []*TransactionDescription `json:"transactions"`
+	BalanceChanges []*BalanceChange          `json:"balance-changes"`
+}
+
+// not compatible with go-mobile
+func (s *TransactionGroupDescription) GetTransactions() []*TransactionDescription {
+	return s.Transactions
+}
+
+func (s *TransactionGroupDescription) TransactionsLen() int {
+	return len(s.Transactions)
+}
+
+func (s *TransactionGroupDescription) GetTransaction(index int) *TransactionDescription {
+	return s.Transactions[index]
+}
+
+func (s *TransactionGroupDescription) BalanceChangesLen() int {
+	return len(s.BalanceChanges)
+}
+
+func (s *TransactionGroupDescription) GetBalanceChange(index int) *BalanceChange {
+	return s.BalanceChanges[index]
+}
+
+func (s *TransactionGroupDescription) JSON() (descriptionStr string, err error) {
+
+	descriptionBytes, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	descriptionStr = string(descriptionBytes)
+	return
+
+}
+
+func (s *TransactionGroupDescription) Text() string {
+
+	var builder strings.Builder
+
+	for _, txn := range s.Transactions {
+		builder.WriteString(fmt.Sprintf("%d. %s\n", txn.Index+1, txn.Summary))
+	}
+
+	if len(s.BalanceChanges) > 0 {
+		builder.WriteString("Net balance change:\n")
+	}
+
+	for _, change := range s.BalanceChanges {
+		builder.WriteString(fmt.Sprintf("  %s %s\n", formatAmount(change.Amount, change.AssetDecimals), change.AssetUnitName))
+	}
+
+	return builder.String()
+
+}
+
+// Describe returns a human-readable summary of every transaction in the group
+// and the net balance change per asset for userAddress.
+// Transactions that are already signed are considered signed by the pool logicsig.
+func (s *TransactionGroup) Describe(fetcher AssetFetcher, userAddress string) (description *TransactionGroupDescription, err error) {
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	description = &TransactionGroupDescription{
+		UserAddress: user.String(),
+	}
+
+	changes := make(map[int]*big.Int)
+
+	addChange := func(assetID int, amount *big.Int) {
+		if _, ok := changes[assetID]; !ok {
+			changes[assetID] = new(big.Int)
+		}
+		changes[assetID].Add(changes[assetID], amount)
+	}
+
+	for i, txn := range s.transactions {
+
+		var txnDescription *TransactionDescription
+		txnDescription, err = describeTransaction(fetcher, txn)
+		if err != nil {
+			return
+		}
+
+		txnDescription.Index = i
+
+		if len(s.signedTransactions[i]) > 0 {
+			txnDescription.Signer = "logicsig"
+		} else if txn.Sender == user {
+			txnDescription.Signer = "user"
+		} else {
+			txnDescription.Signer = txn.Sender.String()
+		}
+
+		txnDescription.Summary = fmt.Sprintf("%s (signed by %s)", txnDescription.Summary, txnDescription.Signer)
+
+		amount := NewBigIntString(txnDescription.Amount)
+
+		if txn.Sender == user {
+			addChange(0, new(big.Int).Neg(new(big.Int).SetUint64(uint64(txn.Fee))))
+		}
+
+		if txn.Type == algoTypes.PaymentTx || txn.Type == algoTypes.AssetTransferTx {
+
+			if txnDescription.Sender == user.String() {
+				addChange(txnDescription.AssetId, new(big.Int).Neg(amount))
+			}
+
+			if txnDescription.Receiver == user.String() {
+				addChange(txnDescription.AssetId, amount)
+			}
+
+		}
+
+		description.Transactions = append(description.Transactions, txnDescription)
+
+	}
+
+	assetIDs := make([]int, 0, len(changes))
+	for assetID := range changes {
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Ints(assetIDs)
+
+	for _, assetID := range assetIDs {
+
+		var asset *types.Asset
+		asset, err = fetcher.FetchAsset(assetID)
+		if err != nil {
+			return
+		}
+
+		description.BalanceChanges = append(description.BalanceChanges, &BalanceChange{
+			AssetId:       assetID,
+			AssetUnitName: asset.UnitName,
+			AssetDecimals: asset.Decimals,
+			Amount:        changes[assetID].String(),
+		})
+
+	}
+
+	return
+
+}
+
+func (s *TransactionGroup) DescribeStr(fetcher AssetFetcher, userAddress string) (descriptionStr string, err error) {
+
+	description, err := s.Describe(fetcher, userAddress)
+	if err != nil {
+		return
+	}
+
+	return description.JSON()
+
+}
+
+// TransactionPlanStepDescription is the description of the group of a step of a transaction plan.
+type TransactionPlanStepDescription struct {
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	NonAtomic   bool                         `json:"non-atomic"`
+	Group       *TransactionGroupDescription `json:"group"`
+}
+
+type TransactionPlanDescription struct {
+	Steps []*TransactionPlanStepDescription `json:"steps"`
+}
+
+func (s *TransactionPlanDescription) StepsLen() int {
+	return len(s.Steps)
+}
+
+func (s *TransactionPlanDescription) GetStep(index int) *TransactionPlanStepDescription {
+	return s.Steps[index]
+}
+
+func (s *TransactionPlanDescription) JSON() (descriptionStr string, err error) {
+
+	descriptionBytes, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	descriptionStr = string(descriptionBytes)
+	return
+
+}
+
+func (s *TransactionPlanDescription) Text() string {
+
+	var builder strings.Builder
+
+	for i, step := range s.Steps {
+
+		builder.WriteString(fmt.Sprintf("Step %d: %s\n", i+1, step.Description))
+
+		if step.NonAtomic {
+			builder.WriteString("Not atomic with the previous steps, they can succeed while this step fails or is never submitted.\n")
+		}
+
+		builder.WriteString(step.Group.Text())
+
+	}
+
+	return builder.String()
+
+}
+
+// Describe returns the description of the group of every step of the plan, see TransactionGroup.Describe.
+func (s *TransactionPlan) Describe(fetcher AssetFetcher, userAddress string) (description *TransactionPlanDescription, err error) {
+
+	description = &TransactionPlanDescription{}
+
+	for _, step := range s.steps {
+
+		var groupDescription *TransactionGroupDescription
+		groupDescription, err = step.group.Describe(fetcher, userAddress)
+		if err != nil {
+			return
+		}
+
+		description.Steps = append(description.Steps, &TransactionPlanStepDescription{
+			Name:        step.Name,
+			Description: step.Description,
+			NonAtomic:   step.NonAtomic,
+			Group:       groupDescription,
+		})
+
+	}
+
+	return
+
+}
+
+func describeTransaction(fetcher AssetFetcher, txn algoTypes.Transaction) (description *TransactionDescription, err error) {
+
+	description = &TransactionDescription{
+		Type:   string(txn.Type),
+		Sender: txn.Sender.String(),
+		Amount: "0",
+		Fee:    new(big.Int).SetUint64(uint64(txn.Fee)).String(),
+	}
+
+	algo, err := fetcher.FetchAsset(0)
+	if err != nil {
+		return
+	}
+
+	fee := fmt.Sprintf("fee %s %s", formatAmount(description.Fee, algo.Decimals), algo.UnitName)
+
+	switch txn.Type {
+
+	case algoTypes.PaymentTx:
+
+		description.Receiver = txn.Receiver.String()
+		description.AssetUnitName = algo.UnitName
+		description.AssetDecimals = algo.Decimals
+		description.Amount = new(big.Int).SetUint64(uint64(txn.Amount)).String()
+		description.Summary = fmt.Sprintf("Pay %s %s from %s to %s", formatAmount(description.Amount, algo.Decimals), algo.UnitName, description.Sender, description.Receiver)
+
+		if string(txn.Note) == "fee" {
+			description.Operation = "tinyman fee payment"
+		} else if string(txn.Note) == types.INTEGRATOR_FEE_NOTE {
+			description.Operation = "integrator fee"
+			description.Summary += " as integrator fee, not atomic with the swap"
+		}
+
+		if !txn.CloseRemainderTo.IsZero() {
+			description.Summary += fmt.Sprintf(", closing remaining ALGO to %s", txn.CloseRemainderTo.String())
+		}
+
+	case algoTypes.AssetTransferTx:
+
+		var asset *types.Asset
+		asset, err = fetcher.FetchAsset(int(txn.XferAsset))
+		if err != nil {
+			return
+		}
+
+		description.Receiver = txn.AssetReceiver.String()
+		description.AssetId = asset.Id
+		description.AssetUnitName = asset.UnitName
+		description.AssetDecimals = asset.Decimals
+		description.Amount = new(big.Int).SetUint64(txn.AssetAmount).String()
+
+		if txn.Sender == txn.AssetReceiver && txn.AssetAmount == 0 {
+			description.Operation = "asset opt-in"
+			description.Summary = fmt.Sprintf("Opt %s into %s (%d)", description.Sender, asset.UnitName, asset.Id)
+		} else {
+			description.Summary = fmt.Sprintf("Transfer %s %s (%d) from %s to %s", formatAmount(description.Amount, asset.Decimals), asset.UnitName, asset.Id, description.Sender, description.Receiver)
+		}
+
+		if string(txn.Note) == types.INTEGRATOR_FEE_NOTE {
+			description.Operation = "integrator fee"
+			description.Summary += " as integrator fee, not atomic with the swap"
+		}
+
+		if !txn.AssetCloseTo.IsZero() {
+			description.Summary += fmt.Sprintf(", closing remaining %s to %s", asset.UnitName, txn.AssetCloseTo.String())
+		}
+
+	case algoTypes.ApplicationCallTx:
+
+		description.AppId = int(txn.ApplicationID)
+		description.Operation = appCallOperation(txn)
+		description.Summary = fmt.Sprintf("Call app %d from %s: %s", description.AppId, description.Sender, description.Operation)
+
+	case algoTypes.AssetConfigTx:
+
+		if txn.ConfigAsset == 0 {
+			description.Operation = "create liquidity asset"
+			description.Summary = fmt.Sprintf("Create asset %s (%s) from %s", txn.AssetParams.AssetName, txn.AssetParams.UnitName, description.Sender)
+		} else {
+			description.Summary = fmt.Sprintf("Configure asset %d from %s", txn.ConfigAsset, description.Sender)
+		}
+
+	default:
+
+		description.Summary = fmt.Sprintf("%s transaction from %s", txn.Type, description.Sender)
+
+	}
+
+	if !txn.RekeyTo.IsZero() {
+		description.Summary += fmt.Sprintf(", rekeying sender to %s", txn.RekeyTo.String())
+	}
+
+	description.Summary = fmt.Sprintf("%s, %s", description.Summary, fee)
+
+	return
+
+}
+
+func appCallOperation(txn algoTypes.Transaction) string {
+
+	switch txn.OnCompletion {
+	case algoTypes.OptInOC:
+		if len(txn.ApplicationArgs) > 0 && string(txn.ApplicationArgs[0]) == "bootstrap" {
+			return "bootstrap pool"
+		}
+		return "opt into validator app"
+	case algoTypes.CloseOutOC:
+		return "opt out of validator app"
+	case algoTypes.ClearStateOC:
+		return "clear validator app state (unredeemed excess is forfeited)"
+	}
+
+	if len(txn.ApplicationArgs) == 0 {
+		return "app call"
+	}
+
+	switch string(txn.ApplicationArgs[0]) {
+	case "swap":
+		if len(txn.ApplicationArgs) > 1 && string(txn.ApplicationArgs[1]) == "fo" {
+			return "swap (fixed output)"
+		}
+		return "swap (fixed input)"
+	case "mint":
+		return "add liquidity (mint)"
+	case "burn":
+		return "remove liquidity (burn)"
+	case "redeem":
+		return "redeem excess amount"
+	case "fees":
+		return "redeem protocol fees"
+	}
+
+	return fmt.Sprintf("app call (%s)", string(txn.ApplicationArgs[0]))
+
+}
+
+func formatAmount(amount string, decimals int) string {
+
+	helper := new(big.Int)
+	helper.Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+
+	value := new(big.Float).Quo(NewBigFloatString(amount), new(big.Float).SetInt(helper))
+
+	return value.Text('f', decimals)
+
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUCache is a concurrency-safe cache that evicts the least recently used entry once it holds capacity entries.
+type LRUCache struct {
+	mutex    sync.Mutex
+	capacity int
+	items    map[interface{}]*list.Element
+	order    *list.List
+}
+
+type lruEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		items:    make(map[interface{}]*list.Element),
+		order:    list.New(),
+	}
+
+}
+
+// not compatible with go-mobile
+func (s *LRUCache) Get(key interface{}) (value interface{}, ok bool) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		return
+	}
+
+	s.order.MoveToFront(element)
+
+	return element.Value.(*lruEntry).value, true
+
+}
+
+// not compatible with go-mobile
+func (s *LRUCache) Add(key, value interface{}) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		element.Value.(*lruEntry).value = value
+		s.order.MoveToFront(element)
+		return
+	}
+
+	s.items[key] = s.order.PushFront(&lruEntry{key, value})
+
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*lruEntry).key)
+	}
+
+}
+
+func (s *LRUCache) Len() int {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.order.Len()
+
+}
+
+func (s *LRUCache) Purge() {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.items = make(map[interface{}]*list.Element)
+	s.order.Init()
+
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"fmt"
+
+	"github.com/algorand/go-algorand-sdk/transaction"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type TransactionPlanStep struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// NonAtomic is true when the step completes the operation of an earlier step from a separate group, e.g. the
+	// integrator fee of a swap. The operation can succeed while the step fails or is never submitted.
+	NonAtomic bool `json:"non-atomic"`
+	group     *TransactionGroup
+}
+
+func (s *TransactionPlanStep) GetTransactionGroup() *TransactionGroup {
+	return s.group
+}
+
+// TransactionPlan is an ordered list of transaction groups that must be submitted one after another.
+// Tinyman v1 contracts require their groups to have an exact size and reference transactions by their absolute index
+// in the group, so whatever an operation needs besides its group is a separate step of the plan. Steps are not atomic
+// with each other, the steps that complete an operation after it, e.g. an integrator fee, are marked NonAtomic.
+type TransactionPlan struct {
+	steps              []*TransactionPlanStep
+	MinBalanceIncrease string `json:"min-balance-increase"`
+}
+
+func NewTransactionPlan() *TransactionPlan {
+	return &TransactionPlan{MinBalanceIncrease: "0"}
+}
+
+func (s *TransactionPlan) AddStep(name, description string, txnGroup *TransactionGroup) {
+	s.steps = append(s.steps, &TransactionPlanStep{Name: name, Description: description, group: txnGroup})
+}
+
+// AddNonAtomicStep adds a step that completes the operation of an earlier step, see TransactionPlanStep.NonAtomic.
+func (s *TransactionPlan) AddNonAtomicStep(name, description string, txnGroup *TransactionGroup) {
+	s.steps = append(s.steps, &TransactionPlanStep{Name: name, Description: description, NonAtomic: true, group: txnGroup})
+}
+
+func (s *TransactionPlan) Len() int {
+	return len(s.steps)
+}
+
+func (s *TransactionPlan) GetStep(index int) *TransactionPlanStep {
+	return s.steps[index]
+}
+
+// MergeTransactionGroups builds a single atomic group from the transactions of unsigned groups.
+func MergeTransactionGroups(groups ...*TransactionGroup) (transactionGroup *TransactionGroup, err error) {
+
+	var transactions []algoTypes.Transaction
+
+	for _, group := range groups {
+
+		for i, txn := range group.transactions {
+
+			if len(group.signedTransactions[i]) > 0 {
+				err = fmt.Errorf("cannot merge signed transactions")
+				return
+			}
+
+			txn.Group = algoTypes.Digest{}
+			transactions = append(transactions, txn)
+
+		}
+
+	}
+
+	if len(transactions) > algoTypes.MaxTxGroupSize {
+		err = fmt.Errorf("merged group has %d transactions, max group size is %d", len(transactions), algoTypes.MaxTxGroupSize)
+		return
+	}
+
+	transactions, err = transaction.AssignGroupID(transactions, "")
+	if err != nil {
+		return
+	}
+
+	signedTransactions := make([][]byte, len(transactions))
+	return &TransactionGroup{transactions, signedTransactions}, nil
+
+}
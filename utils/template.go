@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// Template variables are encoded the way TEAL encodes constants:
+// int is a varint, uint64 is 8 bytes big-endian, bytes is a varint length followed by the bytes
+// and address is the 32 bytes of the public key.
+
+// templateVariableName is the name of v without its TMPL_ prefix, in lower case, e.g. asset_id_1.
+func templateVariableName(v types.Variable) string {
+
+	s := strings.Split(v.Name, "TMPL_")
+	return strings.ToLower(s[len(s)-1])
+
+}
+
+func sortedVariables(definition types.Logic) []types.Variable {
+
+	dVariables := append([]types.Variable{}, definition.Variables...)
+
+	sort.SliceStable(dVariables, func(i, j int) bool {
+		return dVariables[i].Index < dVariables[j].Index
+	})
+
+	return dVariables
+
+}
+
+// fillTemplate replaces every variable of template, sorted by index, with its value encoded by encode.
+func fillTemplate(template []byte, dVariables []types.Variable, encode func(v types.Variable) ([]byte, error)) (templateBytes []byte, err error) {
+
+	templateBytes = template
+	offset := 0
+
+	for _, v := range dVariables {
+
+		start := v.Index - offset
+		end := start + v.Length
+
+		var valueEncoded []byte
+		valueEncoded, err = encode(v)
+
+		if err != nil {
+			return
+		}
+
+		valueEncodedLen := len(valueEncoded)
+		diff := v.Length - valueEncodedLen
+		offset += diff
+
+		var tmp []byte
+		tmp = append(tmp, templateBytes[:start]...)
+		tmp = append(tmp, valueEncoded...)
+		tmp = append(tmp, templateBytes[end:]...)
+
+		templateBytes = tmp
+
+	}
+
+	return
+
+}
+
+// EncodeTemplateValue encodes value as a template variable of valueType:
+// an int for int and uint64, a []byte or string for bytes and an Algorand address string for address.
+// not compatible with go-mobile
+func EncodeTemplateValue(value interface{}, valueType string) (buf []byte, err error) {
+
+	switch valueType {
+
+	case "int", "uint64":
+
+		number, ok := value.(int)
+		if !ok {
+			err = fmt.Errorf("value of type %s must be an int", valueType)
+			return
+		}
+
+		return EncodeValue(number, valueType)
+
+	case "bytes":
+
+		var data []byte
+
+		switch v := value.(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			err = fmt.Errorf("value of type bytes must be a []byte or a string")
+			return
+		}
+
+		buf = append(EncodeVarint(len(data)), data...)
+		return
+
+	case "address":
+
+		addressString, ok := value.(string)
+		if !ok {
+			err = fmt.Errorf("value of type address must be a string")
+			return
+		}
+
+		var address algoTypes.Address
+		address, err = algoTypes.DecodeAddress(addressString)
+		if err != nil {
+			return
+		}
+
+		buf = address[:]
+		return
+
+	}
+
+	err = fmt.Errorf("unsupported value type %s", valueType)
+	return
+
+}
+
+// GetProgramWithValues is GetProgram for variables of any type, see EncodeTemplateValue.
+// not compatible with go-mobile
+func GetProgramWithValues(definition types.Logic, values map[string]interface{}) (programBytes []byte, err error) {
+
+	template, err := b64.StdEncoding.DecodeString(definition.Bytecode)
+	if err != nil {
+		return
+	}
+
+	return fillTemplate(template, sortedVariables(definition), func(v types.Variable) ([]byte, error) {
+
+		value, ok := values[templateVariableName(v)]
+		if !ok {
+			return nil, fmt.Errorf("no value for template variable %s", v.Name)
+		}
+
+		return EncodeTemplateValue(value, v.Type)
+
+	})
+
+}
+
+// decodeTemplateValue reads the value of a template variable of valueType at the start of program
+// and returns it with the number of bytes it takes.
+func decodeTemplateValue(program []byte, valueType string) (value interface{}, n int, err error) {
+
+	switch valueType {
+
+	case "int":
+
+		number, size := binary.Uvarint(program)
+		if size <= 0 {
+			err = fmt.Errorf("invalid int value")
+			return
+		}
+
+		return int(number), size, nil
+
+	case "uint64":
+
+		if len(program) < 8 {
+			err = fmt.Errorf("invalid uint64 value")
+			return
+		}
+
+		return int(binary.BigEndian.Uint64(program[:8])), 8, nil
+
+	case "bytes":
+
+		length, size := binary.Uvarint(program)
+		if size <= 0 || uint64(len(program)-size) < length {
+			err = fmt.Errorf("invalid bytes value")
+			return
+		}
+
+		end := size + int(length)
+
+		return append([]byte{}, program[size:end]...), end, nil
+
+	case "address":
+
+		var address algoTypes.Address
+		if len(program) < len(address) {
+			err = fmt.Errorf("invalid address value")
+			return
+		}
+
+		copy(address[:], program)
+
+		return address.String(), len(address), nil
+
+	}
+
+	err = fmt.Errorf("unsupported value type %s", valueType)
+	return
+
+}
+
+// ExtractVariables is the reverse of GetProgramWithValues: it returns the values of the template variables of definition
+// in program, keyed like the variables of GetProgram, e.g. asset_id_1. Values are ints, []byte for bytes and address strings.
+// It fails unless program is the template of definition with its variables filled in.
+// not compatible with go-mobile
+func ExtractVariables(definition types.Logic, program []byte) (values map[string]interface{}, err error) {
+
+	template, err := b64.StdEncoding.DecodeString(definition.Bytecode)
+	if err != nil {
+		return
+	}
+
+	values = make(map[string]interface{})
+
+	// templatePosition and programPosition are the end of the last variable in the template and in program
+	templatePosition := 0
+	programPosition := 0
+
+	for _, v := range sortedVariables(definition) {
+
+		if v.Index < templatePosition || v.Index+v.Length > len(template) {
+			err = fmt.Errorf("template variable %s is out of bounds", v.Name)
+			return nil, err
+		}
+
+		constant := template[templatePosition:v.Index]
+		if !bytes.HasPrefix(program[programPosition:], constant) {
+			err = fmt.Errorf("program does not match the template before %s", v.Name)
+			return nil, err
+		}
+
+		programPosition += len(constant)
+
+		value, n, decodeErr := decodeTemplateValue(program[programPosition:], v.Type)
+		if decodeErr != nil {
+			err = fmt.Errorf("template variable %s: %s", v.Name, decodeErr)
+			return nil, err
+		}
+
+		values[templateVariableName(v)] = value
+
+		templatePosition = v.Index + v.Length
+		programPosition += n
+
+	}
+
+	if !bytes.Equal(program[programPosition:], template[templatePosition:]) {
+		err = fmt.Errorf("program does not match the template")
+		return nil, err
+	}
+
+	return
+
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// MakeTransferTxn makes an asset transfer, or a payment when assetID is 0 (ALGO).
+// not compatible with go-mobile
+func MakeTransferTxn(senderAddress, receiverAddress string, assetID int, amount string, note []byte, algoSuggestedParams algoTypes.SuggestedParams) (txn algoTypes.Transaction, err error) {
+
+	amountBig := NewBigIntString(amount)
+
+	if assetID != 0 {
+		txn, err = future.MakeAssetTransferTxn(senderAddress, receiverAddress, amountBig.Uint64(), note, algoSuggestedParams, "", uint64(assetID))
+	} else {
+		txn, err = future.MakePaymentTxn(senderAddress, receiverAddress, amountBig.Uint64(), note, "", algoSuggestedParams)
+	}
+
+	return
+
+}
@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+const maxValidityWindow = 1000
+
+// not compatible with go-mobile
+func FromAlgoSuggestedParams(algoSuggestedParams algoTypes.SuggestedParams) *types.SuggestedParams {
+
+	return &types.SuggestedParams{
+		Fee:              int(algoSuggestedParams.Fee),
+		GenesisID:        algoSuggestedParams.GenesisID,
+		GenesisHash:      algoSuggestedParams.GenesisHash,
+		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
+		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
+		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
+		FlatFee:          algoSuggestedParams.FlatFee,
+		MinFee:           int(algoSuggestedParams.MinFee),
+	}
+
+}
+
+// ToAlgoSuggestedParams converts suggestedParams, or the cached options.SuggestedParams when it is nil,
+// and applies the validity window of options.
+// not compatible with go-mobile
+func ToAlgoSuggestedParams(suggestedParams *types.SuggestedParams, options *types.TxnOptions) (algoSuggestedParams algoTypes.SuggestedParams, err error) {
+
+	if suggestedParams == nil && options != nil {
+		suggestedParams = options.SuggestedParams
+	}
+
+	if suggestedParams == nil {
+		err = fmt.Errorf("suggested params are required")
+		return
+	}
+
+	algoSuggestedParams = algoTypes.SuggestedParams{
+		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
+		GenesisID:        suggestedParams.GenesisID,
+		GenesisHash:      suggestedParams.GenesisHash,
+		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
+		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
+		ConsensusVersion: suggestedParams.ConsensusVersion,
+		FlatFee:          suggestedParams.FlatFee,
+		MinFee:           uint64(suggestedParams.MinFee),
+	}
+
+	if options == nil || options.ValidityWindow == 0 {
+		return
+	}
+
+	if options.ValidityWindow < 0 || options.ValidityWindow > maxValidityWindow {
+		err = fmt.Errorf("validity window must be between 1 and %d rounds", maxValidityWindow)
+		return
+	}
+
+	algoSuggestedParams.LastRoundValid = algoSuggestedParams.FirstRoundValid + algoTypes.Round(options.ValidityWindow)
+
+	return
+
+}
+
+// ApplyTxnOptions sets the fee, note and lease of options on the transactions sent by sender.
+// When options has a fee payer, the Tinyman fee payment (the payment of sender with the note "fee") is sent by
+// the fee payer instead and carries the fees of all sender transactions, which then have a zero fee.
+// Groups without a fee payment get an extra zero amount payment from the fee payer to itself carrying the fees.
+// It must be called before the group ID is assigned.
+// not compatible with go-mobile
+func ApplyTxnOptions(transactions []algoTypes.Transaction, sender algoTypes.Address, options *types.TxnOptions) (result []algoTypes.Transaction, err error) {
+
+	return ApplyTxnOptionsWithInnerTransactions(transactions, sender, options, nil)
+
+}
+
+// ApplyTxnOptionsWithInnerTransactions is ApplyTxnOptions for groups with app calls that issue inner transactions.
+// innerTransactionCounts maps the index of such an app call to its number of inner transactions, the app call fee
+// is multiplied by one plus that number so it pays for the inner transactions through fee pooling.
+// not compatible with go-mobile
+func ApplyTxnOptionsWithInnerTransactions(transactions []algoTypes.Transaction, sender algoTypes.Address, options *types.TxnOptions, innerTransactionCounts map[int]int) (result []algoTypes.Transaction, err error) {
+
+	result = transactions
+
+	if options == nil {
+		options = &types.TxnOptions{}
+	}
+
+	if len(options.Lease) != 0 && len(options.Lease) != 32 {
+		err = fmt.Errorf("lease must be 32 bytes, got %d", len(options.Lease))
+		return
+	}
+
+	if options.FlatFee < 0 || options.FeeMultiplier < 0 {
+		err = fmt.Errorf("fee options can not be negative")
+		return
+	}
+
+	leaseSet := false
+
+	for i := range result {
+
+		txn := &result[i]
+
+		if txn.Sender != sender {
+			continue
+		}
+
+		if options.FlatFee > 0 {
+			txn.Fee = algoTypes.MicroAlgos(options.FlatFee)
+		} else if options.FeeMultiplier > 0 {
+			txn.Fee = algoTypes.MicroAlgos(math.Ceil(float64(txn.Fee) * options.FeeMultiplier))
+		}
+
+		if count := innerTransactionCounts[i]; count > 0 {
+			txn.Fee *= algoTypes.MicroAlgos(1 + count)
+		}
+
+		if len(options.Note) > 0 && len(txn.Note) == 0 {
+			txn.Note = options.Note
+		}
+
+		if len(options.Lease) > 0 && !leaseSet {
+			copy(txn.Lease[:], options.Lease)
+			leaseSet = true
+		}
+
+	}
+
+	if len(options.FeePayer) == 0 {
+		return
+	}
+
+	feePayer, err := algoTypes.DecodeAddress(options.FeePayer)
+	if err != nil {
+		return
+	}
+
+	if feePayer == sender {
+		return
+	}
+
+	return applyFeePayer(result, sender, feePayer)
+
+}
+
+func applyFeePayer(transactions []algoTypes.Transaction, sender, feePayer algoTypes.Address) (result []algoTypes.Transaction, err error) {
+
+	result = transactions
+
+	feePaymentIndex := -1
+	var baseTxn algoTypes.Transaction
+	var fees algoTypes.MicroAlgos
+	found := false
+
+	for i := range result {
+
+		txn := &result[i]
+
+		if txn.Sender != sender {
+			continue
+		}
+
+		if !found {
+			baseTxn = *txn
+			found = true
+		}
+
+		if feePaymentIndex < 0 && txn.Type == algoTypes.PaymentTx && string(txn.Note) == "fee" {
+			feePaymentIndex = i
+		}
+
+		fees += txn.Fee
+		txn.Fee = 0
+
+	}
+
+	if !found {
+		err = fmt.Errorf("group has no transaction sent by %s", sender.String())
+		return
+	}
+
+	if feePaymentIndex >= 0 {
+
+		feePayment := &result[feePaymentIndex]
+		feePayment.Sender = feePayer
+		feePayment.Fee = fees
+		return
+
+	}
+
+	if len(result) >= algoTypes.MaxTxGroupSize {
+		err = fmt.Errorf("group is full, can not add a fee payer transaction")
+		return
+	}
+
+	// the fee payer transaction pays the fee of the first sender transaction for itself
+	feePayment := algoTypes.Transaction{
+		Type: algoTypes.PaymentTx,
+		Header: algoTypes.Header{
+			Sender:      feePayer,
+			Fee:         fees + baseTxn.Fee,
+			FirstValid:  baseTxn.FirstValid,
+			LastValid:   baseTxn.LastValid,
+			GenesisID:   baseTxn.GenesisID,
+			GenesisHash: baseTxn.GenesisHash,
+		},
+		PaymentTxnFields: algoTypes.PaymentTxnFields{
+			Receiver: feePayer,
+		},
+	}
+
+	result = append(result, feePayment)
+
+	return
+
+}
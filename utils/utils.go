@@ -1,22 +1,25 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
 	b64 "encoding/base64"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"sort"
-	"strings"
 
 	"github.com/soheil555/tinyman-mobile-sdk/types"
 
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
 	"github.com/algorand/go-algorand-sdk/transaction"
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/algorand/go-codec/codec"
 )
 
 /*
@@ -33,42 +36,23 @@ func GetProgram(definition types.Logic, variables map[string]int) (templateBytes
 		return
 	}
 
-	offset := 0
 	var dVariables = definition.Variables
 
 	sort.SliceStable(dVariables, func(i, j int) bool {
 		return dVariables[i].Index < dVariables[j].Index
 	})
 
-	for _, v := range dVariables {
+	return GetProgramFromTemplate(templateBytes, dVariables, variables)
 
-		s := strings.Split(v.Name, "TMPL_")
-		name := strings.ToLower(s[len(s)-1])
-		value := variables[name]
-		start := v.Index - offset
-		end := start + v.Length
-
-		var valueEncoded []byte
-		valueEncoded, err = EncodeValue(value, v.Type)
-
-		if err != nil {
-			return
-		}
-
-		valueEncodedLen := len(valueEncoded)
-		diff := v.Length - valueEncodedLen
-		offset += diff
-
-		var tmp []byte
-		tmp = append(tmp, templateBytes[:start]...)
-		tmp = append(tmp, valueEncoded...)
-		tmp = append(tmp, templateBytes[end:]...)
-
-		templateBytes = tmp
+}
 
-	}
+// GetProgramFromTemplate is GetProgram for an already decoded template whose variables are sorted by index.
+// not compatible with go-mobile
+func GetProgramFromTemplate(template []byte, dVariables []types.Variable, variables map[string]int) (templateBytes []byte, err error) {
 
-	return
+	return fillTemplate(template, dVariables, func(v types.Variable) ([]byte, error) {
+		return EncodeValue(variables[templateVariableName(v)], v.Type)
+	})
 
 }
 
@@ -81,6 +65,13 @@ func EncodeValue(value int, valueType string) (buf []byte, err error) {
 
 	}
 
+	if valueType == "uint64" {
+
+		buf = IntToBytes(value)
+		return
+
+	}
+
 	err = fmt.Errorf("unsupported value type %s", valueType)
 	return
 
@@ -266,6 +257,55 @@ func NewTransactionGroup(transactions []algoTypes.Transaction) (transactionGroup
 
 }
 
+// NewTransactionGroupFromBytes decodes a group received from a third party.
+// encoded is the concatenation of msgpack encoded transactions, each one either signed or unsigned.
+// The group ID of the transactions is kept as is.
+func NewTransactionGroupFromBytes(encoded []byte) (transactionGroup *TransactionGroup, err error) {
+
+	decoder := msgpack.NewDecoder(bytes.NewReader(encoded))
+
+	var transactions []algoTypes.Transaction
+	var signedTransactions [][]byte
+
+	for {
+
+		var raw codec.Raw
+		err = decoder.Decode(&raw)
+		if err == io.EOF {
+			err = nil
+			break
+		}
+		if err != nil {
+			return
+		}
+
+		var stx algoTypes.SignedTxn
+		if msgpack.Decode(raw, &stx) == nil && stx.Txn.Type != "" {
+			transactions = append(transactions, stx.Txn)
+			signedTransactions = append(signedTransactions, []byte(raw))
+			continue
+		}
+
+		var txn algoTypes.Transaction
+		err = msgpack.Decode(raw, &txn)
+		if err != nil {
+			return
+		}
+
+		transactions = append(transactions, txn)
+		signedTransactions = append(signedTransactions, nil)
+
+	}
+
+	if len(transactions) == 0 {
+		err = fmt.Errorf("encoded transaction group is empty")
+		return
+	}
+
+	return &TransactionGroup{transactions, signedTransactions}, nil
+
+}
+
 // not compatible with go-mobile
 func (s *TransactionGroup) GetTransactions() []algoTypes.Transaction {
 	return s.transactions
@@ -276,14 +316,14 @@ func (s *TransactionGroup) GetSignedTransactions() [][]byte {
 	return s.signedTransactions
 }
 
-// TODO: what is user
-// type User interface {
-// 	SignTransactionGroup(transactionGroup *TransactionGroup)
-// }
+// Signer signs the transactions of a group that are sent by its accounts, e.g. a wallet calling SignWithPrivateKey.
+type Signer interface {
+	SignTransactionGroup(transactionGroup *TransactionGroup) error
+}
 
-// func (s *TransactionGroup) Sign(user User) {
-// 	user.SignTransactionGroup(s)
-// }
+func (s *TransactionGroup) Sign(signer Signer) error {
+	return signer.SignTransactionGroup(s)
+}
 
 func (s *TransactionGroup) GetSignedGroup() (signedGroup []byte) {
 
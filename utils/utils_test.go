@@ -86,6 +86,54 @@ func TestGetProgram(t *testing.T) {
 
 }
 
+func TestGetProgramWithValues(t *testing.T) {
+
+	template := append([]byte{6, 0, 128, 0, 49}, make([]byte, 32)...)
+	template = append(template, 67)
+
+	definition := types.Logic{
+		Bytecode: b64.StdEncoding.EncodeToString(template),
+		Variables: []types.Variable{
+			{Name: "TMPL_OWNER", Type: "address", Index: 5, Length: 32},
+			{Name: "TMPL_APP_ID", Type: "int", Index: 1, Length: 1},
+			{Name: "TMPL_NOTE", Type: "bytes", Index: 3, Length: 1},
+		},
+	}
+
+	owner := crypto.GenerateAccount().Address
+
+	values := map[string]interface{}{
+		"app_id": 123123,
+		"note":   "tinyman",
+		"owner":  owner.String(),
+	}
+
+	program, err := GetProgramWithValues(definition, values)
+	assert.Nil(t, err)
+
+	expected := []byte{6, 243, 193, 7, 128, 7}
+	expected = append(expected, []byte("tinyman")...)
+	expected = append(expected, 49)
+	expected = append(expected, owner[:]...)
+	expected = append(expected, 67)
+
+	assert.Equal(t, expected, program)
+
+	_, err = GetProgramWithValues(definition, map[string]interface{}{"app_id": 1, "note": "", "owner": "invalid"})
+	assert.NotNil(t, err)
+
+	extracted, err := ExtractVariables(definition, program)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"app_id": 123123, "note": []byte("tinyman"), "owner": owner.String()}, extracted)
+
+	// a program that differs from the template outside of its variables
+	program[len(program)-1] = 68
+
+	_, err = ExtractVariables(definition, program)
+	assert.NotNil(t, err)
+
+}
+
 func TestEncodeVarint(t *testing.T) {
 
 	var input int = 123123
@@ -108,6 +156,11 @@ func TestEncodeValue(t *testing.T) {
 
 	assert.Equal(t, expected, result)
 
+	result, err = EncodeValue(input, "uint64")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 1, 178, 7}, result)
+
 }
 
 func TestIntToBytes(t *testing.T) {
@@ -325,3 +378,225 @@ func TestNewBigFloatString(t *testing.T) {
 	assert.Zero(t, expected.Cmp(result))
 
 }
+
+type mockAssetFetcher struct{}
+
+func (s *mockAssetFetcher) FetchAsset(assetID int) (*types.Asset, error) {
+
+	if assetID == 0 {
+		return &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}, nil
+	}
+
+	return &types.Asset{Id: assetID, Name: "Test", UnitName: "TEST", Decimals: 2}, nil
+
+}
+
+func TestDescribe(t *testing.T) {
+
+	user := crypto.GenerateAccount()
+	pool := crypto.GenerateAccount()
+
+	genesisHash := "f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk="
+	genesisBytes, _ := b64.StdEncoding.DecodeString(genesisHash)
+
+	params := algoTypes.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisBytes,
+	}
+
+	txn1, err := future.MakePaymentTxn(user.Address.String(), pool.Address.String(), 2000, []byte("fee"), "", params)
+	assert.Nil(t, err)
+
+	txn2, err := future.MakeAssetTransferTxn(pool.Address.String(), user.Address.String(), 150, nil, params, "", 5)
+	assert.Nil(t, err)
+
+	txnGroup, err := NewTransactionGroup([]algoTypes.Transaction{txn1, txn2})
+	assert.Nil(t, err)
+
+	description, err := txnGroup.Describe(&mockAssetFetcher{}, user.Address.String())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, description.TransactionsLen())
+	assert.Equal(t, "tinyman fee payment", description.GetTransaction(0).Operation)
+	assert.Equal(t, "user", description.GetTransaction(0).Signer)
+	assert.Equal(t, "TEST", description.GetTransaction(1).AssetUnitName)
+
+	assert.Equal(t, 2, description.BalanceChangesLen())
+	assert.Equal(t, "-3000", description.GetBalanceChange(0).Amount)
+	assert.Equal(t, "150", description.GetBalanceChange(1).Amount)
+
+	assert.Contains(t, description.Text(), "1.50 TEST")
+
+}
+
+func TestTransactionPlanDescribe(t *testing.T) {
+
+	user := crypto.GenerateAccount()
+	pool := crypto.GenerateAccount()
+	integrator := crypto.GenerateAccount()
+
+	genesisBytes := make([]byte, 32)
+	params := algoTypes.SuggestedParams{Fee: 1000, FlatFee: true, FirstRoundValid: 1, LastRoundValid: 100, GenesisHash: genesisBytes}
+
+	swapTxn, err := future.MakePaymentTxn(user.Address.String(), pool.Address.String(), 10000, nil, "", params)
+	assert.Nil(t, err)
+
+	feeTxn, err := future.MakePaymentTxn(user.Address.String(), integrator.Address.String(), 100, []byte(types.INTEGRATOR_FEE_NOTE), "", params)
+	assert.Nil(t, err)
+
+	swapGroup, err := NewTransactionGroup([]algoTypes.Transaction{swapTxn})
+	assert.Nil(t, err)
+
+	feeGroup, err := NewTransactionGroup([]algoTypes.Transaction{feeTxn})
+	assert.Nil(t, err)
+
+	plan := NewTransactionPlan()
+	plan.AddStep("swap", "swap assets", swapGroup)
+	plan.AddNonAtomicStep("non-atomic-integrator-fee", "pay the integrator fee after the swap", feeGroup)
+
+	assert.False(t, plan.GetStep(0).NonAtomic)
+	assert.True(t, plan.GetStep(1).NonAtomic)
+
+	description, err := plan.Describe(&mockAssetFetcher{}, user.Address.String())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, description.StepsLen())
+	assert.False(t, description.GetStep(0).NonAtomic)
+	assert.True(t, description.GetStep(1).NonAtomic)
+	assert.Contains(t, description.GetStep(1).Group.GetTransaction(0).Summary, "not atomic with the swap")
+	assert.Contains(t, description.Text(), "Step 2: pay the integrator fee after the swap\nNot atomic with the previous steps")
+
+}
+
+func TestNewTransactionGroupFromBytes(t *testing.T) {
+
+	account := crypto.GenerateAccount()
+
+	txnGroup, err := NewTransactionGroup(mockTransactions())
+	assert.Nil(t, err)
+
+	transactions := txnGroup.GetTransactions()
+
+	_, stx, err := crypto.SignTransaction(account.PrivateKey, transactions[0])
+	assert.Nil(t, err)
+
+	encoded := append(stx, msgpack.Encode(transactions[1])...)
+
+	decoded, err := NewTransactionGroupFromBytes(encoded)
+	assert.Nil(t, err)
+
+	assert.Equal(t, transactions, decoded.GetTransactions())
+	assert.Equal(t, stx, decoded.GetSignedTransactions()[0])
+	assert.Nil(t, decoded.GetSignedTransactions()[1])
+
+	_, err = NewTransactionGroupFromBytes(nil)
+	assert.NotNil(t, err)
+
+}
+
+func TestTxnOptions(t *testing.T) {
+
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 10,
+		LastRoundValid:  1010,
+	}
+
+	options := &types.TxnOptions{
+		FeeMultiplier:   2,
+		ValidityWindow:  20,
+		Note:            []byte("app"),
+		Lease:           make([]byte, 32),
+		SuggestedParams: suggestedParams,
+	}
+	options.Lease[0] = 1
+
+	params, err := ToAlgoSuggestedParams(nil, options)
+	assert.Nil(t, err)
+	assert.Equal(t, algoTypes.Round(30), params.LastRoundValid)
+
+	_, err = ToAlgoSuggestedParams(nil, &types.TxnOptions{ValidityWindow: 20})
+	assert.NotNil(t, err)
+
+	transactions := mockTransactions()
+	transactions[1].Note = []byte("fee")
+	sender := transactions[0].Sender
+
+	transactions, err = ApplyTxnOptions(transactions, sender, options)
+	assert.Nil(t, err)
+
+	assert.Equal(t, algoTypes.MicroAlgos(2000), transactions[0].Fee)
+	assert.Equal(t, []byte("app"), transactions[0].Note)
+	assert.Equal(t, []byte("fee"), transactions[1].Note)
+	assert.Equal(t, byte(1), transactions[0].Lease[0])
+	assert.Equal(t, [32]byte{}, transactions[1].Lease)
+
+	_, err = ApplyTxnOptions(transactions, sender, &types.TxnOptions{Lease: []byte("short")})
+	assert.NotNil(t, err)
+
+	transactions = mockTransactions()
+	fee := transactions[0].Fee
+
+	transactions, err = ApplyTxnOptionsWithInnerTransactions(transactions, transactions[0].Sender, nil, map[int]int{0: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 3*fee, transactions[0].Fee)
+
+}
+
+func TestParsePaymentRequestURI(t *testing.T) {
+
+	account := crypto.GenerateAccount()
+
+	paymentRequest, err := ParsePaymentRequestURI(fmt.Sprintf("algorand://%s?amount=150500000&asset=45&note=hello&xnote=order-42", account.Address.String()))
+	assert.Nil(t, err)
+
+	assert.Equal(t, account.Address.String(), paymentRequest.Address)
+	assert.Equal(t, "150500000", paymentRequest.Amount)
+	assert.Equal(t, 45, paymentRequest.AssetId)
+	assert.Equal(t, "order-42", paymentRequest.GetNote())
+
+	paymentRequest, err = ParsePaymentRequestURI(fmt.Sprintf("algorand://%s?label=shop", account.Address.String()))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "0", paymentRequest.Amount)
+	assert.Equal(t, 0, paymentRequest.AssetId)
+	assert.Equal(t, "shop", paymentRequest.Label)
+
+	_, err = ParsePaymentRequestURI(fmt.Sprintf("algorand://%s?amount=1.5", account.Address.String()))
+	assert.NotNil(t, err)
+
+	_, err = ParsePaymentRequestURI("bitcoin://address")
+	assert.NotNil(t, err)
+
+}
+
+func TestLRUCache(t *testing.T) {
+
+	cache := NewLRUCache(2)
+
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	// "b" is the least recently used entry
+	cache.Add("c", 3)
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+
+	value, ok = cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+
+	cache.Purge()
+	assert.Equal(t, 0, cache.Len())
+
+}
@@ -7,10 +7,12 @@ import (
 
 	"github.com/soheil555/tinyman-mobile-sdk/types"
 	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
 
 	"github.com/algorand/go-algorand-sdk/crypto"
 	"github.com/algorand/go-algorand-sdk/future"
+	"github.com/algorand/go-algorand-sdk/transaction"
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
@@ -24,22 +26,49 @@ func hex2Int(hexStr string) uint64 {
 
 }
 
-func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1UnitName, asset2UnitName string, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+// GetPoolMinBalance is the minimum balance of a pool account once it is bootstrapped: it holds asset1ID, asset2ID
+// unless it is ALGO and its liquidity asset, and it is opted into the validator app validatorAppId.
+func GetPoolMinBalance(validatorAppId, asset2ID int, consensusVersion string) (minBalance int, err error) {
+
+	validatorApp, err := contracts.GetValidatorAppOf(validatorAppId)
+	if err != nil {
+		return
+	}
+
+	numAssets := 2
+	if asset2ID > 0 {
+		numAssets = 3
+	}
+
+	params := types.GetConsensusParams(consensusVersion)
+	minBalance = params.AccountMinBalance(numAssets, 1, 0, validatorApp.LocalStateSchema.NumUints, validatorApp.LocalStateSchema.NumByteSlices, 0)
+
+	return
+
+}
+
+// GetPoolFundingAmount is the amount the bootstrap pays to the pool account: its minimum balance and,
+// like the Tinyman reference SDK, one minimum fee for each of the groupSize transactions of the bootstrap.
+func GetPoolFundingAmount(poolMinBalance, groupSize, minFee int) int {
+
+	if minFee < transaction.MinTxnFee {
+		minFee = transaction.MinTxnFee
+	}
+
+	return poolMinBalance + groupSize*minFee
+
+}
+
+func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1UnitName, asset2UnitName string, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppId, asset1ID, asset2ID)
@@ -59,18 +88,6 @@ func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1
 		asset2UnitName = "ALGO"
 	}
 
-	var paymentTxnAmount uint64
-	if asset2ID > 0 {
-		paymentTxnAmount = 961000
-	} else {
-		paymentTxnAmount = 860000
-	}
-	paymentTxn, err := future.MakePaymentTxn(sender.String(), poolAddress.String(), paymentTxnAmount, []byte("fee"), "", algoSuggestedParams)
-
-	if err != nil {
-		return
-	}
-
 	var foreignAssets []uint64
 
 	if asset2ID == 0 {
@@ -85,7 +102,7 @@ func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1
 		return
 	}
 
-	assetCreateTxn, err := future.MakeAssetCreateTxn(poolAddress.String(), nil, algoSuggestedParams, hex2Int("0xFFFFFFFFFFFFFFFF"), 6, false, "", "", "", "", "TMPOOL11", fmt.Sprintf("TinymanPool1.1 {%s}-{%s}", asset1UnitName, asset2UnitName), "https://tinyman.org", "")
+	assetCreateTxn, err := future.MakeAssetCreateTxn(poolAddress.String(), nil, algoSuggestedParams, hex2Int("0xFFFFFFFFFFFFFFFF"), 6, false, "", "", "", "", constants.LIQUIDITY_ASSET_UNIT_NAME_V1_1, fmt.Sprintf("TinymanPool1.1 {%s}-{%s}", asset1UnitName, asset2UnitName), "https://tinyman.org", "")
 
 	if err != nil {
 		return
@@ -96,7 +113,7 @@ func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1
 		return
 	}
 
-	txns := []algoTypes.Transaction{paymentTxn, applicationOptInTxn, assetCreateTxn, assetOptInTxn1}
+	poolTxns := []algoTypes.Transaction{applicationOptInTxn, assetCreateTxn, assetOptInTxn1}
 
 	if asset2ID > 0 {
 
@@ -106,8 +123,28 @@ func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, asset1
 			return
 		}
 
-		txns = append(txns, assetOptInTxn2)
+		poolTxns = append(poolTxns, assetOptInTxn2)
+
+	}
+
+	poolMinBalance, err := GetPoolMinBalance(validatorAppId, asset2ID, algoSuggestedParams.ConsensusVersion)
+	if err != nil {
+		return
+	}
+
+	paymentTxnAmount := GetPoolFundingAmount(poolMinBalance, len(poolTxns)+1, int(algoSuggestedParams.MinFee))
 
+	paymentTxn, err := future.MakePaymentTxn(sender.String(), poolAddress.String(), uint64(paymentTxnAmount), []byte("fee"), "", algoSuggestedParams)
+
+	if err != nil {
+		return
+	}
+
+	txns := append([]algoTypes.Transaction{paymentTxn}, poolTxns...)
+
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
 	}
 
 	txnGroup, err = utils.NewTransactionGroup(txns)
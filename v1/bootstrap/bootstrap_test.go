@@ -14,3 +14,19 @@ func TestHex2Int(t *testing.T) {
 	assert.Equal(t, expected, actual)
 
 }
+
+func TestGetPoolFundingAmount(t *testing.T) {
+
+	// the pool of an asset and ALGO, with a bootstrap group of 4 transactions
+	minBalance, err := GetPoolMinBalance(1, 0, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 856000, minBalance)
+	assert.Equal(t, 860000, GetPoolFundingAmount(minBalance, 4, 0))
+
+	// the pool of two assets, with a bootstrap group of 5 transactions
+	minBalance, err = GetPoolMinBalance(1, 1, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 956000, minBalance)
+	assert.Equal(t, 961000, GetPoolFundingAmount(minBalance, 5, 1000))
+
+}
@@ -10,22 +10,16 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareBurnTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, asset1Amount, asset2Amount, liquidityAssetAmount string, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareBurnTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, asset1Amount, asset2Amount, liquidityAssetAmount string, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppId, asset1ID, asset2ID)
@@ -86,6 +80,11 @@ func PrepareBurnTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 
 	txns := []algoTypes.Transaction{paymentTxn, applicationNoOpTxn, assetTransferTxn1, assetTransferTxn2, assetTransferTxn3}
 
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(txns)
 
 	if err != nil {
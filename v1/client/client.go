@@ -2,9 +2,8 @@ package client
 
 import (
 	"context"
-	b64 "encoding/base64"
-	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"reflect"
 
@@ -12,11 +11,13 @@ import (
 	"github.com/soheil555/tinyman-mobile-sdk/utils"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/optin"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
 
 	"github.com/algorand/go-algorand-sdk/client/v2/algod"
 	"github.com/algorand/go-algorand-sdk/client/v2/common"
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
 	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
+	"github.com/algorand/go-algorand-sdk/crypto"
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
@@ -25,7 +26,8 @@ type TinymanClient struct {
 	indexer        *indexer.Client
 	ValidatorAppId int `json:"validator-app-id"`
 	assetsCache    map[int]*types.Asset
-	UserAddress    string `json:"user-address"`
+	UserAddress    string               `json:"user-address"`
+	IntegratorFee  *types.IntegratorFee `json:"integrator-fee"`
 }
 
 func NewTinymanClient(algodClientURL, indexerClientURL string, validatorAppId int, userAddress string) (tinymanClient *TinymanClient, err error) {
@@ -58,9 +60,28 @@ func NewTinymanClient(algodClientURL, indexerClientURL string, validatorAppId in
 		validatorAppId,
 		map[int]*types.Asset{},
 		user.String(),
+		nil,
 	}, nil
 }
 
+// SetIntegratorFee makes every swap quote of the client charge basisPoints of the input or output asset,
+// paid to address after the swap.
+func (s *TinymanClient) SetIntegratorFee(address string, basisPoints int, side string) (err error) {
+
+	integratorFee, err := types.NewIntegratorFee(address, basisPoints, side)
+	if err != nil {
+		return
+	}
+
+	s.IntegratorFee = integratorFee
+	return
+
+}
+
+func (s *TinymanClient) RemoveIntegratorFee() {
+	s.IntegratorFee = nil
+}
+
 func NewTinymanTestnetClient(algodClientURL, indexerClientURL, userAddress string) (tinymanClient *TinymanClient, err error) {
 
 	return NewTinymanClient(algodClientURL, indexerClientURL, constants.TESTNET_VALIDATOR_APP_ID, userAddress)
@@ -100,6 +121,146 @@ func (s *TinymanClient) LookupAccountByID(address string) (validRound uint64, re
 
 }
 
+// FetchAssetCreator returns the address of the account that created assetID, e.g. the pool of a liquidity asset.
+func (s *TinymanClient) FetchAssetCreator(assetID int) (creatorAddress string, err error) {
+
+	_, asset, err := s.indexer.LookupAssetByID(uint64(assetID)).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	creatorAddress = asset.Params.Creator
+	return
+
+}
+
+// FetchAppCreator returns the address of the account that created appID, e.g. the protocol fee recipient of a validator app.
+func (s *TinymanClient) FetchAppCreator(appID int) (creatorAddress string, err error) {
+
+	app, err := s.indexer.LookupApplicationByID(uint64(appID)).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	creatorAddress = app.Application.Params.Creator
+	return
+
+}
+
+// FetchPoolLogicsig returns the logicsig program of a pool account from the last transaction the pool signed with it,
+// e.g. for pools whose contract definitions are not bundled. The program must hash to poolAddress.
+func (s *TinymanClient) FetchPoolLogicsig(poolAddress string) (program []byte, err error) {
+
+	response, err := s.indexer.SearchForTransactions().AddressString(poolAddress).AddressRole("sender").SigType("lsig").Limit(1).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	if len(response.Transactions) == 0 {
+		err = fmt.Errorf("pool %s has not signed a transaction with its logicsig", poolAddress)
+		return
+	}
+
+	program = response.Transactions[0].Signature.Logicsig.Logic
+
+	if crypto.AddressFromProgram(program).String() != poolAddress {
+		err = fmt.Errorf("logicsig of the transactions of pool %s does not match its address", poolAddress)
+		program = nil
+	}
+
+	return
+
+}
+
+// FetchValidatorLocalStateSchema returns the local state schema of the validator app appID.
+func (s *TinymanClient) FetchValidatorLocalStateSchema(appID int) (schema *types.LocalStateSchema, err error) {
+
+	app, err := s.algod.GetApplicationByID(uint64(appID)).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	schema = &types.LocalStateSchema{
+		NumUints:      int(app.Params.LocalStateSchema.NumUint),
+		NumByteSlices: int(app.Params.LocalStateSchema.NumByteSlice),
+	}
+
+	return
+
+}
+
+// SearchAppAccounts returns every account opted in to appID, following the indexer pagination.
+// not compatible with go-mobile
+func (s *TinymanClient) SearchAppAccounts(appID int) (accounts []models.Account, err error) {
+
+	var next string
+
+	for {
+
+		search := s.indexer.SearchAccounts().ApplicationId(uint64(appID))
+		if len(next) > 0 {
+			search = search.NextToken(next)
+		}
+
+		var response models.AccountsResponse
+		response, err = search.Do(context.Background())
+		if err != nil {
+			return
+		}
+
+		accounts = append(accounts, response.Accounts...)
+
+		if len(response.NextToken) == 0 || len(response.Accounts) == 0 {
+			return
+		}
+
+		next = response.NextToken
+
+	}
+
+}
+
+// SearchAccountTransactions returns the transactions of type txType sent by address that involve appID or assetID,
+// following the indexer pagination. A zero appID or assetID and an empty txType are not filtered on.
+// not compatible with go-mobile
+func (s *TinymanClient) SearchAccountTransactions(address, txType string, appID, assetID int) (transactions []models.Transaction, err error) {
+
+	var next string
+
+	for {
+
+		search := s.indexer.SearchForTransactions().AddressString(address).AddressRole("sender")
+		if len(txType) > 0 {
+			search = search.TxType(txType)
+		}
+		if appID != 0 {
+			search = search.ApplicationId(uint64(appID))
+		}
+		if assetID != 0 {
+			search = search.AssetID(uint64(assetID))
+		}
+		if len(next) > 0 {
+			search = search.NextToken(next)
+		}
+
+		var response models.TransactionsResponse
+		response, err = search.Do(context.Background())
+		if err != nil {
+			return
+		}
+
+		transactions = append(transactions, response.Transactions...)
+
+		if len(response.NextToken) == 0 || len(response.Transactions) == 0 {
+			return
+		}
+
+		next = response.NextToken
+
+	}
+
+}
+
 // not compatible with go-mobile
 func (s *TinymanClient) AccountInformation(address string) (response models.Account, err error) {
 	return s.algod.AccountInformation(address).Do(context.Background())
@@ -110,6 +271,24 @@ func (s *TinymanClient) SuggestedParams() (params algoTypes.SuggestedParams, err
 	return s.algod.SuggestedParams().Do(context.Background())
 }
 
+// GetSuggestedParams returns the cached suggested params of options, or fetches them from algod.
+func (s *TinymanClient) GetSuggestedParams(options *types.TxnOptions) (suggestedParams *types.SuggestedParams, err error) {
+
+	if options != nil && options.SuggestedParams != nil {
+		suggestedParams = options.SuggestedParams
+		return
+	}
+
+	algoSuggestedParams, err := s.SuggestedParams()
+	if err != nil {
+		return
+	}
+
+	suggestedParams = utils.FromAlgoSuggestedParams(algoSuggestedParams)
+	return
+
+}
+
 func (s *TinymanClient) Submit(transactionGroup *utils.TransactionGroup, wait bool) (transactionInformation *types.TransactionInformation, err error) {
 
 	signedGroup := transactionGroup.GetSignedGroup()
@@ -132,7 +311,52 @@ func (s *TinymanClient) Submit(transactionGroup *utils.TransactionGroup, wait bo
 
 }
 
-func (s *TinymanClient) PrepareAppOptinTransactions(userAddress string) (txnGroup *utils.TransactionGroup, err error) {
+// FetchCreatedAppId returns the ID of the app created by the confirmed transaction txID,
+// e.g. the validator app of deploy.PrepareDeployValidatorTransactions.
+func (s *TinymanClient) FetchCreatedAppId(txID string) (appId int, err error) {
+
+	response, _, err := s.algod.PendingTransactionInformation(txID).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	if response.ApplicationIndex == 0 {
+		err = fmt.Errorf("transaction %s did not create an app", txID)
+		return
+	}
+
+	appId = int(response.ApplicationIndex)
+
+	return
+
+}
+
+// LookupTransaction returns a confirmed transaction from the indexer, including its result,
+// e.g. the local state deltas of an app call.
+// not compatible with go-mobile
+func (s *TinymanClient) LookupTransaction(txID string) (transaction models.Transaction, err error) {
+
+	response, err := s.indexer.LookupTransaction(txID).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	transaction = response.Transaction
+	return
+
+}
+
+// PendingTransactionInformation returns the result of a transaction known to algod, e.g. the local state deltas of
+// a confirmed app call.
+// not compatible with go-mobile
+func (s *TinymanClient) PendingTransactionInformation(txID string) (response models.PendingTransactionInfoResponse, err error) {
+
+	response, _, err = s.algod.PendingTransactionInformation(txID).Do(context.Background())
+	return
+
+}
+
+func (s *TinymanClient) PrepareAppOptinTransactions(userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	if len(userAddress) == 0 {
 		userAddress = s.UserAddress
@@ -143,29 +367,18 @@ func (s *TinymanClient) PrepareAppOptinTransactions(userAddress string) (txnGrou
 		return
 	}
 
-	algoSuggestedParams, err := s.algod.SuggestedParams().Do(context.Background())
+	suggestedParams, err := s.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
-	txnGroup, err = optin.PrepareAppOptinTransactions(s.ValidatorAppId, user.String(), suggestedParams)
+	txnGroup, err = optin.PrepareAppOptinTransactions(s.ValidatorAppId, user.String(), suggestedParams, options)
 
 	return
 
 }
 
-func (s *TinymanClient) PrepareAssetOptinTransactions(assetID int, userAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *TinymanClient) PrepareAssetOptinTransactions(assetID int, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	if len(userAddress) == 0 {
 		userAddress = s.UserAddress
@@ -176,23 +389,12 @@ func (s *TinymanClient) PrepareAssetOptinTransactions(assetID int, userAddress s
 		return
 	}
 
-	algoSuggestedParams, err := s.algod.SuggestedParams().Do(context.Background())
+	suggestedParams, err := s.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
-	txnGroup, err = optin.PrepareAssetOptinTransactions(assetID, user.String(), suggestedParams)
+	txnGroup, err = optin.PrepareAssetOptinTransactions(assetID, user.String(), suggestedParams, options)
 
 	return
 
@@ -200,6 +402,13 @@ func (s *TinymanClient) PrepareAssetOptinTransactions(assetID int, userAddress s
 
 func (s *TinymanClient) FetchExcessAmounts(userAddress string) (excessAmountsStr string, err error) {
 
+	return s.FetchValidatorExcessAmounts(userAddress, s.ValidatorAppId)
+
+}
+
+// FetchValidatorExcessAmounts is FetchExcessAmounts for the local state of another validator app, e.g. an older version.
+func (s *TinymanClient) FetchValidatorExcessAmounts(userAddress string, validatorAppId int) (excessAmountsStr string, err error) {
+
 	pools := make(map[string]map[int]string)
 
 	if len(userAddress) == 0 {
@@ -220,7 +429,7 @@ func (s *TinymanClient) FetchExcessAmounts(userAddress string) (excessAmountsStr
 
 	for _, a := range accountInfo.AppsLocalState {
 
-		if a.Id == uint64(s.ValidatorAppId) {
+		if a.Id == uint64(validatorAppId) {
 			validatorApp = a
 		}
 
@@ -230,49 +439,24 @@ func (s *TinymanClient) FetchExcessAmounts(userAddress string) (excessAmountsStr
 		return
 	}
 
-	validatorAppState := make(map[string]models.TealValue)
-
-	for _, x := range validatorApp.KeyValue {
-
-		validatorAppState[x.Key] = x.Value
-
+	userState, err := state.DecodeUserState(validatorApp.KeyValue)
+	if err != nil {
+		return
 	}
 
-	for key := range validatorAppState {
-		var b []byte
-		b, err = b64.StdEncoding.DecodeString(key)
-		if err != nil {
-			return
-		}
-
-		bLen := len(b)
-
-		if bLen >= 9 && b[bLen-9] == byte('e') {
+	for poolAddress, amounts := range userState.ExcessAmounts {
 
-			value := big.NewInt(int64(validatorAppState[key].Uint))
-			var poolAddress string
-			poolAddress, err = algoTypes.EncodeAddress(b[:bLen-9])
-
-			if err != nil {
-				return
-			}
+		pools[poolAddress] = make(map[int]string)
 
-			if pool, ok := pools[poolAddress]; ok {
-				pools[poolAddress] = pool
-			}
+		for assetID, amount := range amounts {
 
-			assetID := binary.BigEndian.Uint64(b[bLen-8:])
 			var asset *types.Asset
-			asset, err = s.FetchAsset(int(assetID))
+			asset, err = s.FetchAsset(assetID)
 			if err != nil {
 				return
 			}
 
-			if pools[poolAddress] == nil {
-				pools[poolAddress] = make(map[int]string)
-			}
-
-			pools[poolAddress][asset.Id] = value.String()
+			pools[poolAddress][asset.Id] = new(big.Int).SetUint64(amount).String()
 
 		}
 
@@ -0,0 +1,294 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+const (
+	PROBLEM_INSUFFICIENT_ALGO  = "insufficient-algo"
+	PROBLEM_INSUFFICIENT_ASSET = "insufficient-asset"
+	PROBLEM_ASSET_NOT_OPTED_IN = "asset-not-opted-in"
+	PROBLEM_APP_NOT_OPTED_IN   = "app-not-opted-in"
+	PROBLEM_ASSET_FROZEN       = "asset-frozen"
+)
+
+// PreflightProblem is a reason for the group to be rejected on chain.
+// Required and Available are set for the insufficient balance problems.
+type PreflightProblem struct {
+	Type      string `json:"type"`
+	Index     int    `json:"index"`
+	AssetId   int    `json:"asset-id"`
+	AppId     int    `json:"app-id,omitempty"`
+	Required  string `json:"required,omitempty"`
+	Available string `json:"available,omitempty"`
+	Message   string `json:"message"`
+}
+
+type PreflightResult struct {
+	Address         string              `json:"address"`
+	Balance         string              `json:"balance"`
+	MinBalance      string              `json:"min-balance"`
+	MinBalanceAfter string              `json:"min-balance-after"`
+	Problems        []*PreflightProblem `json:"problems"`
+}
+
+func (s *PreflightResult) Ok() bool {
+	return len(s.Problems) == 0
+}
+
+func (s *PreflightResult) ProblemsLen() int {
+	return len(s.Problems)
+}
+
+func (s *PreflightResult) GetProblem(index int) *PreflightProblem {
+	return s.Problems[index]
+}
+
+func (s *PreflightResult) JSON() (resultStr string, err error) {
+
+	resultBytes, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	resultStr = string(resultBytes)
+	return
+
+}
+
+// Preflight fetches the account of userAddress once and simulates the balance changes of the group for it,
+// including transaction fees and the minimum balance increase of new asset and validator app opt-ins.
+// The minimum balance is computed from the consensus parameters of the current protocol version.
+func (s *TinymanClient) Preflight(transactionGroup *utils.TransactionGroup, userAddress string) (result *PreflightResult, err error) {
+
+	if len(userAddress) == 0 {
+		userAddress = s.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.SuggestedParams()
+	if err != nil {
+		return
+	}
+
+	account, err := s.AccountInformation(user.String())
+	if err != nil {
+		return
+	}
+
+	validatorApp, err := contracts.GetValidatorAppOf(s.ValidatorAppId)
+	if err != nil {
+		return
+	}
+
+	params := types.GetConsensusParams(suggestedParams.ConsensusVersion)
+	validatorOptinMinBalance := params.AppOptinMinBalance(validatorApp.LocalStateSchema.NumUints, validatorApp.LocalStateSchema.NumByteSlices)
+
+	result = preflight(transactionGroup.GetTransactions(), user, account, params, s.ValidatorAppId, validatorOptinMinBalance)
+	return
+
+}
+
+type preflightHolding struct {
+	amount *big.Int
+	frozen bool
+}
+
+func accountMinBalance(account models.Account, params *types.ConsensusParams) int {
+
+	numAssets := int(account.TotalAssetsOptedIn)
+	if len(account.Assets) > numAssets {
+		numAssets = len(account.Assets)
+	}
+
+	numAppsOptedIn := int(account.TotalAppsOptedIn)
+	if len(account.AppsLocalState) > numAppsOptedIn {
+		numAppsOptedIn = len(account.AppsLocalState)
+	}
+
+	numCreatedApps := int(account.TotalCreatedApps)
+	if len(account.CreatedApps) > numCreatedApps {
+		numCreatedApps = len(account.CreatedApps)
+	}
+
+	return params.AccountMinBalance(
+		numAssets,
+		numAppsOptedIn,
+		numCreatedApps,
+		int(account.AppsTotalSchema.NumUint),
+		int(account.AppsTotalSchema.NumByteSlice),
+		int(account.AppsTotalExtraPages),
+	)
+
+}
+
+// preflight simulates the transactions in order from the point of view of user.
+// Only the minimum balance of the validator app opt-in is known, opt-ins to other apps are not accounted for.
+func preflight(transactions []algoTypes.Transaction, user algoTypes.Address, account models.Account, params *types.ConsensusParams, validatorAppId int, validatorOptinMinBalance int) (result *PreflightResult) {
+
+	minBalance := accountMinBalance(account, params)
+
+	result = &PreflightResult{
+		Address:    user.String(),
+		Balance:    new(big.Int).SetUint64(account.Amount).String(),
+		MinBalance: fmt.Sprint(minBalance),
+	}
+
+	holdings := make(map[int]*preflightHolding)
+	for _, a := range account.Assets {
+		holdings[int(a.AssetId)] = &preflightHolding{new(big.Int).SetUint64(a.Amount), a.IsFrozen}
+	}
+
+	appsOptedIn := make(map[int]bool)
+	for _, a := range account.AppsLocalState {
+		appsOptedIn[int(a.Id)] = true
+	}
+
+	balance := new(big.Int).SetUint64(account.Amount)
+	shortfall := new(big.Int)
+	shortfallIndex := -1
+
+	addProblem := func(problem *PreflightProblem) {
+		result.Problems = append(result.Problems, problem)
+	}
+
+	for i, txn := range transactions {
+
+		fromUser := txn.Sender == user
+
+		if fromUser {
+			balance.Sub(balance, new(big.Int).SetUint64(uint64(txn.Fee)))
+		}
+
+		switch txn.Type {
+
+		case algoTypes.PaymentTx:
+
+			amount := new(big.Int).SetUint64(uint64(txn.Amount))
+
+			if fromUser {
+				balance.Sub(balance, amount)
+			}
+
+			if txn.Receiver == user {
+				balance.Add(balance, amount)
+			}
+
+		case algoTypes.AssetTransferTx:
+
+			assetID := int(txn.XferAsset)
+			amount := new(big.Int).SetUint64(txn.AssetAmount)
+			holding := holdings[assetID]
+
+			if fromUser && txn.AssetReceiver == user && txn.AssetAmount == 0 {
+				if holding == nil {
+					holdings[assetID] = &preflightHolding{new(big.Int), false}
+					minBalance += params.AssetMinBalance()
+				}
+				break
+			}
+
+			if fromUser {
+
+				if holding == nil {
+					addProblem(&PreflightProblem{Type: PROBLEM_ASSET_NOT_OPTED_IN, Index: i, AssetId: assetID, Message: fmt.Sprintf("account is not opted into asset %d", assetID)})
+				} else if holding.frozen {
+					addProblem(&PreflightProblem{Type: PROBLEM_ASSET_FROZEN, Index: i, AssetId: assetID, Message: fmt.Sprintf("asset %d is frozen for the account", assetID)})
+				} else if holding.amount.Cmp(amount) < 0 {
+					addProblem(&PreflightProblem{Type: PROBLEM_INSUFFICIENT_ASSET, Index: i, AssetId: assetID, Required: amount.String(), Available: holding.amount.String(), Message: fmt.Sprintf("insufficient balance of asset %d", assetID)})
+				} else {
+					holding.amount.Sub(holding.amount, amount)
+				}
+
+			}
+
+			if txn.AssetReceiver == user {
+
+				if holding == nil {
+					addProblem(&PreflightProblem{Type: PROBLEM_ASSET_NOT_OPTED_IN, Index: i, AssetId: assetID, Message: fmt.Sprintf("account is not opted into asset %d to receive it", assetID)})
+				} else if holding.frozen {
+					addProblem(&PreflightProblem{Type: PROBLEM_ASSET_FROZEN, Index: i, AssetId: assetID, Message: fmt.Sprintf("asset %d is frozen for the account", assetID)})
+				} else {
+					holding.amount.Add(holding.amount, amount)
+				}
+
+			}
+
+		case algoTypes.ApplicationCallTx:
+
+			appID := int(txn.ApplicationID)
+
+			if fromUser && txn.OnCompletion == algoTypes.OptInOC {
+				if !appsOptedIn[appID] {
+					appsOptedIn[appID] = true
+					if appID == validatorAppId {
+						minBalance += validatorOptinMinBalance
+					}
+				}
+				break
+			}
+
+			if appID != validatorAppId || appsOptedIn[appID] {
+				break
+			}
+
+			references := fromUser
+			for _, a := range txn.Accounts {
+				if a == user {
+					references = true
+				}
+			}
+
+			if references && txn.OnCompletion == algoTypes.NoOpOC {
+				addProblem(&PreflightProblem{Type: PROBLEM_APP_NOT_OPTED_IN, Index: i, AppId: appID, Message: fmt.Sprintf("account is not opted into app %d", appID)})
+				appsOptedIn[appID] = true
+			}
+
+		}
+
+		if fromUser {
+
+			missing := new(big.Int).Sub(big.NewInt(int64(minBalance)), balance)
+			if missing.Cmp(shortfall) > 0 {
+				shortfall = missing
+				if shortfallIndex < 0 {
+					shortfallIndex = i
+				}
+			}
+
+		}
+
+	}
+
+	result.MinBalanceAfter = fmt.Sprint(minBalance)
+
+	if shortfall.Sign() > 0 {
+
+		required := new(big.Int).Add(new(big.Int).SetUint64(account.Amount), shortfall)
+
+		addProblem(&PreflightProblem{
+			Type:      PROBLEM_INSUFFICIENT_ALGO,
+			Index:     shortfallIndex,
+			Required:  required.String(),
+			Available: result.Balance,
+			Message:   fmt.Sprintf("account needs %s more microAlgos to cover amounts, fees and minimum balance", shortfall.String()),
+		})
+
+	}
+
+	return
+
+}
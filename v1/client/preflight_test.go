@@ -0,0 +1,76 @@
+package client
+
+import (
+	b64 "encoding/base64"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/swap"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreflight(t *testing.T) {
+
+	user := crypto.GenerateAccount()
+
+	genesisHash, _ := b64.StdEncoding.DecodeString("f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk=")
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisHash,
+	}
+
+	// swap 10000 microAlgos for asset 2
+	txnGroup, err := swap.PrepareSwapTransactions(1, 2, 0, 3, 0, "10000", "990", "fixed-input", user.Address.String(), suggestedParams, nil)
+	assert.Nil(t, err)
+
+	params := types.GetConsensusParams("")
+	validatorOptinMinBalance := params.AppOptinMinBalance(16, 0)
+
+	account := models.Account{
+		Address:        user.Address.String(),
+		Amount:         1000000,
+		Assets:         []models.AssetHolding{{AssetId: 2, Amount: 5}},
+		AppsLocalState: []models.ApplicationLocalState{{Id: 1}},
+		AppsTotalSchema: models.ApplicationStateSchema{
+			NumUint: 16,
+		},
+	}
+
+	result := preflight(txnGroup.GetTransactions(), user.Address, account, params, 1, validatorOptinMinBalance)
+	assert.True(t, result.Ok())
+	assert.Equal(t, "756000", result.MinBalance)
+
+	// not opted into the validator app and asset 2, and short on ALGO
+	account = models.Account{
+		Address: user.Address.String(),
+		Amount:  100000,
+	}
+
+	result = preflight(txnGroup.GetTransactions(), user.Address, account, params, 1, validatorOptinMinBalance)
+	assert.False(t, result.Ok())
+	assert.Equal(t, 3, result.ProblemsLen())
+	assert.Equal(t, PROBLEM_APP_NOT_OPTED_IN, result.GetProblem(0).Type)
+	assert.Equal(t, PROBLEM_ASSET_NOT_OPTED_IN, result.GetProblem(1).Type)
+	assert.Equal(t, 2, result.GetProblem(1).AssetId)
+	assert.Equal(t, PROBLEM_INSUFFICIENT_ALGO, result.GetProblem(2).Type)
+	assert.Equal(t, "114000", result.GetProblem(2).Required)
+
+	// frozen asset
+	account = models.Account{
+		Address:        user.Address.String(),
+		Amount:         1000000,
+		Assets:         []models.AssetHolding{{AssetId: 2, IsFrozen: true}},
+		AppsLocalState: []models.ApplicationLocalState{{Id: 1}},
+	}
+
+	result = preflight(txnGroup.GetTransactions(), user.Address, account, params, 1, validatorOptinMinBalance)
+	assert.Equal(t, 1, result.ProblemsLen())
+	assert.Equal(t, PROBLEM_ASSET_FROZEN, result.GetProblem(0).Type)
+
+}
@@ -15,4 +15,10 @@ const (
 
 	TESTNET_VALIDATOR_APP_ID = TESTNET_VALIDATOR_APP_ID_V1_1
 	MAINNET_VALIDATOR_APP_ID = MAINNET_VALIDATOR_APP_ID_V1_1
+
+	LIQUIDITY_ASSET_UNIT_NAME_V1_0 = "TM1POOL"
+	LIQUIDITY_ASSET_UNIT_NAME_V1_1 = "TMPOOL11"
+
+	// LOCKED_LIQUIDITY is the liquidity the first mint of a pool issues but does not give out, it can never be burnt.
+	LOCKED_LIQUIDITY = 1000
 )
@@ -1,12 +1,17 @@
 package contracts
 
 import (
+	"bytes"
 	"embed"
+	b64 "encoding/base64"
 	"encoding/json"
+	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/soheil555/tinyman-mobile-sdk/types"
 	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
 
 	"github.com/algorand/go-algorand-sdk/crypto"
 )
@@ -14,9 +19,34 @@ import (
 //go:embed asc.json
 var f embed.FS
 
+// bundledFiles are the embedded asc.json files by validator version, see validatorVersion.
+// The v1.0 definitions are not embedded, v1.0 pools are registered one by one with RegisterPoolLogicsig
+// or all at once with RegisterContracts.
+var bundledFiles = map[string]string{
+	"v1.1": "asc.json",
+}
+
+// validatorVersion returns the version of the contracts of validatorAppId, any validator that is not a
+// known v1.0 validator is a v1.1 validator.
+func validatorVersion(validatorAppId int) string {
+
+	if isValidatorV1_0(validatorAppId) {
+		return "v1.0"
+	}
+
+	return "v1.1"
+
+}
+
 func readContractsFile() (data types.ASC, err error) {
 
-	file, err := f.ReadFile("asc.json")
+	return readBundledFile(bundledFiles["v1.1"])
+
+}
+
+func readBundledFile(name string) (data types.ASC, err error) {
+
+	file, err := f.ReadFile(name)
 
 	if err != nil {
 		return
@@ -28,22 +58,148 @@ func readContractsFile() (data types.ASC, err error) {
 
 }
 
-func GetPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (lsig *types.LogicSig, err error) {
+// compiledContracts are contract definitions with the pool logicsig template decoded and its variables sorted,
+// ready to be filled in for any pool.
+type compiledContracts struct {
+	asc       types.ASC
+	template  []byte
+	variables []types.Variable
+}
+
+func compileContracts(data types.ASC) (compiled *compiledContracts, err error) {
 
-	contracts, err := readContractsFile()
+	poolLogicsigDef := data.Contracts.PoolLogicsig.Logic
 
+	template, err := b64.StdEncoding.DecodeString(poolLogicsigDef.Bytecode)
 	if err != nil {
 		return
 	}
 
-	poolLogicsigDef := contracts.Contracts.PoolLogicsig.Logic
-	// validatorAppDef := contracts.Contracts.ValidatorApp
+	variables := append([]types.Variable{}, poolLogicsigDef.Variables...)
+	sort.SliceStable(variables, func(i, j int) bool {
+		return variables[i].Index < variables[j].Index
+	})
 
-	assets := []int{asset1ID, asset2ID}
-	sort.Slice(assets, func(i, j int) bool { return assets[i] < assets[j] })
+	compiled = &compiledContracts{
+		asc:       data,
+		template:  template,
+		variables: variables,
+	}
+
+	return
 
-	assetID1 := assets[1]
-	assetID2 := assets[0]
+}
+
+var (
+	bundledContracts      = make(map[string]*compiledContracts)
+	bundledContractsMutex sync.Mutex
+)
+
+// readBundledContracts returns the bundled contract definitions of version, each asc.json is only parsed once.
+func readBundledContracts(version string) (compiled *compiledContracts, err error) {
+
+	name, ok := bundledFiles[version]
+	if !ok {
+		err = fmt.Errorf("contract definitions %s are not bundled", version)
+		return
+	}
+
+	bundledContractsMutex.Lock()
+	defer bundledContractsMutex.Unlock()
+
+	compiled, ok = bundledContracts[version]
+	if ok {
+		return
+	}
+
+	data, err := readBundledFile(name)
+	if err != nil {
+		return
+	}
+
+	compiled, err = compileContracts(data)
+	if err != nil {
+		return
+	}
+
+	bundledContracts[version] = compiled
+
+	return
+
+}
+
+// POOL_LOGICSIG_CACHE_SIZE is the number of pool logicsigs kept compiled.
+const POOL_LOGICSIG_CACHE_SIZE = 1024
+
+type poolKey struct {
+	validatorAppID int
+	asset1ID       int
+	asset2ID       int
+}
+
+type poolLogicsig struct {
+	logic   []byte
+	address string
+}
+
+var poolLogicsigs = utils.NewLRUCache(POOL_LOGICSIG_CACHE_SIZE)
+
+var (
+	registeredContracts      = make(map[int]*compiledContracts)
+	registeredContractsMutex sync.RWMutex
+)
+
+func isValidatorV1_0(validatorAppId int) bool {
+	return validatorAppId == constants.TESTNET_VALIDATOR_APP_ID_V1_0 || validatorAppId == constants.MAINNET_VALIDATOR_APP_ID_V1_0
+}
+
+// readValidatorContracts returns the contract definitions of validatorAppId:
+// the registered ones, or the bundled definitions of its validator version.
+func readValidatorContracts(validatorAppId int) (compiled *compiledContracts, err error) {
+
+	registeredContractsMutex.RLock()
+	compiled, ok := registeredContracts[validatorAppId]
+	registeredContractsMutex.RUnlock()
+
+	if ok {
+		return
+	}
+
+	version := validatorVersion(validatorAppId)
+
+	if _, ok := bundledFiles[version]; !ok {
+		err = fmt.Errorf("contract definitions %s of validator app %d are not bundled, register them with RegisterContracts", version, validatorAppId)
+		return
+	}
+
+	return readBundledContracts(version)
+
+}
+
+// getPoolLogicsig returns the compiled pool logicsig of validatorAppID, asset1ID and asset2ID from the cache,
+// compiling it on a miss.
+func getPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (pool *poolLogicsig, err error) {
+
+	assetID1 := asset1ID
+	assetID2 := asset2ID
+	if assetID1 < assetID2 {
+		assetID1, assetID2 = assetID2, assetID1
+	}
+
+	key := poolKey{validatorAppID, assetID1, assetID2}
+
+	if cached, ok := poolLogicsigs.Get(key); ok {
+		pool = cached.(*poolLogicsig)
+		return
+	}
+
+	contracts, err := readValidatorContracts(validatorAppID)
+	if err != nil {
+		if recovered, ok := recoveredPoolLogicsig(key); ok {
+			pool, err = recovered, nil
+		}
+		return
+	}
 
 	variables := map[string]int{
 		"validator_app_id": validatorAppID,
@@ -51,19 +207,156 @@ func GetPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (lsig *types.LogicS
 		"asset_id_2":       assetID2,
 	}
 
-	programBytes, err := utils.GetProgram(poolLogicsigDef, variables)
+	programBytes, err := utils.GetProgramFromTemplate(contracts.template, contracts.variables, variables)
 	if err != nil {
 		return
 	}
 
-	logsicSig, err := crypto.MakeLogicSig(programBytes, [][]byte{}, nil, crypto.MultisigAccount{})
+	pool = &poolLogicsig{
+		logic:   programBytes,
+		address: crypto.AddressFromProgram(programBytes).String(),
+	}
+
+	poolLogicsigs.Add(key, pool)
+
+	return
+
+}
+
+func GetPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (lsig *types.LogicSig, err error) {
+
+	pool, err := getPoolLogicsig(validatorAppID, asset1ID, asset2ID)
 	if err != nil {
 		return
 	}
 
+	// the cached program is shared, callers get their own copy
 	lsig = &types.LogicSig{
-		Logic: logsicSig.Logic,
+		Logic: append([]byte{}, pool.logic...),
+	}
+
+	return
+
+}
+
+// PoolAddress returns the address of the pool of asset1ID and asset2ID, without building its logicsig.
+func PoolAddress(validatorAppID, asset1ID, asset2ID int) (poolAddress string, err error) {
+
+	pool, err := getPoolLogicsig(validatorAppID, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	poolAddress = pool.address
+
+	return
+
+}
+
+func GetValidatorApp() (validatorApp *types.ValidatorApp, err error) {
+
+	contracts, err := readBundledContracts("v1.1")
+
+	if err != nil {
+		return
+	}
+
+	// the bundled definitions are shared, callers get their own copy
+	app := contracts.asc.Contracts.ValidatorApp
+	validatorApp = &app
+
+	return
+
+}
+
+// GetValidatorAppOf returns the validator app of the contract definitions of validatorAppId, see readValidatorContracts,
+// or only its local state schema when it was registered with RegisterValidatorLocalStateSchema.
+func GetValidatorAppOf(validatorAppId int) (validatorApp *types.ValidatorApp, err error) {
+
+	contracts, err := readValidatorContracts(validatorAppId)
+	if err != nil {
+		if recovered, ok := recoveredValidatorApp(validatorAppId); ok {
+			app := *recovered
+			validatorApp, err = &app, nil
+		}
+		return
+	}
+
+	app := contracts.asc.Contracts.ValidatorApp
+	if app.LocalStateSchema == (types.LocalStateSchema{}) {
+		err = fmt.Errorf("contract definitions of validator app %d have no validator app", validatorAppId)
+		return
+	}
+
+	validatorApp = &app
+
+	return
+
+}
+
+// PoolLogicsigVariables are the values of the template variables of a pool logicsig.
+type PoolLogicsigVariables struct {
+	ValidatorAppId int `json:"validator_app_id"`
+	Asset1Id       int `json:"asset_1_id"`
+	Asset2Id       int `json:"asset_2_id"`
+}
+
+// ParsePoolLogicsig recognizes a pool logicsig program, e.g. the logicsig of a signed transaction or the auth program
+// of an account, and returns its validator app and assets. It fails unless program is the pool logicsig of the bundled
+// or the registered contract definitions, or a pool logicsig registered with RegisterPoolLogicsig.
+func ParsePoolLogicsig(program []byte) (variables *PoolLogicsigVariables, err error) {
+
+	recoveredContractsMutex.RLock()
+	for key, pool := range recoveredPools {
+		if bytes.Equal(pool.logic, program) {
+			variables = &PoolLogicsigVariables{key.validatorAppID, key.asset1ID, key.asset2ID}
+		}
 	}
+	recoveredContractsMutex.RUnlock()
+
+	if variables != nil {
+		return
+	}
+
+	var definitions []*compiledContracts
+
+	for version := range bundledFiles {
+
+		var bundled *compiledContracts
+		bundled, err = readBundledContracts(version)
+		if err != nil {
+			return
+		}
+
+		definitions = append(definitions, bundled)
+
+	}
+
+	registeredContractsMutex.RLock()
+	for _, compiled := range registeredContracts {
+		definitions = append(definitions, compiled)
+	}
+	registeredContractsMutex.RUnlock()
+
+	for _, compiled := range definitions {
+
+		values, extractErr := utils.ExtractVariables(compiled.asc.Contracts.PoolLogicsig.Logic, program)
+		if extractErr != nil {
+			continue
+		}
+
+		validatorAppID, ok1 := values["validator_app_id"].(int)
+		asset1ID, ok2 := values["asset_id_1"].(int)
+		asset2ID, ok3 := values["asset_id_2"].(int)
+
+		if ok1 && ok2 && ok3 {
+			variables = &PoolLogicsigVariables{validatorAppID, asset1ID, asset2ID}
+			return
+		}
+
+	}
+
+	err = fmt.Errorf("program is not a pool logicsig")
 
 	return
 
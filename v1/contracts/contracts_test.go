@@ -1,9 +1,14 @@
 package contracts
 
 import (
+	b64 "encoding/base64"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
 
 	"github.com/algorand/go-algorand-sdk/crypto"
 	"github.com/stretchr/testify/assert"
@@ -34,3 +39,266 @@ func TestGetPoolLogicsig(t *testing.T) {
 	assert.Equal(t, expectedAddress, actualAddress)
 
 }
+
+func TestRegisterContracts(t *testing.T) {
+
+	validatorAppID := constants.TESTNET_VALIDATOR_APP_ID_V1_0
+
+	assert.Equal(t, "v1.0", validatorVersion(validatorAppID))
+	assert.Equal(t, "v1.1", validatorVersion(constants.TESTNET_VALIDATOR_APP_ID_V1_1))
+
+	_, err := readBundledContracts("v1.0")
+	assert.NotNil(t, err)
+
+	_, err = GetPoolLogicsig(validatorAppID, 1, 2)
+	assert.NotNil(t, err)
+
+	_, err = GetValidatorAppOf(validatorAppID)
+	assert.NotNil(t, err)
+
+	// the v1.0 definitions are not bundled, the v1.1 ones stand in for any verified definitions
+	file, err := f.ReadFile(bundledFiles["v1.1"])
+	assert.Nil(t, err)
+
+	err = RegisterContracts(validatorAppID, "{}", "")
+	assert.NotNil(t, err)
+
+	err = RegisterContracts(validatorAppID, string(file), "")
+	assert.Nil(t, err)
+
+	defer func() {
+		registeredContractsMutex.Lock()
+		delete(registeredContracts, validatorAppID)
+		registeredContractsMutex.Unlock()
+		poolLogicsigs.Purge()
+	}()
+
+	lsig, err := GetPoolLogicsig(validatorAppID, 1, 2)
+	assert.Nil(t, err)
+	assert.NotEmpty(t, lsig.Logic)
+
+	validatorApp, err := GetValidatorAppOf(validatorAppID)
+	assert.Nil(t, err)
+	assert.Equal(t, 16, validatorApp.LocalStateSchema.NumUints)
+
+}
+
+func TestPoolAddress(t *testing.T) {
+
+	poolAddress, err := PoolAddress(1, 2, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "7ZRYUGMMMGCBBQYMKEHIU7YMZ7WW6H4ADOIBAH3MCELK3KGAUC7MVJ5OAY", poolAddress)
+
+	lsig, err := GetPoolLogicsig(1, 1, 2)
+	assert.Nil(t, err)
+
+	// callers must not be able to change the cached program
+	lsig.Logic[0] ^= 0xff
+
+	lsig, err = GetPoolLogicsig(1, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, poolAddress, crypto.AddressFromProgram(lsig.Logic).String())
+
+}
+
+func BenchmarkGetPoolLogicsig(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		_, _ = GetPoolLogicsig(1, 1, 2)
+	}
+
+}
+
+func BenchmarkPoolAddress(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		_, _ = PoolAddress(1, 1, 2)
+	}
+
+}
+
+func BenchmarkPoolAddressUncached(b *testing.B) {
+
+	for i := 0; i < b.N; i++ {
+		_, _ = PoolAddress(1, 1, 3+i)
+	}
+
+}
+
+func TestLoadDefinitions(t *testing.T) {
+
+	file, err := f.ReadFile("asc.json")
+	assert.Nil(t, err)
+
+	err = VerifyDefinitions(string(file), "")
+	assert.Nil(t, err)
+
+	tamper := func(change func(data *types.ASC)) string {
+		var data types.ASC
+		err := json.Unmarshal(file, &data)
+		assert.Nil(t, err)
+		change(&data)
+		ascJSON, err := json.Marshal(data)
+		assert.Nil(t, err)
+		return string(ascJSON)
+	}
+
+	// a program that does not match its hash
+	err = VerifyDefinitions(tamper(func(data *types.ASC) {
+		bytecode, _ := b64.StdEncoding.DecodeString(data.Contracts.PoolLogicsig.Logic.Bytecode)
+		bytecode[len(bytecode)-1] ^= 0xff
+		data.Contracts.PoolLogicsig.Logic.Bytecode = b64.StdEncoding.EncodeToString(bytecode)
+	}), "")
+	assert.NotNil(t, err)
+
+	// a program that matches its own hash but not the pinned one
+	changeProgram := func(data *types.ASC) {
+		bytecode, _ := b64.StdEncoding.DecodeString(data.Contracts.PoolLogicsig.Logic.Bytecode)
+		bytecode[len(bytecode)-1] ^= 0xff
+		data.Contracts.PoolLogicsig.Logic.Bytecode = b64.StdEncoding.EncodeToString(bytecode)
+		data.Contracts.PoolLogicsig.Logic.Address = crypto.AddressFromProgram(bytecode).String()
+	}
+	err = VerifyDefinitions(tamper(changeProgram), "")
+	assert.NotNil(t, err)
+
+	// sources are required
+	err = VerifyDefinitions(tamper(func(data *types.ASC) {
+		data.Contracts.ValidatorApp.ClearProgram.Source = ""
+	}), "")
+	assert.NotNil(t, err)
+
+	err = VerifyDefinitions(tamper(func(data *types.ASC) {
+		data.Contracts.PoolLogicsig.Logic.Source = data.Repo + "/tree/" + data.Ref + "/../contracts/pool_logicsig.teal.tmpl"
+	}), "")
+	assert.NotNil(t, err)
+
+	// definitions of a release that is not pinned need the expected pool logicsig address
+	fork := func(data *types.ASC) {
+		changeProgram(data)
+		sources := []*string{&data.Contracts.PoolLogicsig.Logic.Source, &data.Contracts.ValidatorApp.ApprovalProgram.Source, &data.Contracts.ValidatorApp.ClearProgram.Source}
+		for _, source := range sources {
+			*source = strings.Replace(*source, data.Ref, "fork", 1)
+		}
+		data.Ref = "fork"
+	}
+	var forkData types.ASC
+	err = json.Unmarshal([]byte(tamper(fork)), &forkData)
+	assert.Nil(t, err)
+	forkAddress := forkData.Contracts.PoolLogicsig.Logic.Address
+
+	err = VerifyDefinitions(tamper(fork), "")
+	assert.NotNil(t, err)
+
+	err = VerifyDefinitions(tamper(fork), forkAddress)
+	assert.Nil(t, err)
+
+	err = VerifyDefinitions(tamper(fork), "ABUKAXTANWR6K6ZYV75DWJEPVWWOU6SFUVRI6QHO44E4SIDLHBTD2CZ64A")
+	assert.NotNil(t, err)
+
+	// a variable past the end of the template
+	err = VerifyDefinitions(tamper(func(data *types.ASC) {
+		data.Contracts.PoolLogicsig.Logic.Variables[0].Index = data.Contracts.PoolLogicsig.Logic.Size
+	}), "")
+	assert.NotNil(t, err)
+
+	// sources from another ref
+	err = VerifyDefinitions(tamper(func(data *types.ASC) {
+		data.Ref = "main"
+	}), "")
+	assert.NotNil(t, err)
+
+	err = LoadDefinitions("private", tamper(func(data *types.ASC) {
+		data.Repo = "http://example.com"
+	}), "")
+	assert.NotNil(t, err)
+
+	err = UseDefinitions(1, "private")
+	assert.NotNil(t, err)
+
+	err = LoadDefinitions("private", string(file), "")
+	assert.Nil(t, err)
+
+	err = UseDefinitions(1, "private")
+	assert.Nil(t, err)
+
+	defer func() {
+		namedContractsMutex.Lock()
+		delete(namedContracts, "private")
+		namedContractsMutex.Unlock()
+		registeredContractsMutex.Lock()
+		delete(registeredContracts, 1)
+		registeredContractsMutex.Unlock()
+		poolLogicsigs.Purge()
+	}()
+
+	poolAddress, err := PoolAddress(1, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "7ZRYUGMMMGCBBQYMKEHIU7YMZ7WW6H4ADOIBAH3MCELK3KGAUC7MVJ5OAY", poolAddress)
+
+}
+
+func TestParsePoolLogicsig(t *testing.T) {
+
+	lsig, err := GetPoolLogicsig(1, 1, 2)
+	assert.Nil(t, err)
+
+	variables, err := ParsePoolLogicsig(lsig.Logic)
+	assert.Nil(t, err)
+	assert.Equal(t, &PoolLogicsigVariables{ValidatorAppId: 1, Asset1Id: 2, Asset2Id: 1}, variables)
+
+	_, err = ParsePoolLogicsig(lsig.Logic[1:])
+	assert.NotNil(t, err)
+
+}
+
+func TestRegisterPoolLogicsig(t *testing.T) {
+
+	validatorAppID := constants.MAINNET_VALIDATOR_APP_ID_V1_0
+
+	defer func() {
+		recoveredContractsMutex.Lock()
+		recoveredPools = make(map[poolKey]*poolLogicsig)
+		recoveredValidatorApps = make(map[int]*types.ValidatorApp)
+		recoveredContractsMutex.Unlock()
+	}()
+
+	_, err := PoolAddress(validatorAppID, 31566704, 0)
+	assert.NotNil(t, err)
+
+	_, err = GetValidatorAppOf(validatorAppID)
+	assert.NotNil(t, err)
+
+	// a stand-in for a v1.0 pool logicsig, it holds the validator app and the assets
+	program := append([]byte{0x04, 0x20, 0x03}, utils.EncodeVarint(validatorAppID)...)
+	program = append(program, utils.EncodeVarint(31566704)...)
+	program = append(program, utils.EncodeVarint(0)...)
+	poolAddress := crypto.AddressFromProgram(program).String()
+
+	err = RegisterPoolLogicsig(validatorAppID, 0, 31566704, program, crypto.GenerateAccount().Address.String())
+	assert.NotNil(t, err)
+
+	err = RegisterPoolLogicsig(validatorAppID, 0, 31566705, program, poolAddress)
+	assert.NotNil(t, err)
+
+	err = RegisterPoolLogicsig(validatorAppID, 0, 31566704, program, poolAddress)
+	assert.Nil(t, err)
+
+	address, err := PoolAddress(validatorAppID, 31566704, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, poolAddress, address)
+
+	lsig, err := GetPoolLogicsig(validatorAppID, 0, 31566704)
+	assert.Nil(t, err)
+	assert.Equal(t, program, lsig.Logic)
+
+	variables, err := ParsePoolLogicsig(program)
+	assert.Nil(t, err)
+	assert.Equal(t, &PoolLogicsigVariables{ValidatorAppId: validatorAppID, Asset1Id: 31566704, Asset2Id: 0}, variables)
+
+	RegisterValidatorLocalStateSchema(validatorAppID, 16, 0)
+
+	validatorApp, err := GetValidatorAppOf(validatorAppID)
+	assert.Nil(t, err)
+	assert.Equal(t, types.LocalStateSchema{NumUints: 16, NumByteSlices: 0}, validatorApp.LocalStateSchema)
+
+}
@@ -0,0 +1,393 @@
+package contracts
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+)
+
+var (
+	namedContracts      = make(map[string]*compiledContracts)
+	namedContractsMutex sync.RWMutex
+)
+
+// pinnedProgram is the address of a program of a release and the path of its source in the release repo.
+type pinnedProgram struct {
+	address string
+	source  string
+}
+
+// pinnedDefinitions are the programs of the known releases by repo and ref, see pinnedDefinitionsKey.
+// Definitions that declare one of these releases are rejected unless their programs are the pinned ones.
+var pinnedDefinitions = map[string]map[string]pinnedProgram{
+	"https://github.com/tinymanorg/tinyman-contracts-v1@13acadd1a619d0fcafadd6f6c489a906bf347484": {
+		"pool logicsig":    {"ABUKAXTANWR6K6ZYV75DWJEPVWWOU6SFUVRI6QHO44E4SIDLHBTD2CZ64A", "contracts/pool_logicsig.teal.tmpl"},
+		"approval program": {"BUQHXHPLMYUVS3P2INJ2EUJFCSNT6LNUGXVM6T2SZ27TDRDYLUMWCFYW3E", "contracts/validator_approval.teal"},
+		"clear program":    {"P7GEWDXXW5IONRW6XRIRVPJCT2XXEQGOBGG65VJPBUOYZEJCBZWTPHS3VQ", "contracts/validator_clear_state.teal"},
+	},
+}
+
+func pinnedDefinitionsKey(repo, ref string) string {
+	return repo + "@" + ref
+}
+
+// parseDefinitions decodes asc.json contract definitions and rejects them unless they pass verifyDefinitions.
+func parseDefinitions(ascJSON, expectedAddress string) (compiled *compiledContracts, err error) {
+
+	var data types.ASC
+	err = json.Unmarshal([]byte(ascJSON), &data)
+	if err != nil {
+		return
+	}
+
+	if len(data.Contracts.PoolLogicsig.Logic.Bytecode) == 0 {
+		err = fmt.Errorf("contract definitions have no pool logicsig")
+		return
+	}
+
+	compiled, err = compileContracts(data)
+	if err != nil {
+		return
+	}
+
+	err = verifyDefinitions(compiled, expectedAddress)
+	if err != nil {
+		compiled = nil
+	}
+
+	return
+
+}
+
+// verifyProgram checks that bytecode hashes to address and, when size is set, that it is size bytes long.
+func verifyProgram(name, bytecode, address string, size int) (program []byte, err error) {
+
+	program, err = b64.StdEncoding.DecodeString(bytecode)
+	if err != nil {
+		err = fmt.Errorf("%s bytecode is not base64: %s", name, err)
+		return
+	}
+
+	if size != 0 && size != len(program) {
+		err = fmt.Errorf("%s bytecode is %d bytes, expected %d", name, len(program), size)
+		return
+	}
+
+	if crypto.AddressFromProgram(program).String() != address {
+		err = fmt.Errorf("%s bytecode does not match its address %s", name, address)
+	}
+
+	return
+
+}
+
+// verifySource checks that source is a file of repo at ref.
+func verifySource(name, source, repo, ref string) (err error) {
+
+	prefix := repo + "/tree/" + ref + "/"
+
+	if !strings.HasPrefix(source, prefix) {
+		err = fmt.Errorf("%s source %q is not in %s at %s", name, source, repo, ref)
+		return
+	}
+
+	file := strings.TrimPrefix(source, prefix)
+
+	if len(file) == 0 || path.Clean(file) != file || strings.HasPrefix(file, "../") || strings.ContainsAny(file, "?#") {
+		err = fmt.Errorf("%s source %q is not a file path", name, source)
+	}
+
+	return
+
+}
+
+// verifyPinned checks that the program name of a pinned release has the pinned address and source.
+func verifyPinned(name, address, source, repo, ref string, pins map[string]pinnedProgram) (err error) {
+
+	pin, ok := pins[name]
+	if !ok {
+		err = fmt.Errorf("%s of %s at %s is not pinned", name, repo, ref)
+		return
+	}
+
+	if address != pin.address {
+		err = fmt.Errorf("%s address %s is not the address %s of %s at %s", name, address, pin.address, repo, ref)
+		return
+	}
+
+	if source != repo+"/tree/"+ref+"/"+pin.source {
+		err = fmt.Errorf("%s source %q is not %s of %s at %s", name, source, pin.source, repo, ref)
+	}
+
+	return
+
+}
+
+// verifyDefinitions checks that the programs match their hashes, that the pool logicsig template variables
+// are within the template and do not overlap, and that every program comes from the declared repo and ref.
+// The programs of a pinned repo and ref must be the pinned ones, other definitions are only trusted when their
+// pool logicsig template hashes to expectedAddress.
+func verifyDefinitions(compiled *compiledContracts, expectedAddress string) (err error) {
+
+	data := compiled.asc
+
+	if !strings.HasPrefix(data.Repo, "https://") {
+		err = fmt.Errorf("contract definitions repo %q is not an https URL", data.Repo)
+		return
+	}
+
+	if len(data.Ref) == 0 {
+		err = fmt.Errorf("contract definitions have no ref")
+		return
+	}
+
+	pins, pinned := pinnedDefinitions[pinnedDefinitionsKey(data.Repo, data.Ref)]
+
+	if !pinned && len(expectedAddress) == 0 {
+		err = fmt.Errorf("contract definitions of %s at %s are not pinned, an expected pool logicsig address is required", data.Repo, data.Ref)
+		return
+	}
+
+	// verify checks the program name against its hash, its source and, for a pinned release, its pin
+	verify := func(name, bytecode, address, source string, size int) (err error) {
+
+		_, err = verifyProgram(name, bytecode, address, size)
+		if err != nil {
+			return
+		}
+
+		err = verifySource(name, source, data.Repo, data.Ref)
+		if err != nil {
+			return
+		}
+
+		if pinned {
+			err = verifyPinned(name, address, source, data.Repo, data.Ref, pins)
+		}
+
+		return
+
+	}
+
+	poolLogicsigDef := data.Contracts.PoolLogicsig.Logic
+
+	err = verify("pool logicsig", poolLogicsigDef.Bytecode, poolLogicsigDef.Address, poolLogicsigDef.Source, poolLogicsigDef.Size)
+	if err != nil {
+		return
+	}
+
+	if len(expectedAddress) > 0 && poolLogicsigDef.Address != expectedAddress {
+		err = fmt.Errorf("pool logicsig address %s is not the expected address %s", poolLogicsigDef.Address, expectedAddress)
+		return
+	}
+
+	names := make(map[string]bool)
+	end := 0
+
+	// compiled.variables are sorted by index
+	for _, v := range compiled.variables {
+
+		if v.Index < end || v.Length <= 0 || v.Index+v.Length > len(compiled.template) {
+			err = fmt.Errorf("pool logicsig variable %s at %d is out of bounds", v.Name, v.Index)
+			return
+		}
+
+		if v.Type != "int" && v.Type != "uint64" && v.Type != "bytes" && v.Type != "address" {
+			err = fmt.Errorf("pool logicsig variable %s has unsupported type %s", v.Name, v.Type)
+			return
+		}
+
+		names[v.Name] = true
+		end = v.Index + v.Length
+
+	}
+
+	for _, name := range []string{"TMPL_VALIDATOR_APP_ID", "TMPL_ASSET_ID_1", "TMPL_ASSET_ID_2"} {
+		if !names[name] {
+			err = fmt.Errorf("pool logicsig has no %s variable", name)
+			return
+		}
+	}
+
+	validatorApp := data.Contracts.ValidatorApp
+
+	if len(validatorApp.ApprovalProgram.Bytecode) == 0 {
+		return
+	}
+
+	err = verify("approval program", validatorApp.ApprovalProgram.Bytecode, validatorApp.ApprovalProgram.Address, validatorApp.ApprovalProgram.Source, validatorApp.ApprovalProgram.Size)
+	if err != nil {
+		return
+	}
+
+	err = verify("clear program", validatorApp.ClearProgram.Bytecode, validatorApp.ClearProgram.Address, validatorApp.ClearProgram.Source, validatorApp.ClearProgram.Size)
+
+	return
+
+}
+
+// VerifyDefinitions returns an error when asc.json contract definitions would be rejected by LoadDefinitions.
+func VerifyDefinitions(ascJSON, expectedAddress string) (err error) {
+
+	_, err = parseDefinitions(ascJSON, expectedAddress)
+
+	return
+
+}
+
+// LoadDefinitions verifies asc.json contract definitions and registers them under name, see UseDefinitions.
+// Definitions are rejected unless their programs match their addresses, the pool logicsig template variables
+// are within its bytecode and every program source is a file of the declared repo at the declared ref.
+// expectedAddress is the address of the pool logicsig template, from a source the caller trusts. It is required
+// unless the repo and ref are a release pinned by the SDK, whose programs must then be the pinned ones.
+func LoadDefinitions(name, ascJSON, expectedAddress string) (err error) {
+
+	compiled, err := parseDefinitions(ascJSON, expectedAddress)
+	if err != nil {
+		return
+	}
+
+	namedContractsMutex.Lock()
+	namedContracts[name] = compiled
+	namedContractsMutex.Unlock()
+
+	return
+
+}
+
+// LoadDefinitionsFromFile is LoadDefinitions with the asc.json contract definitions read from path.
+func LoadDefinitionsFromFile(name, path, expectedAddress string) (err error) {
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	return LoadDefinitions(name, string(file), expectedAddress)
+
+}
+
+// UseDefinitions makes the pools of validatorAppId use the contract definitions loaded under name.
+func UseDefinitions(validatorAppId int, name string) (err error) {
+
+	namedContractsMutex.RLock()
+	compiled, ok := namedContracts[name]
+	namedContractsMutex.RUnlock()
+
+	if !ok {
+		err = fmt.Errorf("contract definitions %q are not loaded", name)
+		return
+	}
+
+	registerContracts(validatorAppId, compiled)
+
+	return
+
+}
+
+// RegisterContracts verifies asc.json contract definitions like LoadDefinitions and registers them for the pools
+// of validatorAppId. The SDK bundles the v1.1 definitions only, register the v1.0 definitions to work with v1.0 pools.
+func RegisterContracts(validatorAppId int, ascJSON, expectedAddress string) (err error) {
+
+	compiled, err := parseDefinitions(ascJSON, expectedAddress)
+	if err != nil {
+		return
+	}
+
+	registerContracts(validatorAppId, compiled)
+
+	return
+
+}
+
+func registerContracts(validatorAppId int, compiled *compiledContracts) {
+
+	registeredContractsMutex.Lock()
+	registeredContracts[validatorAppId] = compiled
+	registeredContractsMutex.Unlock()
+
+	// the cached pool logicsigs of validatorAppId may come from the previous definitions
+	poolLogicsigs.Purge()
+
+}
+
+var (
+	recoveredPools          = make(map[poolKey]*poolLogicsig)
+	recoveredValidatorApps  = make(map[int]*types.ValidatorApp)
+	recoveredContractsMutex sync.RWMutex
+)
+
+// RegisterPoolLogicsig registers the logicsig program of the pool of asset1ID and asset2ID of validatorAppID, for
+// validators whose contract definitions are not available, e.g. v1.0 pools, with the program of a transaction
+// the pool signed, see TinymanClient.FetchPoolLogicsig.
+// The program is rejected unless it hashes to poolAddress and holds the validator app and the assets.
+func RegisterPoolLogicsig(validatorAppID, asset1ID, asset2ID int, program []byte, poolAddress string) (err error) {
+
+	if crypto.AddressFromProgram(program).String() != poolAddress {
+		err = fmt.Errorf("pool logicsig does not match the pool address %s", poolAddress)
+		return
+	}
+
+	for _, id := range []int{validatorAppID, asset1ID, asset2ID} {
+		if !bytes.Contains(program, utils.EncodeVarint(id)) {
+			err = fmt.Errorf("pool logicsig of %s does not hold %d", poolAddress, id)
+			return
+		}
+	}
+
+	if asset1ID < asset2ID {
+		asset1ID, asset2ID = asset2ID, asset1ID
+	}
+
+	recoveredContractsMutex.Lock()
+	recoveredPools[poolKey{validatorAppID, asset1ID, asset2ID}] = &poolLogicsig{
+		logic:   append([]byte{}, program...),
+		address: poolAddress,
+	}
+	recoveredContractsMutex.Unlock()
+
+	return
+
+}
+
+// RegisterValidatorLocalStateSchema registers the local state schema of validatorAppId for validators whose
+// contract definitions are not available, e.g. the v1.0 validator, see TinymanClient.FetchValidatorLocalStateSchema.
+func RegisterValidatorLocalStateSchema(validatorAppId, numUints, numByteSlices int) {
+
+	recoveredContractsMutex.Lock()
+	recoveredValidatorApps[validatorAppId] = &types.ValidatorApp{
+		LocalStateSchema: types.LocalStateSchema{NumUints: numUints, NumByteSlices: numByteSlices},
+	}
+	recoveredContractsMutex.Unlock()
+
+}
+
+func recoveredPoolLogicsig(key poolKey) (pool *poolLogicsig, ok bool) {
+
+	recoveredContractsMutex.RLock()
+	pool, ok = recoveredPools[key]
+	recoveredContractsMutex.RUnlock()
+
+	return
+
+}
+
+func recoveredValidatorApp(validatorAppId int) (validatorApp *types.ValidatorApp, ok bool) {
+
+	recoveredContractsMutex.RLock()
+	validatorApp, ok = recoveredValidatorApps[validatorAppId]
+	recoveredContractsMutex.RUnlock()
+
+	return
+
+}
@@ -0,0 +1,81 @@
+package deploy
+
+import (
+	b64 "encoding/base64"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// maxProgramPageSize is the size of the approval and clear programs an app gets without extra pages.
+const maxProgramPageSize = 2048
+
+// PrepareDeployValidatorTransactions creates a validator app from the programs and schemas of the bundled contract definitions,
+// to run Tinyman on a private network. Once the group is confirmed, TinymanClient.FetchCreatedAppId returns the app ID
+// to create a TinymanClient with.
+func PrepareDeployValidatorTransactions(creatorAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	validatorApp, err := contracts.GetValidatorApp()
+	if err != nil {
+		return
+	}
+
+	return prepareDeployTransactions(validatorApp, creatorAddress, suggestedParams, options)
+
+}
+
+func prepareDeployTransactions(validatorApp *types.ValidatorApp, creatorAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	creator, err := algoTypes.DecodeAddress(creatorAddress)
+	if err != nil {
+		return
+	}
+
+	approvalProgram, err := b64.StdEncoding.DecodeString(validatorApp.ApprovalProgram.Bytecode)
+	if err != nil {
+		return
+	}
+
+	clearProgram, err := b64.StdEncoding.DecodeString(validatorApp.ClearProgram.Bytecode)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	globalSchema := algoTypes.StateSchema{
+		NumUint:      uint64(validatorApp.GlobalStateSchema.NumUints),
+		NumByteSlice: uint64(validatorApp.GlobalStateSchema.NumByteSlices),
+	}
+
+	localSchema := algoTypes.StateSchema{
+		NumUint:      uint64(validatorApp.LocalStateSchema.NumUints),
+		NumByteSlice: uint64(validatorApp.LocalStateSchema.NumByteSlices),
+	}
+
+	extraPages := uint32((len(approvalProgram) + len(clearProgram) - 1) / maxProgramPageSize)
+
+	txn, err := future.MakeApplicationCreateTxWithExtraPages(false, approvalProgram, clearProgram, globalSchema, localSchema, nil, nil, nil, nil, algoSuggestedParams, creator, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{}, extraPages)
+	if err != nil {
+		return
+	}
+
+	transactions := []algoTypes.Transaction{txn}
+
+	transactions, err = utils.ApplyTxnOptions(transactions, creator, options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(transactions)
+
+	return
+
+}
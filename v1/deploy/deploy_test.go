@@ -0,0 +1,37 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareDeployValidatorTransactions(t *testing.T) {
+
+	creator := crypto.GenerateAccount().Address
+
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		GenesisID:       "sandnet-v1",
+		GenesisHash:     make([]byte, 32),
+		FirstRoundValid: 1,
+		LastRoundValid:  1001,
+		FlatFee:         true,
+	}
+
+	txnGroup, err := PrepareDeployValidatorTransactions(creator.String(), suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txn := txnGroup.GetTransactions()[0]
+
+	assert.Equal(t, creator, txn.Sender)
+	assert.Equal(t, uint64(0), uint64(txn.ApplicationID))
+	assert.Equal(t, "BUQHXHPLMYUVS3P2INJ2EUJFCSNT6LNUGXVM6T2SZ27TDRDYLUMWCFYW3E", crypto.AddressFromProgram(txn.ApprovalProgram).String())
+	assert.Equal(t, "P7GEWDXXW5IONRW6XRIRVPJCT2XXEQGOBGG65VJPBUOYZEJCBZWTPHS3VQ", crypto.AddressFromProgram(txn.ClearStateProgram).String())
+	assert.Equal(t, uint64(16), txn.LocalStateSchema.NumUint)
+	assert.Equal(t, uint32(0), txn.ExtraProgramPages)
+
+}
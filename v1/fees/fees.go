@@ -10,7 +10,7 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareRedeemFeesTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, amount, creatorAddress, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareRedeemFeesTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, amount, creatorAddress, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	creator, err := algoTypes.DecodeAddress(creatorAddress)
 	if err != nil {
@@ -24,15 +24,9 @@ func PrepareRedeemFeesTransactions(validatorAppId, asset1ID, asset2ID, liquidity
 
 	amountBig := utils.NewBigIntString(amount)
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppId, asset1ID, asset2ID)
@@ -71,6 +65,11 @@ func PrepareRedeemFeesTransactions(validatorAppId, asset1ID, asset2ID, liquidity
 
 	txns := []algoTypes.Transaction{paymentTxn, applicationNoOpTxn, assetTransferTxn}
 
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(txns)
 
 	if err != nil {
@@ -10,7 +10,7 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareMintTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, asset1Amount, asset2Amount, liquidityAssetAmount, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareMintTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID int, asset1Amount, asset2Amount, liquidityAssetAmount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
@@ -23,15 +23,9 @@ func PrepareMintTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	asset1AmountBig := utils.NewBigIntString(asset1Amount)
@@ -85,6 +79,11 @@ func PrepareMintTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 
 	txns := []algoTypes.Transaction{paymentTxn, applicationNoOpTxn, assetTransferTxn1, assetTransferTxn2, assetTransferTxn3}
 
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(txns)
 
 	if err != nil {
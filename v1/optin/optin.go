@@ -8,22 +8,16 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareAppOptinTransactions(validatorAppId int, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareAppOptinTransactions(validatorAppId int, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	txn, err := future.MakeApplicationOptInTx(uint64(validatorAppId), nil, nil, nil, nil, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
@@ -34,28 +28,27 @@ func PrepareAppOptinTransactions(validatorAppId int, senderAddress string, sugge
 
 	transactions := []algoTypes.Transaction{txn}
 
+	transactions, err = utils.ApplyTxnOptions(transactions, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(transactions)
 
 	return
 
 }
 
-func PrepareAssetOptinTransactions(assetID int, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareAssetOptinTransactions(assetID int, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	txn, err := future.MakeAssetTransferTxn(sender.String(), sender.String(), 0, nil, algoSuggestedParams, "", uint64(assetID))
@@ -65,6 +58,11 @@ func PrepareAssetOptinTransactions(assetID int, senderAddress string, suggestedP
 
 	transactions := []algoTypes.Transaction{txn}
 
+	transactions, err = utils.ApplyTxnOptions(transactions, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(transactions)
 
 	return
@@ -1,6 +1,10 @@
 package optout
 
 import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
 	"github.com/soheil555/tinyman-mobile-sdk/utils"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
 
@@ -8,20 +12,55 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func GetOptoutTransactions(client *client.TinymanClient, senderAddress string, validatorAppId int) (trxGroup *utils.TransactionGroup, err error) {
+// GetOptoutTransactions opts senderAddress out of the validator app.
+// The Tinyman v1 validator rejects CloseOut calls (contracts/validator_approval.teal of tinyman-contracts-v1, which
+// returns 0 for UpdateApplication, DeleteApplication and CloseOut before anything else), so the only way out is a
+// ClearState call that deletes the local state of the sender, including the excess amounts that are not redeemed yet. Unless force is set, the opt out fails while
+// the local state has excess amounts: redeem them first, e.g. with pools.PrepareExitPlan.
+func GetOptoutTransactions(client *client.TinymanClient, senderAddress string, validatorAppId int, force bool, options *types.TxnOptions) (trxGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
 		return
 	}
 
-	suggestedParams, err := client.SuggestedParams()
+	if !force {
+
+		var excessAmountsStr string
+		excessAmountsStr, err = client.FetchValidatorExcessAmounts(sender.String(), validatorAppId)
+		if err != nil {
+			return
+		}
+
+		if len(excessAmountsStr) == 0 {
+			err = fmt.Errorf("%s is not opted in to validator app %d", sender.String(), validatorAppId)
+			return
+		}
+
+		excessAmounts := make(map[string]map[int]string)
+		err = json.Unmarshal([]byte(excessAmountsStr), &excessAmounts)
+		if err != nil {
+			return
+		}
+
+		if len(excessAmounts) > 0 {
+			err = fmt.Errorf("opting out forfeits the excess amounts of %d pools, redeem them first or force the opt out", len(excessAmounts))
+			return
+		}
+
+	}
+
+	suggestedParams, err := client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
 
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
 	if err != nil {
 		return
 	}
 
-	txn, err := future.MakeApplicationClearStateTx(uint64(validatorAppId), nil, nil, nil, nil, suggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	txn, err := future.MakeApplicationClearStateTx(uint64(validatorAppId), nil, nil, nil, nil, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
 
 	if err != nil {
 		return
@@ -29,7 +68,48 @@ func GetOptoutTransactions(client *client.TinymanClient, senderAddress string, v
 
 	transactions := []algoTypes.Transaction{txn}
 
+	transactions, err = utils.ApplyTxnOptions(transactions, sender, options)
+	if err != nil {
+		return
+	}
+
 	trxGroup, err = utils.NewTransactionGroup(transactions)
 
 	return
 }
+
+// PrepareAssetCloseOutTransactions removes the asset holding of senderAddress and sends its remaining balance to closeToAddress.
+func PrepareAssetCloseOutTransactions(assetID int, closeToAddress, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (trxGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	closeTo, err := algoTypes.DecodeAddress(closeToAddress)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	txn, err := future.MakeAssetTransferTxn(sender.String(), closeTo.String(), 0, nil, algoSuggestedParams, closeTo.String(), uint64(assetID))
+	if err != nil {
+		return
+	}
+
+	transactions := []algoTypes.Transaction{txn}
+
+	transactions, err = utils.ApplyTxnOptions(transactions, sender, options)
+	if err != nil {
+		return
+	}
+
+	trxGroup, err = utils.NewTransactionGroup(transactions)
+
+	return
+
+}
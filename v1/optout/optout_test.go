@@ -0,0 +1,44 @@
+package optout
+
+import (
+	"bytes"
+	b64 "encoding/base64"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The approval program of the bundled validator starts like contracts/validator_approval.teal of tinyman-contracts-v1:
+//
+//	txn OnCompletion; int UpdateApplication; ==
+//	txn OnCompletion; int DeleteApplication; ==; ||
+//	txn OnCompletion; int CloseOut; ==; ||
+//	bnz fail
+//	...
+//	fail: int 0; return
+func TestValidatorRejectsCloseOut(t *testing.T) {
+
+	validatorApp, err := contracts.GetValidatorApp()
+	assert.Nil(t, err)
+
+	program, err := b64.StdEncoding.DecodeString(validatorApp.ApprovalProgram.Bytecode)
+	assert.Nil(t, err)
+
+	// intcblock, its first constant is 0
+	assert.Equal(t, []byte{0x20, 0x07, 0x00}, program[1:4])
+
+	// txn OnCompletion; pushint 2 (CloseOut); ==; ||; bnz
+	closeOut := []byte{0x31, 0x19, 0x81, 0x02, 0x12, 0x11, 0x40}
+	index := bytes.Index(program, closeOut)
+	assert.Equal(t, 75, index)
+
+	// the branch target is relative to the end of the bnz instruction
+	bnzEnd := index + len(closeOut) + 2
+	target := bnzEnd + int(program[bnzEnd-2])<<8 + int(program[bnzEnd-1])
+
+	// intc_0 (0); return
+	assert.Equal(t, []byte{0x22, 0x43}, program[target:target+2])
+
+}
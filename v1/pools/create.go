@@ -0,0 +1,233 @@
+package pools
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type CreatePoolResult struct {
+	// Plan has the steps to submit in order, stop at the first step that fails.
+	Plan *utils.TransactionPlan `json:"-"`
+	// Pool is the pool to create, with the status it has before Plan is submitted.
+	Pool *Pool `json:"pool"`
+	// MintQuote is the initial mint, nil until the pool is bootstrapped.
+	MintQuote *MintQuote `json:"mint-quote"`
+	// Complete is false when Plan stops at the bootstrap, because the initial mint needs the liquidity asset
+	// the bootstrap creates. Prepare the plan again once Plan is submitted.
+	Complete bool `json:"complete"`
+	// statuses are the statuses of the pool after each step of Plan.
+	statuses []string
+}
+
+// GetStepStatus is the status of the pool once the step index of Plan is confirmed.
+func (s *CreatePoolResult) GetStepStatus(index int) string {
+	return s.statuses[index]
+}
+
+func (s *CreatePoolResult) addStep(name, description string, txnGroup *utils.TransactionGroup, status string) {
+
+	s.Plan.AddStep(name, description, txnGroup)
+	s.statuses = append(s.statuses, status)
+
+}
+
+// initialMintAmounts returns the amounts of the first mint of a pool that gives liquidityAssetAmount to the minter at price,
+// the amount of asset 2 for one unit of asset 1 as in Pool.Asset1Price. The first mint issues the square root of the product
+// of the amounts and locks LOCKED_LIQUIDITY of it, so the amounts are rounded up to issue at least liquidityAssetAmount.
+func initialMintAmounts(price float64, liquidityAssetAmount *big.Int) (asset1Amount, asset2Amount *big.Int, err error) {
+
+	if price <= 0 {
+		err = fmt.Errorf("initial price must be greater than 0")
+		return
+	}
+
+	if liquidityAssetAmount.Sign() <= 0 {
+		err = fmt.Errorf("initial liquidity must be greater than 0")
+		return
+	}
+
+	issuedLiquidity := new(big.Int).Add(liquidityAssetAmount, big.NewInt(constants.LOCKED_LIQUIDITY))
+
+	priceBig := new(big.Float).SetPrec(256).SetFloat64(price)
+	sqrtPrice := new(big.Float).SetPrec(256).Sqrt(priceBig)
+
+	asset1AmountFloat := new(big.Float).SetPrec(256).SetInt(issuedLiquidity)
+	asset1AmountFloat.Quo(asset1AmountFloat, sqrtPrice)
+	asset1Amount = ceil(asset1AmountFloat)
+
+	asset2AmountFloat := new(big.Float).SetPrec(256).SetInt(asset1Amount)
+	asset2AmountFloat.Mul(asset2AmountFloat, priceBig)
+	asset2Amount = ceil(asset2AmountFloat)
+
+	if asset1Amount.Sign() == 0 || asset2Amount.Sign() == 0 {
+		err = fmt.Errorf("initial price is out of range")
+		return
+	}
+
+	for new(big.Int).Sqrt(new(big.Int).Mul(asset1Amount, asset2Amount)).Cmp(issuedLiquidity) < 0 {
+		asset2Amount.Add(asset2Amount, big.NewInt(1))
+	}
+
+	return
+
+}
+
+func ceil(x *big.Float) *big.Int {
+
+	result, accuracy := x.Int(nil)
+	if accuracy == big.Below {
+		result.Add(result, big.NewInt(1))
+	}
+
+	return result
+
+}
+
+// CreatePool prepares the steps to create the pool of assetA and assetB and make its first mint: the opt in of the creator
+// to the validator app, the bootstrap of the pool, the opt in of the creator to the liquidity asset and the initial mint.
+// price is the amount of assetB for one unit of assetA, in micro units of both, and liquidityAssetAmount is the liquidity
+// the creator gets. The bootstrap funds the pool account with its minimum balance.
+// The plan can be prepared again at any time to resume it: it only has the steps that are left.
+// It fails with a PoolStatusError when the pool already has liquidity.
+func CreatePool(tinymanClient *client.TinymanClient, assetA, assetB *types.Asset, price float64, liquidityAssetAmount, creatorAddress string, options *types.TxnOptions) (result *CreatePoolResult, err error) {
+
+	if len(creatorAddress) == 0 {
+		creatorAddress = tinymanClient.UserAddress
+	}
+
+	creator, err := algoTypes.DecodeAddress(creatorAddress)
+	if err != nil {
+		return
+	}
+
+	if assetA == nil || assetB == nil || assetA.Id == assetB.Id {
+		err = fmt.Errorf("two different assets are required")
+		return
+	}
+
+	liquidityAssetAmountBig, ok := new(big.Int).SetString(liquidityAssetAmount, 10)
+	if !ok {
+		err = fmt.Errorf("invalid liquidity asset amount %s", liquidityAssetAmount)
+		return
+	}
+
+	pool, err := NewPool(tinymanClient, assetA, assetB, nil, true, 0)
+	if err != nil {
+		return
+	}
+
+	if pool.Status == POOL_STATUS_ACTIVE || pool.Status == POOL_STATUS_DRAINED {
+		err = &PoolStatusError{Status: pool.Status, Operation: "create the pool", Reason: "pool already exists"}
+		return
+	}
+
+	asset1Price := price
+	if pool.Asset1.Id != assetA.Id {
+		asset1Price = 1 / price
+	}
+
+	asset1Amount, asset2Amount, err := initialMintAmounts(asset1Price, liquidityAssetAmountBig)
+	if err != nil {
+		return
+	}
+
+	// a lease can only be used by one group, so the groups of the plan are prepared without it
+	options = withoutLease(options)
+
+	account, err := tinymanClient.AccountInformation(creator.String())
+	if err != nil {
+		return
+	}
+
+	result = &CreatePoolResult{Plan: utils.NewTransactionPlan(), Pool: pool}
+
+	optedIn := false
+	for _, a := range account.AppsLocalState {
+		if a.Id == uint64(pool.ValidatorAppId) {
+			optedIn = true
+		}
+	}
+
+	if !optedIn {
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = tinymanClient.PrepareAppOptinTransactions(creator.String(), options)
+		if err != nil {
+			return
+		}
+
+		result.addStep("optin", fmt.Sprintf("opt into validator app %d", pool.ValidatorAppId), txnGroup, pool.Status)
+
+	}
+
+	if pool.Status == POOL_STATUS_NOT_CREATED {
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = pool.PrepareBootstrapTransactions(creator.String(), options)
+		if err != nil {
+			return
+		}
+
+		result.addStep("bootstrap", fmt.Sprintf("create the pool of %s and %s", pool.Asset1.UnitName, pool.Asset2.UnitName), txnGroup, POOL_STATUS_EMPTY)
+		return
+
+	}
+
+	heldLiquidityAsset := false
+	for _, holding := range account.Assets {
+		if int(holding.AssetId) == pool.LiquidityAsset.Id {
+			heldLiquidityAsset = true
+		}
+	}
+
+	if !heldLiquidityAsset {
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = pool.PrepareLiquidityAssetOptinTransactions(creator.String(), options)
+		if err != nil {
+			return
+		}
+
+		result.addStep("optin", fmt.Sprintf("opt into asset %d", pool.LiquidityAsset.Id), txnGroup, pool.Status)
+
+	}
+
+	issuedLiquidity := new(big.Int).Sqrt(new(big.Int).Mul(asset1Amount, asset2Amount))
+
+	result.MintQuote = &MintQuote{
+		amountsIn: map[int]string{
+			pool.Asset1.Id: asset1Amount.String(),
+			pool.Asset2.Id: asset2Amount.String(),
+		},
+		LiquidityAssetAmount: &types.AssetAmount{Asset: pool.LiquidityAsset, Amount: issuedLiquidity.Sub(issuedLiquidity, big.NewInt(constants.LOCKED_LIQUIDITY)).String()},
+		Slippage:             0,
+	}
+
+	err = pool.setMintExcess(result.MintQuote)
+	if err != nil {
+		return
+	}
+
+	result.MintQuote.Round, result.MintQuote.Timestamp, result.MintQuote.PoolAddress, err = pool.quoteSource()
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := pool.PrepareMintTransactionsFromQuote(result.MintQuote, creator.String(), options)
+	if err != nil {
+		return
+	}
+
+	result.addStep("mint", fmt.Sprintf("mint %s in the pool of %s and %s", result.MintQuote.LiquidityAssetAmount.Amount, pool.Asset1.UnitName, pool.Asset2.UnitName), txnGroup, POOL_STATUS_ACTIVE)
+	result.Complete = true
+
+	return
+
+}
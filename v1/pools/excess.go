@@ -0,0 +1,188 @@
+package pools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+)
+
+// setExcess predicts the excess of the swap. The validator transfers the quoted amount with slippage and credits the
+// difference with the amount it computes to the local state of the swapper: the output asset of a fixed-input swap,
+// the input asset of a fixed-output swap.
+func (s *SwapQuote) setExcess() (err error) {
+
+	var quoted, withSlippage *types.AssetAmount
+
+	if s.SwapType == "fixed-output" {
+		quoted = s.AmountIn
+		withSlippage, err = s.AmountInWithSlippage()
+	} else {
+		quoted = s.AmountOut
+		withSlippage, err = s.AmountOutWithSlippage()
+	}
+
+	if err != nil {
+		return
+	}
+
+	excess := new(big.Int).Sub(utils.NewBigIntString(withSlippage.Amount), utils.NewBigIntString(quoted.Amount))
+	excess.Abs(excess)
+
+	s.ExpectedExcess = &types.AssetAmount{Asset: quoted.Asset, Amount: excess.String()}
+	// at the slippage boundary the validator computes exactly the amount with slippage
+	s.WorstCaseExcess = &types.AssetAmount{Asset: quoted.Asset, Amount: "0"}
+
+	return
+
+}
+
+// nonNegative returns x, or zero when x is negative.
+func nonNegative(x *big.Int) *big.Int {
+
+	if x.Sign() < 0 {
+		return new(big.Int)
+	}
+
+	return x
+
+}
+
+// setMintExcess predicts the excess of the mint. The validator issues the liquidity of the scarcer asset, credits the
+// unused amount of the other asset and the difference between the issued liquidity and the quoted liquidity with
+// slippage to the local state of the pooler.
+//
+// The worst case is at the slippage boundary: swaps moved the reserves along the constant product until only the
+// liquidity with slippage is issued, leaving the largest unused amount of the other asset.
+func (s *Pool) setMintExcess(quote *MintQuote) (err error) {
+
+	quote.expectedExcess = map[int]string{s.Asset1.Id: "0", s.Asset2.Id: "0", s.LiquidityAsset.Id: "0"}
+	quote.worstCaseExcess = map[int]string{s.Asset1.Id: "0", s.Asset2.Id: "0", s.LiquidityAsset.Id: "0"}
+
+	issuedLiquidity := utils.NewBigIntString(s.IssuedLiquidity)
+
+	// the first mint uses both amounts entirely and has no slippage
+	if issuedLiquidity.Sign() == 0 {
+		return
+	}
+
+	minLiquidityAmount, err := quote.LiquidityAssetAmountWithSlippage()
+	if err != nil {
+		return
+	}
+
+	amount1 := utils.NewBigIntString(quote.amountsIn[s.Asset1.Id])
+	amount2 := utils.NewBigIntString(quote.amountsIn[s.Asset2.Id])
+	reserves1 := utils.NewBigIntString(s.Asset1Reserves)
+	reserves2 := utils.NewBigIntString(s.Asset2Reserves)
+	liquidity := utils.NewBigIntString(quote.LiquidityAssetAmount.Amount)
+	minLiquidity := utils.NewBigIntString(minLiquidityAmount.Amount)
+
+	// used is the amount of an asset the validator keeps for issuing liquidity at reserves
+	used := func(liquidity, reserves *big.Int) *big.Int {
+		used := new(big.Int).Mul(liquidity, reserves)
+		return used.Quo(used, issuedLiquidity)
+	}
+
+	quote.expectedExcess[s.Asset1.Id] = nonNegative(new(big.Int).Sub(amount1, used(liquidity, reserves1))).String()
+	quote.expectedExcess[s.Asset2.Id] = nonNegative(new(big.Int).Sub(amount2, used(liquidity, reserves2))).String()
+	quote.expectedExcess[s.LiquidityAsset.Id] = nonNegative(new(big.Int).Sub(liquidity, minLiquidity)).String()
+
+	if minLiquidity.Sign() == 0 || amount1.Sign() == 0 || amount2.Sign() == 0 {
+		quote.worstCaseExcess[s.Asset1.Id] = amount1.String()
+		quote.worstCaseExcess[s.Asset2.Id] = amount2.String()
+		return
+	}
+
+	// when the asset1 reserves grew until amount1 issues minLiquidity, the asset2 reserves are
+	// reserves1 * reserves2 * minLiquidity / (amount1 * issued), and so on for asset2
+	product := new(big.Int).Mul(reserves1, reserves2)
+	product.Mul(product, minLiquidity)
+
+	reserves2AtBoundary := new(big.Int).Quo(product, new(big.Int).Mul(amount1, issuedLiquidity))
+	reserves1AtBoundary := new(big.Int).Quo(product, new(big.Int).Mul(amount2, issuedLiquidity))
+
+	quote.worstCaseExcess[s.Asset1.Id] = nonNegative(new(big.Int).Sub(amount1, used(minLiquidity, reserves1AtBoundary))).String()
+	quote.worstCaseExcess[s.Asset2.Id] = nonNegative(new(big.Int).Sub(amount2, used(minLiquidity, reserves2AtBoundary))).String()
+
+	return
+
+}
+
+func (s *MintQuote) GetExpectedExcess() map[int]string {
+
+	return s.expectedExcess
+
+}
+
+func (s *MintQuote) GetExpectedExcessStr() (string, error) {
+
+	expectedExcess, err := json.Marshal(s.expectedExcess)
+	return string(expectedExcess), err
+
+}
+
+func (s *MintQuote) GetWorstCaseExcess() map[int]string {
+
+	return s.worstCaseExcess
+
+}
+
+func (s *MintQuote) GetWorstCaseExcessStr() (string, error) {
+
+	worstCaseExcess, err := json.Marshal(s.worstCaseExcess)
+	return string(worstCaseExcess), err
+
+}
+
+// PrepareRedeemExcessTransactions returns one redeem group per asset that userAddress has excess of in the pool,
+// e.g. to collect the excess of a mint or a swap once it is confirmed. The plan is empty when there is no excess.
+func (s *Pool) PrepareRedeemExcessTransactions(userAddress string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	excessAmounts, err := s.FetchExcessAmounts(userAddress)
+	if err != nil {
+		return
+	}
+
+	assets := map[int]*types.Asset{
+		s.Asset1.Id:         s.Asset1,
+		s.Asset2.Id:         s.Asset2,
+		s.LiquidityAsset.Id: s.LiquidityAsset,
+	}
+
+	assetIDs := make([]int, 0, len(excessAmounts))
+	for assetID := range excessAmounts {
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Ints(assetIDs)
+
+	// a lease can only be used by one group, so the redeem groups are prepared without it
+	options = withoutLease(options)
+
+	plan = utils.NewTransactionPlan()
+
+	for _, assetID := range assetIDs {
+
+		asset, ok := assets[assetID]
+		if !ok || utils.NewBigIntString(excessAmounts[assetID]).Sign() == 0 {
+			continue
+		}
+
+		amountOut := &types.AssetAmount{Asset: asset, Amount: excessAmounts[assetID]}
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = s.PrepareRedeemTransactions(amountOut, userAddress, options)
+		if err != nil {
+			return
+		}
+
+		plan.AddStep("redeem", fmt.Sprintf("redeem %s", amountOut.String()), txnGroup)
+
+	}
+
+	return
+
+}
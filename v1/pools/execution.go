@@ -0,0 +1,417 @@
+package pools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// ExecutionReport compares a confirmed swap or mint with its quote.
+type ExecutionReport struct {
+	// Operation is "swap" or "mint".
+	Operation   string `json:"operation"`
+	PoolAddress string `json:"pool-address"`
+	UserAddress string `json:"user-address"`
+	// TxId is the ID of the validator app call of the group.
+	TxId           string         `json:"tx-id"`
+	ConfirmedRound int            `json:"confirmed-round"`
+	amountsIn      map[int]string // map[asset.id][amount transferred to the pool]
+	amountsOut     map[int]string // map[asset.id][amount transferred by the pool]
+	excess         map[int]string // map[asset.id][excess credited by the group]
+	// Fees is the Tinyman fee payment plus the fees of the transactions not sent by the pool, in microAlgos,
+	// whether the user or a fee payer paid them.
+	Fees string `json:"fees"`
+	// QuotedAmount is the quoted amount the slippage is measured on: the output of a fixed-input swap,
+	// the input of a fixed-output swap and the liquidity of a mint. RealizedAmount is the same amount as computed
+	// by the validator, i.e. the transferred amount corrected by the excess.
+	QuotedAmount   *types.AssetAmount `json:"quoted-amount"`
+	RealizedAmount *types.AssetAmount `json:"realized-amount"`
+	// RealizedSlippage is the shortfall of RealizedAmount relative to QuotedAmount, negative when the trade did better.
+	RealizedSlippage float64 `json:"realized-slippage"`
+	// Asset1Reserves, Asset2Reserves and IssuedLiquidity are the pool state after the trade.
+	Asset1Reserves  string `json:"asset1-reserves"`
+	Asset2Reserves  string `json:"asset2-reserves"`
+	IssuedLiquidity string `json:"issued-liquidity"`
+}
+
+func (s *ExecutionReport) GetAmountsIn() map[int]string {
+	return s.amountsIn
+}
+
+func (s *ExecutionReport) GetAmountsOut() map[int]string {
+	return s.amountsOut
+}
+
+func (s *ExecutionReport) GetExcess() map[int]string {
+	return s.excess
+}
+
+// JSON is the report including its amounts in, amounts out and excess.
+func (s *ExecutionReport) JSON() (reportStr string, err error) {
+
+	type report ExecutionReport
+
+	reportBytes, err := json.Marshal(struct {
+		*report
+		AmountsIn  map[int]string `json:"amounts-in"`
+		AmountsOut map[int]string `json:"amounts-out"`
+		Excess     map[int]string `json:"excess"`
+	}{(*report)(s), s.amountsIn, s.amountsOut, s.excess})
+	if err != nil {
+		return
+	}
+
+	reportStr = string(reportBytes)
+	return
+
+}
+
+// setRealized sets the realized amount and slippage. shortfall is realized - quoted for an input amount
+// and quoted - realized for an output amount.
+func (s *ExecutionReport) setRealized(quoted *types.AssetAmount, realized *big.Int, input bool) {
+
+	s.QuotedAmount = quoted
+	s.RealizedAmount = &types.AssetAmount{Asset: quoted.Asset, Amount: realized.String()}
+
+	quotedAmount := utils.NewBigIntString(quoted.Amount)
+	if quotedAmount.Sign() == 0 {
+		return
+	}
+
+	shortfall := new(big.Int).Sub(quotedAmount, realized)
+	if input {
+		shortfall.Neg(shortfall)
+	}
+
+	s.RealizedSlippage, _ = new(big.Float).Quo(new(big.Float).SetInt(shortfall), new(big.Float).SetInt(quotedAmount)).Float64()
+
+}
+
+func amountOf(amounts map[int]string, assetID int) *big.Int {
+	return utils.NewBigIntString(amounts[assetID])
+}
+
+// validatorAppCall returns the ID of the validator app call of txns and the user it is made for, the first account
+// of the call. The user is not always the sender of the first transaction, e.g. with a fee payer.
+func (s *Pool) validatorAppCall(txns []algoTypes.Transaction) (txID, user string, err error) {
+
+	for _, txn := range txns {
+		if txn.Type == algoTypes.ApplicationCallTx && int(txn.ApplicationID) == s.ValidatorAppId && len(txn.Accounts) > 0 {
+			return crypto.TransactionIDString(txn), txn.Accounts[0].String(), nil
+		}
+	}
+
+	err = fmt.Errorf("transaction group has no call of validator app %d", s.ValidatorAppId)
+
+	return
+
+}
+
+// executionReport reads the transfers of txns and the local state deltas of its confirmed validator app call.
+func (s *Pool) executionReport(operation string, txns []algoTypes.Transaction, appCall models.Transaction, excessBefore map[int]string) (report *ExecutionReport, err error) {
+
+	poolAddress, err := s.Address()
+	if err != nil {
+		return
+	}
+
+	txID, user, err := s.validatorAppCall(txns)
+	if err != nil {
+		return
+	}
+
+	report = &ExecutionReport{
+		Operation:       operation,
+		PoolAddress:     poolAddress,
+		UserAddress:     user,
+		TxId:            txID,
+		ConfirmedRound:  int(appCall.ConfirmedRound),
+		amountsIn:       make(map[int]string),
+		amountsOut:      make(map[int]string),
+		excess:          map[int]string{s.Asset1.Id: "0", s.Asset2.Id: "0", s.LiquidityAsset.Id: "0"},
+		Asset1Reserves:  s.Asset1Reserves,
+		Asset2Reserves:  s.Asset2Reserves,
+		IssuedLiquidity: s.IssuedLiquidity,
+	}
+
+	fees := new(big.Int)
+	amountsIn := make(map[int]*big.Int)
+	amountsOut := make(map[int]*big.Int)
+
+	for _, txn := range txns {
+
+		sender := txn.Sender.String()
+		if sender != poolAddress {
+			fees.Add(fees, new(big.Int).SetUint64(uint64(txn.Fee)))
+		}
+
+		var assetID int
+		var receiver string
+		var amount uint64
+
+		switch txn.Type {
+		case algoTypes.PaymentTx:
+			receiver, amount = txn.Receiver.String(), uint64(txn.Amount)
+		case algoTypes.AssetTransferTx:
+			assetID, receiver, amount = int(txn.XferAsset), txn.AssetReceiver.String(), txn.AssetAmount
+		default:
+			continue
+		}
+
+		// the Tinyman fee payment pays the fees of the pool transactions, the user or a fee payer sends it
+		if receiver == poolAddress && txn.Type == algoTypes.PaymentTx && string(txn.Note) == "fee" {
+			fees.Add(fees, new(big.Int).SetUint64(amount))
+			continue
+		}
+
+		if sender == user && receiver == poolAddress {
+
+			if amountsIn[assetID] == nil {
+				amountsIn[assetID] = new(big.Int)
+			}
+			amountsIn[assetID].Add(amountsIn[assetID], new(big.Int).SetUint64(amount))
+
+		} else if sender == poolAddress && receiver == user {
+
+			if amountsOut[assetID] == nil {
+				amountsOut[assetID] = new(big.Int)
+			}
+			amountsOut[assetID].Add(amountsOut[assetID], new(big.Int).SetUint64(amount))
+
+		}
+
+	}
+
+	report.Fees = fees.String()
+	for assetID, amount := range amountsIn {
+		report.amountsIn[assetID] = amount.String()
+	}
+	for assetID, amount := range amountsOut {
+		report.amountsOut[assetID] = amount.String()
+	}
+
+	for _, accountDelta := range appCall.LocalStateDelta {
+
+		var values map[string]uint64
+		values, err = state.DecodeDelta(accountDelta.Delta)
+		if err != nil {
+			return
+		}
+
+		switch accountDelta.Address {
+
+		case poolAddress:
+
+			if value, ok := values[state.ASSET_1_RESERVES_KEY]; ok {
+				report.Asset1Reserves = new(big.Int).SetUint64(value).String()
+			}
+			if value, ok := values[state.ASSET_2_RESERVES_KEY]; ok {
+				report.Asset2Reserves = new(big.Int).SetUint64(value).String()
+			}
+			if value, ok := values[state.ISSUED_LIQUIDITY_KEY]; ok {
+				report.IssuedLiquidity = new(big.Int).SetUint64(value).String()
+			}
+
+		case user:
+
+			for assetID := range report.excess {
+
+				var key []byte
+				key, err = state.ExcessKey(poolAddress, assetID)
+				if err != nil {
+					return
+				}
+
+				if value, ok := values[string(key)]; ok {
+					created := new(big.Int).Sub(new(big.Int).SetUint64(value), amountOf(excessBefore, assetID))
+					report.excess[assetID] = nonNegative(created).String()
+				}
+
+			}
+
+		}
+
+	}
+
+	return
+
+}
+
+// fetchExecutionReport fetches the result of the confirmed validator app call of txnGroup.
+func (s *Pool) fetchExecutionReport(operation string, txnGroup *utils.TransactionGroup, excessBeforeStr string) (report *ExecutionReport, err error) {
+
+	excessBefore := make(map[int]string)
+	if len(excessBeforeStr) > 0 {
+		err = json.Unmarshal([]byte(excessBeforeStr), &excessBefore)
+		if err != nil {
+			return
+		}
+	}
+
+	txns := txnGroup.GetTransactions()
+
+	appCallID, _, err := s.validatorAppCall(txns)
+	if err != nil {
+		return
+	}
+
+	appCall, err := s.Client.LookupTransaction(appCallID)
+	if err != nil {
+
+		// the indexer lags behind algod, which only keeps the transaction for a few rounds after its confirmation
+		pendingInfo, pendingErr := s.Client.PendingTransactionInformation(appCallID)
+		if pendingErr != nil {
+			return
+		}
+
+		appCall = models.Transaction{ConfirmedRound: pendingInfo.ConfirmedRound, LocalStateDelta: pendingInfo.LocalStateDelta}
+		err = nil
+
+	}
+
+	if appCall.ConfirmedRound == 0 {
+		err = fmt.Errorf("transaction %s is not confirmed", appCallID)
+		return
+	}
+
+	return s.executionReport(operation, txns, appCall, excessBefore)
+
+}
+
+// FetchSwapExecutionReport returns the execution report of the confirmed swap group prepared from quote.
+// excessBeforeStr is FetchExcessAmountsStr of the swapper before the swap, empty when the swapper had no excess.
+func (s *Pool) FetchSwapExecutionReport(txnGroup *utils.TransactionGroup, quote *SwapQuote, excessBeforeStr string) (report *ExecutionReport, err error) {
+
+	report, err = s.fetchExecutionReport("swap", txnGroup, excessBeforeStr)
+	if err != nil {
+		return
+	}
+
+	setSwapRealized(report, quote)
+
+	return
+
+}
+
+func setSwapRealized(report *ExecutionReport, quote *SwapQuote) {
+
+	if quote.SwapType == "fixed-output" {
+		assetID := quote.AmountIn.Asset.Id
+		realized := new(big.Int).Sub(amountOf(report.amountsIn, assetID), amountOf(report.excess, assetID))
+		report.setRealized(quote.AmountIn, realized, true)
+		return
+	}
+
+	assetID := quote.AmountOut.Asset.Id
+	realized := new(big.Int).Add(amountOf(report.amountsOut, assetID), amountOf(report.excess, assetID))
+	report.setRealized(quote.AmountOut, realized, false)
+
+}
+
+// FetchMintExecutionReport returns the execution report of the confirmed mint group prepared from quote.
+// excessBeforeStr is FetchExcessAmountsStr of the pooler before the mint, empty when the pooler had no excess.
+func (s *Pool) FetchMintExecutionReport(txnGroup *utils.TransactionGroup, quote *MintQuote, excessBeforeStr string) (report *ExecutionReport, err error) {
+
+	report, err = s.fetchExecutionReport("mint", txnGroup, excessBeforeStr)
+	if err != nil {
+		return
+	}
+
+	setMintRealized(report, quote)
+
+	return
+
+}
+
+func setMintRealized(report *ExecutionReport, quote *MintQuote) {
+
+	assetID := quote.LiquidityAssetAmount.Asset.Id
+	realized := new(big.Int).Add(amountOf(report.amountsOut, assetID), amountOf(report.excess, assetID))
+	report.setRealized(quote.LiquidityAssetAmount, realized, false)
+
+}
+
+// submitWithReport submits the signed txnGroup, waits for its confirmation and returns its execution report.
+func (s *Pool) submitWithReport(operation string, txnGroup *utils.TransactionGroup) (report *ExecutionReport, err error) {
+
+	_, user, err := s.validatorAppCall(txnGroup.GetTransactions())
+	if err != nil {
+		return
+	}
+
+	excessBeforeStr, err := s.FetchExcessAmountsStr(user)
+	if err != nil {
+		return
+	}
+
+	_, err = s.Client.Submit(txnGroup, true)
+	if err != nil {
+		return
+	}
+
+	return s.fetchExecutionReport(operation, txnGroup, excessBeforeStr)
+
+}
+
+// SubmitSwapWithReport submits the signed swap group prepared from quote, waits for its confirmation and
+// returns its execution report.
+func (s *Pool) SubmitSwapWithReport(txnGroup *utils.TransactionGroup, quote *SwapQuote) (report *ExecutionReport, err error) {
+
+	report, err = s.submitWithReport("swap", txnGroup)
+	if err != nil {
+		return
+	}
+
+	setSwapRealized(report, quote)
+
+	return
+
+}
+
+// SubmitMintWithReport submits the signed mint group prepared from quote, waits for its confirmation and
+// returns its execution report.
+func (s *Pool) SubmitMintWithReport(txnGroup *utils.TransactionGroup, quote *MintQuote) (report *ExecutionReport, err error) {
+
+	report, err = s.submitWithReport("mint", txnGroup)
+	if err != nil {
+		return
+	}
+
+	setMintRealized(report, quote)
+
+	return
+
+}
+
+// SlippageStats aggregates the realized slippage of execution reports.
+type SlippageStats struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+func (s *SlippageStats) Add(report *ExecutionReport) {
+
+	slippage := report.RealizedSlippage
+
+	if s.Count == 0 || slippage < s.Min {
+		s.Min = slippage
+	}
+
+	if s.Count == 0 || slippage > s.Max {
+		s.Max = slippage
+	}
+
+	s.Count++
+	s.Mean += (slippage - s.Mean) / float64(s.Count)
+
+}
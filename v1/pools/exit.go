@@ -0,0 +1,246 @@
+package pools
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/optout"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type ExitPlanResult struct {
+	// Plan has the steps to submit in order, stop at the first step that fails.
+	Plan *utils.TransactionPlan `json:"-"`
+	// Skipped has the excess amounts that are not redeemed and the liquidity assets that are not burnt.
+	Skipped []*SkippedRedeem `json:"skipped"`
+	// Dust has the excess amounts that are worth less than the fees of redeeming them, the opt out forfeits them.
+	Dust []*SkippedRedeem `json:"dust"`
+	// Complete is false when Plan does not opt out of the validator app yet.
+	// Prepare the exit plan again once Plan is submitted.
+	Complete bool `json:"complete"`
+}
+
+func (s *ExitPlanResult) SkippedLen() int {
+	return len(s.Skipped)
+}
+
+func (s *ExitPlanResult) GetSkipped(index int) *SkippedRedeem {
+	return s.Skipped[index]
+}
+
+func (s *ExitPlanResult) DustLen() int {
+	return len(s.Dust)
+}
+
+func (s *ExitPlanResult) GetDust(index int) *SkippedRedeem {
+	return s.Dust[index]
+}
+
+func isLiquidityAsset(asset *types.Asset) bool {
+	return asset.UnitName == constants.LIQUIDITY_ASSET_UNIT_NAME_V1_0 || asset.UnitName == constants.LIQUIDITY_ASSET_UNIT_NAME_V1_1
+}
+
+// PrepareExitPlan prepares the steps to leave Tinyman: burn the liquidity assets of userAddress, redeem its excess amounts,
+// close out its liquidity asset holdings and opt out of the validator app.
+//
+// The burns transfer the quoted amounts with slippage applied. A burn stores the difference between the pool state
+// at submission and those amounts as new excess amounts, which can only be redeemed after the burn is confirmed.
+// When the plan burns, it does not opt out yet and Complete is false: submit the plan and prepare the exit plan again
+// to redeem the rest and opt out, or use ExecuteExitPlan.
+// The validator app rejects CloseOut, so opting out clears the local state and forfeits the excess amounts
+// that are not redeemed. Unless force is set, the plan does not opt out while any excess amount is skipped,
+// the Dust worth less than the fees of redeeming it is forfeited.
+// progress may be nil.
+func PrepareExitPlan(tinymanClient *client.TinymanClient, userAddress string, slippage float64, force bool, progress RedeemProgress, options *types.TxnOptions) (result *ExitPlanResult, err error) {
+
+	if len(userAddress) == 0 {
+		userAddress = tinymanClient.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := tinymanClient.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	// the lease of options is only used by the opt out, the last step of the plan
+	stepOptions := withoutLease(options)
+
+	account, err := tinymanClient.AccountInformation(user.String())
+	if err != nil {
+		return
+	}
+
+	result = &ExitPlanResult{Plan: utils.NewTransactionPlan()}
+
+	burns := 0
+	var closeOuts []*utils.TransactionGroup
+
+	for _, holding := range account.Assets {
+
+		var asset *types.Asset
+		asset, err = tinymanClient.FetchAsset(int(holding.AssetId))
+		if err != nil {
+			return
+		}
+
+		if !isLiquidityAsset(asset) {
+			continue
+		}
+
+		var poolAddress string
+		poolAddress, err = tinymanClient.FetchAssetCreator(asset.Id)
+		if err != nil {
+			return
+		}
+
+		amount := fmt.Sprint(holding.Amount)
+
+		_, poolAccount, lookupErr := tinymanClient.LookupAccountByID(poolAddress)
+		if lookupErr != nil {
+			err = lookupErr
+			return
+		}
+
+		pool, poolErr := NewPoolFromAccountInfo(poolAccount, tinymanClient)
+		if poolErr != nil || pool.LiquidityAsset == nil || pool.LiquidityAsset.Id != asset.Id {
+			result.Skipped = append(result.Skipped, &SkippedRedeem{0, poolAddress, asset.Id, amount, "contract definition of the pool is not available"})
+			continue
+		}
+
+		if holding.Amount > 0 {
+
+			var quote *BurnQuote
+			quote, err = pool.FetchBurnQuote(pool.LiquidityAsset.Call(amount), slippage)
+			if err != nil {
+				return
+			}
+
+			var txnGroup *utils.TransactionGroup
+			txnGroup, err = pool.PrepareBurnTransactionsFromQuote(quote, user.String(), stepOptions)
+			if err != nil {
+				return
+			}
+
+			result.Plan.AddStep("burn", fmt.Sprintf("burn %s of pool %s", quote.LiquidityAssetAmount.String(), poolAddress), txnGroup)
+			burns++
+
+		}
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = optout.PrepareAssetCloseOutTransactions(asset.Id, poolAddress, user.String(), suggestedParams, stepOptions)
+		if err != nil {
+			return
+		}
+
+		closeOuts = append(closeOuts, txnGroup)
+
+	}
+
+	redeemAll, err := PrepareRedeemAllTransactions(tinymanClient, user.String(), progress, stepOptions)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < redeemAll.Plan.Len(); i++ {
+		step := redeemAll.Plan.GetStep(i)
+		result.Plan.AddStep(step.Name, step.Description, step.GetTransactionGroup())
+	}
+
+	result.Skipped = append(result.Skipped, redeemAll.Skipped...)
+	result.Dust = redeemAll.Dust
+
+	for _, txnGroup := range closeOuts {
+		result.Plan.AddStep("closeout", fmt.Sprintf("close out asset %d", txnGroup.GetTransactions()[0].XferAsset), txnGroup)
+	}
+
+	optedIn := false
+	for _, a := range account.AppsLocalState {
+		if a.Id == uint64(tinymanClient.ValidatorAppId) {
+			optedIn = true
+		}
+	}
+
+	// the dust is not worth redeeming, it does not hold the opt out back
+	forfeits := false
+	for _, skipped := range redeemAll.Skipped {
+		if skipped.ValidatorAppId == tinymanClient.ValidatorAppId {
+			forfeits = true
+		}
+	}
+
+	if !optedIn {
+		result.Complete = burns == 0
+		return
+	}
+
+	if burns > 0 || (forfeits && !force) {
+		return
+	}
+
+	// the excess amounts are redeemed by the previous steps
+	txnGroup, err := optout.GetOptoutTransactions(tinymanClient, user.String(), tinymanClient.ValidatorAppId, true, options)
+	if err != nil {
+		return
+	}
+
+	result.Plan.AddStep("optout", fmt.Sprintf("opt out of validator app %d", tinymanClient.ValidatorAppId), txnGroup)
+	result.Complete = true
+
+	return
+
+}
+
+// ExecuteExitPlan signs the steps of PrepareExitPlan with signer and submits them in order, waiting for each one.
+// When the plan burns, the excess amounts are scanned again once the burns are confirmed and the exit plan prepared
+// again, so the excess amounts left by the burns are redeemed before opting out. result is the last plan submitted,
+// Complete is false when it could still not opt out, e.g. an excess amount is skipped and force is not set.
+// progress may be nil.
+func ExecuteExitPlan(tinymanClient *client.TinymanClient, userAddress string, slippage float64, force bool, signer utils.Signer, progress RedeemProgress, options *types.TxnOptions) (result *ExitPlanResult, err error) {
+
+	for {
+
+		result, err = PrepareExitPlan(tinymanClient, userAddress, slippage, force, progress, options)
+		if err != nil {
+			return
+		}
+
+		burns := 0
+
+		for i := 0; i < result.Plan.Len(); i++ {
+
+			step := result.Plan.GetStep(i)
+
+			err = step.GetTransactionGroup().Sign(signer)
+			if err != nil {
+				return
+			}
+
+			_, err = tinymanClient.Submit(step.GetTransactionGroup(), true)
+			if err != nil {
+				err = fmt.Errorf("step %d (%s) failed: %s", i, step.Name, err)
+				return
+			}
+
+			if step.Name == "burn" {
+				burns++
+			}
+
+		}
+
+		// the liquidity is all burnt, the next plan has no burn
+		if result.Complete || burns == 0 {
+			return
+		}
+
+	}
+
+}
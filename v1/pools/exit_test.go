@@ -0,0 +1,80 @@
+package pools
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestPrepareExitPlanDust(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	user := crypto.GenerateAccount().Address.String()
+	validatorAppID := constants.TESTNET_VALIDATOR_APP_ID_V1_1
+
+	poolAddress, err := contracts.PoolAddress(validatorAppID, 2, 0)
+	assert.Nil(t, err)
+
+	// 100 microAlgos of excess, less than the 4000 microAlgos of fees of redeeming it
+	userState := &state.UserState{ExcessAmounts: map[string]map[int]uint64{poolAddress: {0: 100}}}
+	keyValues, err := userState.Encode()
+	assert.Nil(t, err)
+
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).
+		Reply(200).JSON(models.Account{
+		Address:        user,
+		AppsLocalState: []models.ApplicationLocalState{{Id: uint64(validatorAppID), KeyValue: keyValues}},
+	})
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        user,
+			AppsLocalState: []models.ApplicationLocalState{{Id: uint64(validatorAppID), KeyValue: keyValues}},
+		},
+	})
+
+	poolState := &state.PoolState{Asset1Id: 2, Asset2Id: 0, Asset1Reserves: 1000000, Asset2Reserves: 1000000, IssuedLiquidity: 1000000}
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", poolAddress)).
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        poolAddress,
+			AppsLocalState: []models.ApplicationLocalState{{Id: uint64(validatorAppID), KeyValue: poolState.Encode()}},
+			CreatedAssets:  []models.Asset{{Index: 3}},
+		},
+	})
+
+	tinymanClient, err := client.NewTinymanTestnetClient(algodURL, indexerURL, user)
+	assert.Nil(t, err)
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}}
+
+	result, err := PrepareExitPlan(tinymanClient, user, 0.01, false, nil, options)
+	assert.Nil(t, err)
+
+	// the dust is reported on its own and does not hold the opt out back
+	assert.Equal(t, 0, result.SkippedLen())
+	assert.Equal(t, 1, result.DustLen())
+	assert.Equal(t, poolAddress, result.GetDust(0).PoolAddress)
+	assert.Equal(t, "100", result.GetDust(0).Amount)
+
+	assert.True(t, result.Complete)
+	assert.Equal(t, 1, result.Plan.Len())
+	assert.Equal(t, "optout", result.Plan.GetStep(0).Name)
+
+}
@@ -0,0 +1,111 @@
+package pools
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+)
+
+// quoteSource returns the round of the pool state, the current time and the pool address recorded in a quote.
+func (s *Pool) quoteSource() (round int, timestamp int64, poolAddress string, err error) {
+
+	poolAddress, err = s.Address()
+	if err != nil {
+		return
+	}
+
+	round = s.LastRefreshedRound
+	timestamp = time.Now().Unix()
+
+	return
+
+}
+
+// StaleQuoteError is the error of preparing a quote that is more than MaxQuoteAge rounds old.
+type StaleQuoteError struct {
+	Round  int `json:"round"`
+	Age    int `json:"age"`
+	MaxAge int `json:"max-age"`
+}
+
+func (s *StaleQuoteError) Error() string {
+	return fmt.Sprintf("quote of round %d is %d rounds old, the max quote age is %d rounds", s.Round, s.Age, s.MaxAge)
+}
+
+// AsStaleQuoteError returns err as a StaleQuoteError, nil if it is not one.
+func AsStaleQuoteError(err error) *StaleQuoteError {
+
+	var staleErr *StaleQuoteError
+	if errors.As(err, &staleErr) {
+		return staleErr
+	}
+
+	return nil
+
+}
+
+// bindQuote checks that a quote of the pool state at round is at most options.MaxQuoteAge rounds old and returns
+// options with a last valid round at most that age, so the group can not be executed once the quote is stale.
+// Quotes without a round, e.g. built by hand, are not bound.
+func (s *Pool) bindQuote(round int, poolAddress string, options *types.TxnOptions) (boundOptions *types.TxnOptions, err error) {
+
+	if len(poolAddress) > 0 {
+
+		var address string
+		address, err = s.Address()
+		if err != nil {
+			return
+		}
+
+		if poolAddress != address {
+			err = fmt.Errorf("quote is of pool %s, not %s", poolAddress, address)
+			return
+		}
+
+	}
+
+	maxAge := types.DEFAULT_MAX_QUOTE_AGE
+	if options != nil && options.MaxQuoteAge != 0 {
+		maxAge = options.MaxQuoteAge
+	}
+
+	if round == 0 || maxAge < 0 {
+		return options, nil
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	deadline := round + maxAge
+
+	if suggestedParams.FirstRoundValid > deadline {
+		err = &StaleQuoteError{Round: round, Age: suggestedParams.FirstRoundValid - round, MaxAge: maxAge}
+		return
+	}
+
+	optionsCopy := types.TxnOptions{}
+	if options != nil {
+		optionsCopy = *options
+	}
+
+	params := *suggestedParams
+
+	// a validity window replaces the last valid round of the suggested params
+	if optionsCopy.ValidityWindow > 0 && params.FirstRoundValid+optionsCopy.ValidityWindow > deadline {
+		optionsCopy.ValidityWindow = deadline - params.FirstRoundValid
+	}
+	if params.LastRoundValid > deadline {
+		params.LastRoundValid = deadline
+	}
+
+	optionsCopy.SuggestedParams = &params
+
+	boundOptions = &optionsCopy
+
+	return
+
+}
@@ -0,0 +1,186 @@
+package pools
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+)
+
+// ceilQuo returns x / y rounded up.
+func ceilQuo(x, y *big.Int) *big.Int {
+
+	quo, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+
+	return quo
+
+}
+
+// burnAmounts returns the amounts of the pool assets the validator transfers for burning liquidity,
+// rounded down like on chain.
+func (s *Pool) burnAmounts(liquidity *big.Int) (asset1Amount, asset2Amount *big.Int) {
+
+	issuedLiquidity := utils.NewBigIntString(s.IssuedLiquidity)
+
+	asset1Amount = new(big.Int).Mul(liquidity, utils.NewBigIntString(s.Asset1Reserves))
+	asset1Amount.Quo(asset1Amount, issuedLiquidity)
+
+	asset2Amount = new(big.Int).Mul(liquidity, utils.NewBigIntString(s.Asset2Reserves))
+	asset2Amount.Quo(asset2Amount, issuedLiquidity)
+
+	return
+
+}
+
+// mintLiquidity returns the liquidity the validator issues for depositing asset1Amount and asset2Amount in a pool
+// that has liquidity, rounded down like on chain.
+func (s *Pool) mintLiquidity(asset1Amount, asset2Amount *big.Int) *big.Int {
+
+	issuedLiquidity := utils.NewBigIntString(s.IssuedLiquidity)
+
+	liquidity1 := new(big.Int).Mul(asset1Amount, issuedLiquidity)
+	liquidity1.Quo(liquidity1, utils.NewBigIntString(s.Asset1Reserves))
+
+	liquidity2 := new(big.Int).Mul(asset2Amount, issuedLiquidity)
+	liquidity2.Quo(liquidity2, utils.NewBigIntString(s.Asset2Reserves))
+
+	if liquidity1.Cmp(liquidity2) < 0 {
+		return liquidity1
+	}
+
+	return liquidity2
+
+}
+
+// burnQuoteForAssetAmount is FetchBurnQuoteForAssetAmount at the current pool state.
+func (s *Pool) burnQuoteForAssetAmount(assetOut *types.AssetAmount, slippage float64) (quote *BurnQuote, err error) {
+
+	var reserves *big.Int
+	if assetOut.Asset.Id == s.Asset1.Id {
+		reserves = utils.NewBigIntString(s.Asset1Reserves)
+	} else if assetOut.Asset.Id == s.Asset2.Id {
+		reserves = utils.NewBigIntString(s.Asset2Reserves)
+	} else {
+		err = fmt.Errorf("asset %d is not in the pool", assetOut.Asset.Id)
+		return
+	}
+
+	amountOut := utils.NewBigIntString(assetOut.Amount)
+	if amountOut.Sign() <= 0 {
+		err = fmt.Errorf("amount out must be positive")
+		return
+	}
+
+	issuedLiquidity := utils.NewBigIntString(s.IssuedLiquidity)
+
+	// the smallest liquidity whose burn transfers at least amountOut: floor(liquidity * reserves / issued) >= amountOut
+	liquidity := ceilQuo(new(big.Int).Mul(amountOut, issuedLiquidity), reserves)
+
+	// the locked liquidity can never be burnt
+	if liquidity.Cmp(new(big.Int).Sub(issuedLiquidity, big.NewInt(constants.LOCKED_LIQUIDITY))) > 0 {
+		err = &PoolStatusError{Status: s.Status, Operation: "burn", Reason: "pool has not enough liquidity"}
+		return
+	}
+
+	asset1Amount, asset2Amount := s.burnAmounts(liquidity)
+
+	quote = &BurnQuote{
+		amountsOut: map[int]string{
+			s.Asset1.Id: asset1Amount.String(),
+			s.Asset2.Id: asset2Amount.String(),
+		},
+		LiquidityAssetAmount: &types.AssetAmount{Asset: s.LiquidityAsset, Amount: liquidity.String()},
+		Slippage:             slippage,
+	}
+
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
+	return
+
+}
+
+// FetchBurnQuoteForAssetAmount returns the quote of burning the least liquidity that returns at least assetOut,
+// e.g. how much liquidity to burn to withdraw exactly 500 USDC. The other asset is returned in proportion.
+func (s *Pool) FetchBurnQuoteForAssetAmount(assetOut *types.AssetAmount, slippage float64) (quote *BurnQuote, err error) {
+
+	err = s.Refresh()
+	if err != nil {
+		return
+	}
+
+	err = s.checkStatus("burn", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
+
+	return s.burnQuoteForAssetAmount(assetOut, slippage)
+
+}
+
+// mintQuoteForLiquidity is FetchMintQuoteForLiquidity at the current pool state.
+func (s *Pool) mintQuoteForLiquidity(liquidityAssetAmount *types.AssetAmount, slippage float64) (quote *MintQuote, err error) {
+
+	if liquidityAssetAmount.Asset.Id != s.LiquidityAsset.Id {
+		err = fmt.Errorf("asset %d is not the liquidity asset of the pool", liquidityAssetAmount.Asset.Id)
+		return
+	}
+
+	liquidity := utils.NewBigIntString(liquidityAssetAmount.Amount)
+	if liquidity.Sign() <= 0 {
+		err = fmt.Errorf("liquidity asset amount must be positive")
+		return
+	}
+
+	issuedLiquidity := utils.NewBigIntString(s.IssuedLiquidity)
+
+	// the smallest deposits that issue at least liquidity: floor(amount * issued / reserves) >= liquidity
+	asset1Amount := ceilQuo(new(big.Int).Mul(liquidity, utils.NewBigIntString(s.Asset1Reserves)), issuedLiquidity)
+	asset2Amount := ceilQuo(new(big.Int).Mul(liquidity, utils.NewBigIntString(s.Asset2Reserves)), issuedLiquidity)
+
+	quote = &MintQuote{
+		amountsIn: map[int]string{
+			s.Asset1.Id: asset1Amount.String(),
+			s.Asset2.Id: asset2Amount.String(),
+		},
+		LiquidityAssetAmount: &types.AssetAmount{Asset: s.LiquidityAsset, Amount: s.mintLiquidity(asset1Amount, asset2Amount).String()},
+		Slippage:             slippage,
+	}
+
+	err = s.setMintExcess(quote)
+	if err != nil {
+		return
+	}
+
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
+	return
+
+}
+
+// FetchMintQuoteForLiquidity returns the quote of the least deposits that issue at least liquidityAssetAmount,
+// e.g. what to deposit for 1% of the issued liquidity. The first mint of a pool sets its price, use CreatePool instead.
+func (s *Pool) FetchMintQuoteForLiquidity(liquidityAssetAmount *types.AssetAmount, slippage float64) (quote *MintQuote, err error) {
+
+	err = s.Refresh()
+	if err != nil {
+		return
+	}
+
+	err = s.checkStatus("mint", POOL_STATUS_NOT_CREATED)
+	if err != nil {
+		return
+	}
+
+	if s.Status == POOL_STATUS_EMPTY {
+		err = &PoolStatusError{Status: s.Status, Operation: "mint", Reason: "amounts required for both assets for first mint"}
+		return
+	}
+
+	return s.mintQuoteForLiquidity(liquidityAssetAmount, slippage)
+
+}
@@ -0,0 +1,232 @@
+package pools
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type MigrationPlanResult struct {
+	// Plan has the steps to submit in order, stop at the first step that fails.
+	Plan *utils.TransactionPlan `json:"-"`
+	// Skipped has the v1.0 liquidity assets that are not migrated and the excess amounts that are not redeemed.
+	Skipped []*SkippedRedeem `json:"skipped"`
+	// Complete is false when Plan leaves work for later, e.g. a pool is bootstrapped before liquidity moves to it
+	// or the burns and mints leave excess amounts. Prepare the migration plan again once Plan is submitted.
+	Complete bool `json:"complete"`
+}
+
+func (s *MigrationPlanResult) SkippedLen() int {
+	return len(s.Skipped)
+}
+
+func (s *MigrationPlanResult) GetSkipped(index int) *SkippedRedeem {
+	return s.Skipped[index]
+}
+
+// legacyValidatorAppId returns the v1.0 validator of the network of validatorAppId, 0 if there is none.
+func legacyValidatorAppId(validatorAppId int) int {
+
+	switch validatorAppId {
+	case constants.TESTNET_VALIDATOR_APP_ID_V1_1:
+		return constants.TESTNET_VALIDATOR_APP_ID_V1_0
+	case constants.MAINNET_VALIDATOR_APP_ID_V1_1:
+		return constants.MAINNET_VALIDATOR_APP_ID_V1_0
+	}
+
+	return 0
+
+}
+
+// PrepareMigrationPlan moves the liquidity of userAddress from v1.0 pools to the v1.1 pools of the client validator.
+// For every v1.0 liquidity asset it burns the whole balance in the v1.0 pool and mints the burnt amounts, with slippage
+// applied, in the v1.1 pool. A v1.1 pool that does not exist is bootstrapped first and its liquidity moves on the next run,
+// because the mint needs the liquidity asset the bootstrap creates. The excess amounts already in the local state of
+// either validator are redeemed at the end.
+// The v1.0 contract definitions are not bundled with the SDK, the logicsigs of the v1.0 pools are recovered from the
+// transactions the pools signed and their liquidity is reported as skipped when that fails.
+// progress may be nil.
+func PrepareMigrationPlan(tinymanClient *client.TinymanClient, userAddress string, slippage float64, progress RedeemProgress, options *types.TxnOptions) (result *MigrationPlanResult, err error) {
+
+	legacyValidatorAppID := legacyValidatorAppId(tinymanClient.ValidatorAppId)
+	if legacyValidatorAppID == 0 {
+		err = fmt.Errorf("validator app %d has no v1.0 version to migrate from", tinymanClient.ValidatorAppId)
+		return
+	}
+
+	if len(userAddress) == 0 {
+		userAddress = tinymanClient.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	// a lease can only be used by one group, so the groups of the plan are prepared without it
+	options = withoutLease(options)
+
+	account, err := tinymanClient.AccountInformation(user.String())
+	if err != nil {
+		return
+	}
+
+	optedIn := false
+	for _, a := range account.AppsLocalState {
+		if a.Id == uint64(tinymanClient.ValidatorAppId) {
+			optedIn = true
+		}
+	}
+
+	heldAssets := make(map[int]bool)
+	for _, holding := range account.Assets {
+		heldAssets[int(holding.AssetId)] = true
+	}
+
+	result = &MigrationPlanResult{Plan: utils.NewTransactionPlan(), Complete: true}
+
+	for _, holding := range account.Assets {
+
+		if holding.Amount == 0 {
+			continue
+		}
+
+		var asset *types.Asset
+		asset, err = tinymanClient.FetchAsset(int(holding.AssetId))
+		if err != nil {
+			return
+		}
+
+		if asset.UnitName != constants.LIQUIDITY_ASSET_UNIT_NAME_V1_0 {
+			continue
+		}
+
+		var poolAddress string
+		poolAddress, err = tinymanClient.FetchAssetCreator(asset.Id)
+		if err != nil {
+			return
+		}
+
+		amount := fmt.Sprint(holding.Amount)
+
+		_, poolAccount, lookupErr := tinymanClient.LookupAccountByID(poolAddress)
+		if lookupErr != nil {
+			err = lookupErr
+			return
+		}
+
+		legacyPool, poolErr := NewPoolFromAccountInfo(poolAccount, tinymanClient)
+		if poolErr == nil && (legacyPool.ValidatorAppId != legacyValidatorAppID || legacyPool.LiquidityAsset.Id != asset.Id) {
+			poolErr = fmt.Errorf("asset %d is not the liquidity asset of a pool of validator %d", asset.Id, legacyValidatorAppID)
+		}
+		if poolErr != nil {
+			result.Skipped = append(result.Skipped, &SkippedRedeem{legacyValidatorAppID, poolAddress, asset.Id, amount, poolErr.Error()})
+			continue
+		}
+
+		var pool *Pool
+		pool, err = NewPool(tinymanClient, legacyPool.Asset1, legacyPool.Asset2, nil, true, tinymanClient.ValidatorAppId)
+		if err != nil {
+			return
+		}
+
+		if !optedIn {
+
+			var txnGroup *utils.TransactionGroup
+			txnGroup, err = tinymanClient.PrepareAppOptinTransactions(user.String(), options)
+			if err != nil {
+				return
+			}
+
+			result.Plan.AddStep("optin", fmt.Sprintf("opt into validator app %d", tinymanClient.ValidatorAppId), txnGroup)
+			optedIn = true
+
+		}
+
+		if !pool.Exists {
+
+			var txnGroup *utils.TransactionGroup
+			txnGroup, err = pool.PrepareBootstrapTransactions(user.String(), options)
+			if err != nil {
+				return
+			}
+
+			result.Plan.AddStep("bootstrap", fmt.Sprintf("create the v1.1 pool of %s and %s", pool.Asset1.UnitName, pool.Asset2.UnitName), txnGroup)
+			result.Complete = false
+			continue
+
+		}
+
+		var burnQuote *BurnQuote
+		burnQuote, err = legacyPool.FetchBurnQuote(legacyPool.LiquidityAsset.Call(amount), slippage)
+		if err != nil {
+			return
+		}
+
+		var amountsOut map[int]string
+		amountsOut, err = burnQuote.AmountsOutWithSlippage()
+		if err != nil {
+			return
+		}
+
+		var mintQuote *MintQuote
+		mintQuote, err = pool.FetchMintQuote(pool.Asset1.Call(amountsOut[pool.Asset1.Id]), pool.Asset2.Call(amountsOut[pool.Asset2.Id]), slippage)
+		if err != nil {
+			return
+		}
+
+		var burnGroup *utils.TransactionGroup
+		burnGroup, err = legacyPool.PrepareBurnTransactionsFromQuote(burnQuote, user.String(), options)
+		if err != nil {
+			return
+		}
+
+		result.Plan.AddStep("burn", fmt.Sprintf("burn %s of v1.0 pool %s", burnQuote.LiquidityAssetAmount.String(), poolAddress), burnGroup)
+
+		if !heldAssets[pool.LiquidityAsset.Id] {
+
+			var txnGroup *utils.TransactionGroup
+			txnGroup, err = tinymanClient.PrepareAssetOptinTransactions(pool.LiquidityAsset.Id, user.String(), options)
+			if err != nil {
+				return
+			}
+
+			result.Plan.AddStep("optin", fmt.Sprintf("opt into asset %d", pool.LiquidityAsset.Id), txnGroup)
+			heldAssets[pool.LiquidityAsset.Id] = true
+
+		}
+
+		var mintGroup *utils.TransactionGroup
+		mintGroup, err = pool.PrepareMintTransactionsFromQuote(mintQuote, user.String(), options)
+		if err != nil {
+			return
+		}
+
+		result.Plan.AddStep("mint", fmt.Sprintf("mint %s in v1.1 pool of %s and %s", mintQuote.LiquidityAssetAmount.String(), pool.Asset1.UnitName, pool.Asset2.UnitName), mintGroup)
+
+		// the burn and the mint leave excess amounts that are only known once they are confirmed
+		result.Complete = false
+
+	}
+
+	redeemAll, err := PrepareRedeemAllTransactions(tinymanClient, user.String(), progress, options)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < redeemAll.Plan.Len(); i++ {
+		step := redeemAll.Plan.GetStep(i)
+		result.Plan.AddStep(step.Name, step.Description, step.GetTransactionGroup())
+	}
+
+	result.Skipped = append(result.Skipped, redeemAll.Skipped...)
+	result.Skipped = append(result.Skipped, redeemAll.Dust...)
+
+	return
+
+}
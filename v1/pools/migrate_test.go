@@ -0,0 +1,183 @@
+package pools
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/bootstrap"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// legacyPoolProgram is a stand-in for a v1.0 pool logicsig, it holds the validator app and the assets.
+func legacyPoolProgram(validatorAppID, asset1ID, asset2ID int) []byte {
+
+	program := append([]byte{0x04, 0x20, 0x03}, utils.EncodeVarint(validatorAppID)...)
+	program = append(program, utils.EncodeVarint(asset1ID)...)
+	program = append(program, utils.EncodeVarint(asset2ID)...)
+
+	return program
+
+}
+
+func TestLegacyValidatorAppId(t *testing.T) {
+
+	assert.Equal(t, constants.TESTNET_VALIDATOR_APP_ID_V1_0, legacyValidatorAppId(constants.TESTNET_VALIDATOR_APP_ID_V1_1))
+	assert.Equal(t, constants.MAINNET_VALIDATOR_APP_ID_V1_0, legacyValidatorAppId(constants.MAINNET_VALIDATOR_APP_ID_V1_1))
+	assert.Equal(t, 0, legacyValidatorAppId(constants.MAINNET_VALIDATOR_APP_ID_V1_0))
+	assert.Equal(t, 0, legacyValidatorAppId(1))
+
+}
+
+func TestPrepareMigrationPlan(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	user := crypto.GenerateAccount().Address.String()
+
+	legacyProgram := legacyPoolProgram(constants.TESTNET_VALIDATOR_APP_ID_V1_0, 2, 0)
+	legacyPoolAddress := crypto.AddressFromProgram(legacyProgram).String()
+
+	tinymanClient, err := client.NewTinymanClient(algodURL, indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	_, err = PrepareMigrationPlan(tinymanClient, user, 0.01, nil, nil)
+	assert.NotNil(t, err)
+
+	tinymanClient, err = client.NewTinymanTestnetClient(algodURL, indexerURL, user)
+	assert.Nil(t, err)
+
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).
+		Reply(200).JSON(models.Account{
+		Address: user,
+		Assets: []models.AssetHolding{
+			{AssetId: 5, Amount: 100},
+			{AssetId: 6, Amount: 200},
+			{AssetId: 7, Amount: 0},
+		},
+	})
+
+	gock.New(indexerURL).Get("/v2/assets/5").Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"asset": models.Asset{Index: 5, Params: models.AssetParams{UnitName: constants.LIQUIDITY_ASSET_UNIT_NAME_V1_0, Creator: legacyPoolAddress}},
+	})
+
+	gock.New(indexerURL).Get("/v2/assets/6").
+		Reply(200).JSON(map[string]interface{}{
+		"asset": models.Asset{Index: 6, Params: models.AssetParams{UnitName: "USDC"}},
+	})
+
+	poolState := &state.PoolState{Asset1Id: 2, Asset2Id: 0, Asset1Reserves: 1000, Asset2Reserves: 1000, IssuedLiquidity: 1000}
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", legacyPoolAddress)).
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        legacyPoolAddress,
+			AppsLocalState: []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID_V1_0, KeyValue: poolState.Encode()}},
+		},
+	})
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{Address: user},
+	})
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, FirstRoundValid: 1, LastRoundValid: 1000, GenesisHash: make([]byte, 32)}}
+
+	// the logicsig of the v1.0 pool can not be recovered, its liquidity is reported and not migrated
+	result, err := PrepareMigrationPlan(tinymanClient, user, 0.01, nil, options)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, result.Plan.Len())
+	assert.True(t, result.Complete)
+	assert.Equal(t, 1, result.SkippedLen())
+	assert.Equal(t, constants.TESTNET_VALIDATOR_APP_ID_V1_0, result.GetSkipped(0).ValidatorAppId)
+	assert.Equal(t, legacyPoolAddress, result.GetSkipped(0).PoolAddress)
+	assert.Equal(t, 5, result.GetSkipped(0).AssetId)
+	assert.Equal(t, "100", result.GetSkipped(0).Amount)
+
+	// the logicsig of the v1.0 pool is recovered from a transaction the pool signed, its liquidity moves to the v1.1 pool
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).
+		Reply(200).JSON(models.Account{
+		Address: user,
+		Assets:  []models.AssetHolding{{AssetId: 5, Amount: 100}},
+	})
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", legacyPoolAddress)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        legacyPoolAddress,
+			AppsLocalState: []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID_V1_0, KeyValue: poolState.Encode()}},
+			CreatedAssets:  []models.Asset{{Index: 5, Params: models.AssetParams{Name: "TinymanPool1.0 TEST-ALGO"}}},
+		},
+	})
+
+	gock.New(indexerURL).Get("/v2/transactions").MatchParam("address", legacyPoolAddress).MatchParam("sig-type", "lsig").
+		Reply(200).JSON(map[string]interface{}{
+		"transactions": []models.Transaction{{Signature: models.TransactionSignature{Logicsig: models.TransactionSignatureLogicsig{Logic: legacyProgram}}}},
+	})
+
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/applications/%d", constants.TESTNET_VALIDATOR_APP_ID_V1_0)).
+		Reply(200).JSON(models.Application{
+		Id:     constants.TESTNET_VALIDATOR_APP_ID_V1_0,
+		Params: models.ApplicationParams{LocalStateSchema: models.ApplicationStateSchema{NumUint: 16}},
+	})
+
+	gock.New(indexerURL).Get("/v2/assets/2").
+		Reply(200).JSON(map[string]interface{}{
+		"asset": models.Asset{Index: 2, Params: models.AssetParams{UnitName: "TEST", Decimals: 6}},
+	})
+
+	poolAddress, err := contracts.PoolAddress(constants.TESTNET_VALIDATOR_APP_ID_V1_1, 2, 0)
+	assert.Nil(t, err)
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", poolAddress)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        poolAddress,
+			Amount:         2000000,
+			AppsLocalState: []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID_V1_1, KeyValue: poolState.Encode()}},
+			CreatedAssets:  []models.Asset{{Index: 9, Params: models.AssetParams{Name: "TinymanPool1.1 TEST-ALGO"}}},
+		},
+	})
+
+	result, err = PrepareMigrationPlan(tinymanClient, user, 0.01, nil, options)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, result.SkippedLen())
+	assert.False(t, result.Complete)
+	assert.Equal(t, 4, result.Plan.Len())
+	assert.Equal(t, "optin", result.Plan.GetStep(0).Name)
+	assert.Equal(t, "burn", result.Plan.GetStep(1).Name)
+	assert.Equal(t, "optin", result.Plan.GetStep(2).Name)
+	assert.Equal(t, "mint", result.Plan.GetStep(3).Name)
+
+	// the burn transactions of the v1.0 pool are signed with the recovered logicsig
+	burnGroup := result.Plan.GetStep(1).GetTransactionGroup()
+	poolSigned := 0
+	for i, txn := range burnGroup.GetTransactions() {
+		if txn.Sender.String() == legacyPoolAddress {
+			assert.NotNil(t, burnGroup.GetSignedTransactions()[i])
+			poolSigned++
+		}
+	}
+	assert.Greater(t, poolSigned, 0)
+
+	// the schema of the v1.0 validator is read from the chain, e.g. for the min balance of its pools
+	minBalance, err := bootstrap.GetPoolMinBalance(constants.TESTNET_VALIDATOR_APP_ID_V1_0, 0, "")
+	assert.Nil(t, err)
+	assert.Greater(t, minBalance, 0)
+
+}
@@ -0,0 +1,126 @@
+package pools
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/swap"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// PrepareSwapAndPayTransactions swaps with a fixed-output quote and pays amount of the output asset to recipientAddress.
+// The plan has the swap (and integrator fee) followed by the payment group. The payment is not atomic with the swap:
+// it is a NonAtomic step submitted after the swap is confirmed, so the swap can succeed while the recipient is never paid.
+func (s *Pool) PrepareSwapAndPayTransactions(quote *SwapQuote, payerAddress, recipientAddress, amount string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	return s.prepareSwapAndPayTransactions(quote, payerAddress, recipientAddress, amount, nil, options)
+
+}
+
+// PrepareSwapAndPayTransactionsFromURI quotes a fixed-output swap for an ARC-26 payment request and prepares it
+// with PrepareSwapAndPayTransactions, the payment is not atomic with the swap. The note (or xnote) of the request is
+// set on the payment.
+func (s *Pool) PrepareSwapAndPayTransactionsFromURI(uri, payerAddress string, slippage float64, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	paymentRequest, err := utils.ParsePaymentRequestURI(uri)
+	if err != nil {
+		return
+	}
+
+	if utils.NewBigIntString(paymentRequest.Amount).Sign() == 0 {
+		err = fmt.Errorf("payment request has no amount")
+		return
+	}
+
+	var assetOut *types.Asset
+	if paymentRequest.AssetId == s.Asset1.Id {
+		assetOut = s.Asset1
+	} else if paymentRequest.AssetId == s.Asset2.Id {
+		assetOut = s.Asset2
+	} else {
+		err = fmt.Errorf("payment request asset %d does not belong to the pool", paymentRequest.AssetId)
+		return
+	}
+
+	quote, err := s.FetchFixedOutputSwapQuote(assetOut.Call(paymentRequest.Amount), slippage)
+	if err != nil {
+		return
+	}
+
+	var note []byte
+	if len(paymentRequest.GetNote()) > 0 {
+		note = []byte(paymentRequest.GetNote())
+	}
+
+	return s.prepareSwapAndPayTransactions(quote, payerAddress, paymentRequest.Address, paymentRequest.Amount, note, options)
+
+}
+
+func (s *Pool) prepareSwapAndPayTransactions(quote *SwapQuote, payerAddress, recipientAddress, amount string, note []byte, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	if quote.SwapType != "fixed-output" {
+		err = fmt.Errorf("swap and pay requires a fixed-output quote")
+		return
+	}
+
+	if len(payerAddress) == 0 {
+		payerAddress = s.Client.UserAddress
+	}
+
+	payer, err := algoTypes.DecodeAddress(payerAddress)
+	if err != nil {
+		return
+	}
+
+	recipient, err := algoTypes.DecodeAddress(recipientAddress)
+	if err != nil {
+		return
+	}
+
+	minimumReceived, err := quote.MinimumReceived()
+	if err != nil {
+		return
+	}
+
+	payment := quote.AmountOut.Asset.Call(amount)
+
+	insufficient, err := minimumReceived.Lt(payment)
+	if err != nil {
+		return
+	}
+
+	if insufficient {
+		err = fmt.Errorf("quote receives %s, less than the payment of %s", minimumReceived.String(), payment.String())
+		return
+	}
+
+	plan, err = s.PrepareSwapPlanFromQuote(quote, payer.String(), options)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := swap.PreparePaymentTransactions(
+		payment.Asset.Id,
+		payment.Amount,
+		recipient.String(),
+		payer.String(),
+		note,
+		suggestedParams,
+		withoutLease(options),
+	)
+	if err != nil {
+		return
+	}
+
+	plan.AddNonAtomicStep("non-atomic-payment", fmt.Sprintf("pay %s to %s after the swap", payment.String(), recipient.String()), txnGroup)
+
+	return
+
+}
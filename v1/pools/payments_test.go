@@ -0,0 +1,64 @@
+package pools
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestPrepareSwapAndPayTransactions(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	user := crypto.GenerateAccount().Address.String()
+	recipient := crypto.GenerateAccount().Address.String()
+
+	mockRetryPool(t, indexerURL, 2, 2000000, 2000000)
+
+	gock.New(indexerURL).Get("/v2/assets/2").
+		Reply(200).JSON(map[string]interface{}{
+		"asset": models.Asset{Index: 2, Params: models.AssetParams{UnitName: "TEST", Decimals: 6}},
+	})
+
+	tinymanClient, err := client.NewTinymanClient(algodURL, indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(tinymanClient, asset1, asset2, nil, true, 1)
+	assert.Nil(t, err)
+
+	quote, err := pool.FetchFixedOutputSwapQuote(asset2.Call("10000"), 0.01)
+	assert.Nil(t, err)
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 100, LastRoundValid: 1000}}
+
+	_, err = pool.PrepareSwapAndPayTransactions(quote, user, recipient, "20000", options)
+	assert.NotNil(t, err)
+
+	plan, err := pool.PrepareSwapAndPayTransactions(quote, user, recipient, "10000", options)
+	assert.Nil(t, err)
+
+	// the payment is a separate group after the swap, it is not atomic with it
+	assert.Equal(t, 2, plan.Len())
+	assert.Equal(t, "swap", plan.GetStep(0).Name)
+	assert.False(t, plan.GetStep(0).NonAtomic)
+	assert.Equal(t, "non-atomic-payment", plan.GetStep(1).Name)
+	assert.True(t, plan.GetStep(1).NonAtomic)
+
+	description, err := plan.Describe(tinymanClient, user)
+	assert.Nil(t, err)
+	assert.True(t, description.GetStep(1).NonAtomic)
+	assert.Contains(t, description.Text(), "Not atomic with the previous steps")
+
+}
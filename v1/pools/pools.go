@@ -1,7 +1,6 @@
 package pools
 
 import (
-	b64 "encoding/base64"
 	"encoding/json"
 	"fmt"
 	"math/big"
@@ -13,15 +12,16 @@ import (
 	"github.com/soheil555/tinyman-mobile-sdk/v1/bootstrap"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/burn"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/fees"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/mint"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/optin"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/redeem"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
 	"github.com/soheil555/tinyman-mobile-sdk/v1/swap"
 
 	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
-	"github.com/algorand/go-algorand-sdk/crypto"
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
@@ -44,18 +44,18 @@ type PoolInfo struct {
 	AlgoBalance                     string `json:"algo-balance"`
 	Round                           int    `json:"round"`
 	LastRefreshedRound              int    `json:"last-refreshed-round"`
+	// Status is one of the POOL_STATUS constants.
+	Status string `json:"status"`
 }
 
 func GetPoolInfo(client *client.TinymanClient, validatorAppID, asset1ID, asset2ID int) (poolInfo *PoolInfo, err error) {
 
-	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppID, asset1ID, asset2ID)
+	poolAddress, err := contracts.PoolAddress(validatorAppID, asset1ID, asset2ID)
 	if err != nil {
 		return
 	}
 
-	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
-
-	_, accountInfo, err := client.LookupAccountByID(poolAddress.String())
+	_, accountInfo, err := client.LookupAccountByID(poolAddress)
 	if err != nil {
 		return
 	}
@@ -73,68 +73,45 @@ func GetPoolInfoFromAccountInfo(accountInfo models.Account) (poolInfo *PoolInfo,
 
 	validatorAppID := int(accountInfo.AppsLocalState[0].Id)
 
-	validatorAppState := make(map[string]models.TealValue)
-
-	for _, x := range accountInfo.AppsLocalState[0].KeyValue {
-		validatorAppState[x.Key] = x.Value
+	poolState, err := state.DecodePoolState(accountInfo.AppsLocalState[0].KeyValue)
+	if err != nil {
+		return
 	}
 
-	asset1Id := utils.GetStateInt(validatorAppState, "a1")
-	asset2Id := utils.GetStateInt(validatorAppState, "a2")
+	asset1Id := poolState.Asset1Id
+	asset2Id := poolState.Asset2Id
 
-	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppID, asset1Id, asset2Id)
+	poolAddress, err := contracts.PoolAddress(validatorAppID, asset1Id, asset2Id)
 
 	if err != nil {
 		return
 	}
 
-	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
-
-	if accountInfo.Address != poolAddress.String() {
+	if accountInfo.Address != poolAddress {
 		err = fmt.Errorf("accountInfo address is not equal to poolAddress")
 		return
 	}
 
-	asset1Reserves := utils.GetStateInt(validatorAppState, "s1")
-	asset2Reserves := utils.GetStateInt(validatorAppState, "s2")
-	issuedLiquidity := utils.GetStateInt(validatorAppState, "ilt")
-	unclaimedProtocolFees := utils.GetStateInt(validatorAppState, "p")
+	// the liquidity asset is created by the bootstrap, with the opt in to the validator app
+	var liquidityAsset models.Asset
+	if len(accountInfo.CreatedAssets) > 0 {
+		liquidityAsset = accountInfo.CreatedAssets[0]
+	}
 
-	liquidityAsset := accountInfo.CreatedAssets[0]
 	liquidityAssetID := int(liquidityAsset.Index)
 
-	key1 := []byte("o")
-	key1 = append(key1, utils.IntToBytes(int(asset1Id))...)
-	encodedKey1 := make([]byte, b64.StdEncoding.EncodedLen(len(key1)))
+	asset1ReservesBig := new(big.Int).SetUint64(poolState.Asset1Reserves)
+	asset2ReservesBig := new(big.Int).SetUint64(poolState.Asset2Reserves)
+	issuedLiquidityBig := new(big.Int).SetUint64(poolState.IssuedLiquidity)
+	accountAmountBig := new(big.Int).SetUint64(accountInfo.Amount)
 
-	b64.StdEncoding.Encode(encodedKey1, key1)
-
-	outstandingAsset1Amount := utils.GetStateInt(validatorAppState, encodedKey1)
-
-	key2 := []byte("o")
-	key2 = append(key2, utils.IntToBytes(asset2Id)...)
-	encodedKey2 := make([]byte, b64.StdEncoding.EncodedLen(len(key2)))
-
-	outstandingAsset2Amount := utils.GetStateInt(validatorAppState, encodedKey2)
-
-	key3 := []byte("o")
-	key3 = append(key3, utils.IntToBytes(liquidityAssetID)...)
-	encodedKey3 := make([]byte, b64.StdEncoding.EncodedLen(len(key3)))
-
-	outstandingLiquidityAssetAmount := utils.GetStateInt(validatorAppState, encodedKey3)
-
-	asset1ReservesBig := big.NewInt(int64(asset1Reserves))
-	asset2ReservesBig := big.NewInt(int64(asset2Reserves))
-	issuedLiquidityBig := big.NewInt(int64(issuedLiquidity))
-	accountAmountBig := big.NewInt(int64(accountInfo.Amount))
-
-	unclaimedProtocolFeesBig := big.NewInt(int64(unclaimedProtocolFees))
-	outstandingAsset1AmountBig := big.NewInt(int64(outstandingAsset1Amount))
-	outstandingAsset2AmountBig := big.NewInt(int64(outstandingAsset2Amount))
-	outstandingLiquidityAssetAmountBig := big.NewInt(int64(outstandingLiquidityAssetAmount))
+	unclaimedProtocolFeesBig := new(big.Int).SetUint64(poolState.UnclaimedProtocolFees)
+	outstandingAsset1AmountBig := new(big.Int).SetUint64(poolState.OutstandingAmount(asset1Id))
+	outstandingAsset2AmountBig := new(big.Int).SetUint64(poolState.OutstandingAmount(asset2Id))
+	outstandingLiquidityAssetAmountBig := new(big.Int).SetUint64(poolState.OutstandingAmount(liquidityAssetID))
 
 	poolInfo = &PoolInfo{
-		Address:                         poolAddress.String(),
+		Address:                         poolAddress,
 		Asset1Id:                        asset1Id,
 		Asset2Id:                        asset2Id,
 		LiquidityAssetId:                liquidityAssetID,
@@ -151,21 +128,14 @@ func GetPoolInfoFromAccountInfo(accountInfo models.Account) (poolInfo *PoolInfo,
 		Round:                           int(accountInfo.Round),
 	}
 
+	poolInfo.Status = poolStatus(poolInfo)
+
 	return
 
 }
 
 func GetExcessAssetKey(poolAddress string, assetID int) (key []byte, err error) {
-	a, err := algoTypes.DecodeAddress(poolAddress)
-	if err != nil {
-		return
-	}
-
-	key = append(key, a[:]...)
-	key = append(key, byte('e'))
-	key = append(key, utils.IntToBytes(assetID)...)
-
-	return
+	return state.ExcessKey(poolAddress, assetID)
 }
 
 type SwapQuote struct {
@@ -174,6 +144,64 @@ type SwapQuote struct {
 	AmountOut *types.AssetAmount `json:"amount-out"`
 	SwapFees  *types.AssetAmount `json:"swap-fees"`
 	Slippage  float64            `json:"slippage"`
+	// RequestedAmount is the fixed amount the quote was fetched for, AmountIn or AmountOut before the integrator fee.
+	RequestedAmount *types.AssetAmount `json:"requested-amount"`
+	// IntegratorFee is the platform fee paid to IntegratorFeeAddress after the swap, nil when there is none.
+	// It is taken from the input or the output asset on top of the swap amounts. It is not atomic with the swap:
+	// it is paid by a separate group, so the swap can succeed while the fee is never paid.
+	IntegratorFee        *types.AssetAmount `json:"integrator-fee,omitempty"`
+	IntegratorFeeAddress string             `json:"integrator-fee-address,omitempty"`
+	// ExpectedExcess is the excess credited to the swapper at the quoted reserves and
+	// WorstCaseExcess the excess at the slippage boundary, see FetchExcessAmounts.
+	ExpectedExcess  *types.AssetAmount `json:"expected-excess"`
+	WorstCaseExcess *types.AssetAmount `json:"worst-case-excess"`
+	// Round is the round of the pool state the quote is computed from, Timestamp the unix time it is computed at.
+	Round       int    `json:"round"`
+	Timestamp   int64  `json:"timestamp"`
+	PoolAddress string `json:"pool-address"`
+}
+
+func (s *SwapQuote) setIntegratorFee(integratorFee *types.IntegratorFee, amount *types.AssetAmount) {
+
+	if integratorFee == nil || amount == nil || utils.NewBigIntString(amount.Amount).Sign() == 0 {
+		return
+	}
+
+	s.IntegratorFee = amount
+	s.IntegratorFeeAddress = integratorFee.Address
+
+}
+
+// MinimumReceived is the output amount with slippage minus an integrator fee taken from the output asset.
+func (s *SwapQuote) MinimumReceived() (assetAmount *types.AssetAmount, err error) {
+
+	assetAmount, err = s.AmountOutWithSlippage()
+	if err != nil {
+		return
+	}
+
+	if s.IntegratorFee != nil && *s.IntegratorFee.Asset == *assetAmount.Asset {
+		assetAmount, err = assetAmount.Sub(s.IntegratorFee)
+	}
+
+	return
+
+}
+
+// MaximumSent is the input amount with slippage plus an integrator fee taken from the input asset.
+func (s *SwapQuote) MaximumSent() (assetAmount *types.AssetAmount, err error) {
+
+	assetAmount, err = s.AmountInWithSlippage()
+	if err != nil {
+		return
+	}
+
+	if s.IntegratorFee != nil && *s.IntegratorFee.Asset == *assetAmount.Asset {
+		assetAmount, err = assetAmount.Add(s.IntegratorFee)
+	}
+
+	return
+
 }
 
 func (s *SwapQuote) AmountOutWithSlippage() (assetAmount *types.AssetAmount, err error) {
@@ -240,11 +268,17 @@ func (s *SwapQuote) PriceWithSlippage() (priceWithSlippage float64, err error) {
 
 }
 
-//TODO: in python code AmountsIn is dict[AssetAmount]
+// TODO: in python code AmountsIn is dict[AssetAmount]
 type MintQuote struct {
 	amountsIn            map[int]string     // map[asset.id][assetAmount.Amount]
+	expectedExcess       map[int]string     // map[asset.id][excess amount at the quoted reserves]
+	worstCaseExcess      map[int]string     // map[asset.id][excess amount at the slippage boundary]
 	LiquidityAssetAmount *types.AssetAmount `json:"liquidity-asset-amount"`
 	Slippage             float64            `json:"slippage"`
+	// Round is the round of the pool state the quote is computed from, Timestamp the unix time it is computed at.
+	Round       int    `json:"round"`
+	Timestamp   int64  `json:"timestamp"`
+	PoolAddress string `json:"pool-address"`
 }
 
 func (s *MintQuote) GetAmountsInStr() (string, error) {
@@ -260,7 +294,7 @@ func (s *MintQuote) GetAmountsIn() map[int]string {
 
 }
 
-//TODO: in python code it return int
+// TODO: in python code it return int
 func (s *MintQuote) LiquidityAssetAmountWithSlippage() (assetAmount *types.AssetAmount, err error) {
 	assetAmount, err = s.LiquidityAssetAmount.Sub(s.LiquidityAssetAmount.Mul(s.Slippage))
 	return
@@ -270,6 +304,10 @@ type BurnQuote struct {
 	amountsOut           map[int]string     // map[asset.id][assetAmount.Amount]
 	LiquidityAssetAmount *types.AssetAmount `json:"liquidity-asset-amount"`
 	Slippage             float64            `json:"slippage"`
+	// Round is the round of the pool state the quote is computed from, Timestamp the unix time it is computed at.
+	Round       int    `json:"round"`
+	Timestamp   int64  `json:"timestamp"`
+	PoolAddress string `json:"pool-address"`
 }
 
 func (s *BurnQuote) GetAmountsOutStr() (amountsOutStr string, err error) {
@@ -328,25 +366,27 @@ func (s *BurnQuote) AmountsOutWithSlippageStr() (amountsOutWithSlippageStr strin
 }
 
 type Pool struct {
-	Client                          *client.TinymanClient `json:"client"`
-	ValidatorAppId                  int                   `json:"validator-app-id"`
-	Asset1                          *types.Asset          `json:"asset1"`
-	Asset2                          *types.Asset          `json:"asset2"`
-	Exists                          bool                  `json:"exists"`
-	LiquidityAsset                  *types.Asset          `json:"liquidity-asset"`
-	Asset1Reserves                  string                `json:"asset1-reserves"`
-	Asset2Reserves                  string                `json:"asset2-reserves"`
-	IssuedLiquidity                 string                `json:"issued-liquidity"`
-	UnclaimedProtocolFees           string                `json:"unclaimed-protocol-fees"`
-	OutstandingAsset1Amount         string                `json:"outstanding-asset1-amount"`
-	OutstandingAsset2Amount         string                `json:"outstanding-asset2-amount"`
-	OutstandingLiquidityAssetAmount string                `json:"outstanding-liquidity-asset-amount"`
-	LastRefreshedRound              int                   `json:"last-refreshed-round"`
-	AlgoBalance                     string                `json:"algo-balance"`
-	MinBalance                      int                   `json:"min-balance"`
-}
-
-//TODO: is validatorID == 0 a valid ID
+	Client         *client.TinymanClient `json:"client"`
+	ValidatorAppId int                   `json:"validator-app-id"`
+	Asset1         *types.Asset          `json:"asset1"`
+	Asset2         *types.Asset          `json:"asset2"`
+	Exists         bool                  `json:"exists"`
+	// Status is one of the POOL_STATUS constants, Exists is false when it is POOL_STATUS_NOT_CREATED.
+	Status                          string       `json:"status"`
+	LiquidityAsset                  *types.Asset `json:"liquidity-asset"`
+	Asset1Reserves                  string       `json:"asset1-reserves"`
+	Asset2Reserves                  string       `json:"asset2-reserves"`
+	IssuedLiquidity                 string       `json:"issued-liquidity"`
+	UnclaimedProtocolFees           string       `json:"unclaimed-protocol-fees"`
+	OutstandingAsset1Amount         string       `json:"outstanding-asset1-amount"`
+	OutstandingAsset2Amount         string       `json:"outstanding-asset2-amount"`
+	OutstandingLiquidityAssetAmount string       `json:"outstanding-liquidity-asset-amount"`
+	LastRefreshedRound              int          `json:"last-refreshed-round"`
+	AlgoBalance                     string       `json:"algo-balance"`
+	MinBalance                      int          `json:"min-balance"`
+}
+
+// TODO: is validatorID == 0 a valid ID
 func NewPool(client *client.TinymanClient, assetA, assetB *types.Asset, info *PoolInfo, fetch bool, validatorAppId int) (pool *Pool, err error) {
 
 	pool = new(Pool)
@@ -357,6 +397,7 @@ func NewPool(client *client.TinymanClient, assetA, assetB *types.Asset, info *Po
 	}
 
 	pool.Client = client
+	pool.Status = POOL_STATUS_UNKNOWN
 
 	if validatorAppId == 0 {
 		pool.ValidatorAppId = client.ValidatorAppId
@@ -385,7 +426,7 @@ func NewPool(client *client.TinymanClient, assetA, assetB *types.Asset, info *Po
 
 	} else if info != nil {
 
-		pool.UpdateFromInfo(info)
+		err = pool.UpdateFromInfo(info)
 
 	}
 
@@ -394,8 +435,59 @@ func NewPool(client *client.TinymanClient, assetA, assetB *types.Asset, info *Po
 }
 
 // not compatible with go-mobile
+// recoverPoolContracts registers the logicsig of the pool of accountInfo and the local state schema of its validator
+// from the chain when the contract definitions of the validator are not available, e.g. for v1.0 pools.
+func recoverPoolContracts(tinymanClient *client.TinymanClient, accountInfo models.Account) (err error) {
+
+	if len(accountInfo.AppsLocalState) == 0 {
+		return
+	}
+
+	validatorAppID := int(accountInfo.AppsLocalState[0].Id)
+
+	poolState, err := state.DecodePoolState(accountInfo.AppsLocalState[0].KeyValue)
+	if err != nil {
+		return
+	}
+
+	if _, addressErr := contracts.PoolAddress(validatorAppID, poolState.Asset1Id, poolState.Asset2Id); addressErr == nil {
+		return
+	}
+
+	program, err := tinymanClient.FetchPoolLogicsig(accountInfo.Address)
+	if err != nil {
+		return
+	}
+
+	err = contracts.RegisterPoolLogicsig(validatorAppID, poolState.Asset1Id, poolState.Asset2Id, program, accountInfo.Address)
+	if err != nil {
+		return
+	}
+
+	if _, appErr := contracts.GetValidatorAppOf(validatorAppID); appErr == nil {
+		return
+	}
+
+	schema, err := tinymanClient.FetchValidatorLocalStateSchema(validatorAppID)
+	if err != nil {
+		return
+	}
+
+	contracts.RegisterValidatorLocalStateSchema(validatorAppID, schema.NumUints, schema.NumByteSlices)
+
+	return
+
+}
+
+// NewPoolFromAccountInfo reads the pool of a pool account. The logicsig of a pool whose contract definitions
+// are not available, e.g. a v1.0 pool, is recovered from the transactions the pool signed.
 func NewPoolFromAccountInfo(accountInfo models.Account, client *client.TinymanClient) (pool *Pool, err error) {
 
+	err = recoverPoolContracts(client, accountInfo)
+	if err != nil {
+		return
+	}
+
 	info, err := GetPoolInfoFromAccountInfo(accountInfo)
 
 	if err != nil {
@@ -423,24 +515,28 @@ func (s *Pool) Refresh() (err error) {
 
 	info, err := GetPoolInfo(s.Client, s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id)
 
-	if err != nil || reflect.ValueOf(info).IsZero() {
+	if err != nil {
 		return
 	}
 
-	s.UpdateFromInfo(info)
+	if reflect.ValueOf(info).IsZero() {
+		s.Exists = false
+		s.Status = POOL_STATUS_NOT_CREATED
+		return
+	}
+
+	err = s.UpdateFromInfo(info)
 
 	return
 
 }
 
-func (s *Pool) UpdateFromInfo(info *PoolInfo) {
+func (s *Pool) UpdateFromInfo(info *PoolInfo) (err error) {
 
-	//TODO: LiquidityAssetID is an ASA(Algorand Standard Asset). 0 is not a valid ASA ID
-	if info.LiquidityAssetId != 0 {
-		s.Exists = true
-	}
+	s.Status = poolStatus(info)
+	s.Exists = s.Status != POOL_STATUS_NOT_CREATED
 
-	s.LiquidityAsset = &types.Asset{Id: info.LiquidityAssetId, Name: info.LiquidityAssetName, UnitName: "TMPOOL11", Decimals: 6}
+	s.LiquidityAsset = &types.Asset{Id: info.LiquidityAssetId, Name: info.LiquidityAssetName, UnitName: liquidityAssetUnitName(s.ValidatorAppId), Decimals: 6}
 	s.Asset1Reserves = info.Asset1Reserves
 	s.Asset2Reserves = info.Asset2Reserves
 	s.IssuedLiquidity = info.IssuedLiquidity
@@ -451,7 +547,10 @@ func (s *Pool) UpdateFromInfo(info *PoolInfo) {
 	s.LastRefreshedRound = info.Round
 
 	s.AlgoBalance = info.AlgoBalance
-	s.MinBalance = s.GetMinimumBalance()
+	s.MinBalance, err = s.GetMinimumBalance()
+	if err != nil {
+		return
+	}
 
 	if s.Asset2.Id == 0 {
 
@@ -467,6 +566,18 @@ func (s *Pool) UpdateFromInfo(info *PoolInfo) {
 
 	}
 
+	return
+
+}
+
+func liquidityAssetUnitName(validatorAppId int) string {
+
+	if validatorAppId == constants.TESTNET_VALIDATOR_APP_ID_V1_0 || validatorAppId == constants.MAINNET_VALIDATOR_APP_ID_V1_0 {
+		return constants.LIQUIDITY_ASSET_UNIT_NAME_V1_0
+	}
+
+	return constants.LIQUIDITY_ASSET_UNIT_NAME_V1_1
+
 }
 
 func (s *Pool) GetLogicsig() (poolLogicsig *types.LogicSig, err error) {
@@ -479,13 +590,7 @@ func (s *Pool) GetLogicsig() (poolLogicsig *types.LogicSig, err error) {
 
 func (s *Pool) Address() (poolAddress string, err error) {
 
-	logicsig, err := s.GetLogicsig()
-
-	if err != nil {
-		return
-	}
-
-	poolAddress = crypto.AddressFromProgram(logicsig.Logic).String()
+	poolAddress, err = contracts.PoolAddress(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id)
 
 	return
 
@@ -520,6 +625,11 @@ func (s *Pool) Asset2Price() float64 {
 
 func (s *Pool) Info() (poolInfo *PoolInfo, err error) {
 
+	if s.LiquidityAsset == nil {
+		err = &PoolStatusError{Status: s.Status, Operation: "get the info of the pool", Reason: poolStatusReasons[POOL_STATUS_NOT_CREATED]}
+		return
+	}
+
 	address, err := s.Address()
 
 	if err != nil {
@@ -542,6 +652,7 @@ func (s *Pool) Info() (poolInfo *PoolInfo, err error) {
 		OutstandingAsset2Amount:         s.OutstandingAsset2Amount,
 		OutstandingLiquidityAssetAmount: s.OutstandingLiquidityAssetAmount,
 		LastRefreshedRound:              s.LastRefreshedRound,
+		Status:                          s.Status,
 	}
 
 	return
@@ -593,8 +704,8 @@ func (s *Pool) FetchMintQuote(amountA, amountB *types.AssetAmount, slippage floa
 		return
 	}
 
-	if !s.Exists {
-		err = fmt.Errorf("pool has not been bootstrapped yet")
+	err = s.checkStatus("mint", POOL_STATUS_NOT_CREATED)
+	if err != nil {
 		return
 	}
 
@@ -609,33 +720,12 @@ func (s *Pool) FetchMintQuote(amountA, amountB *types.AssetAmount, slippage floa
 			amount2 = s.Convert(amount1)
 		}
 
-		amount1Amount := utils.NewBigFloatString(amount1.Amount)
-		amount2Amount := utils.NewBigFloatString(amount2.Amount)
-		asset1Reserves := utils.NewBigFloatString(s.Asset1Reserves)
-		asset2Reserves := utils.NewBigFloatString(s.Asset2Reserves)
-
-		helper1 := new(big.Float).Mul(amount1Amount, issuedLiquidity)
-		helper2 := new(big.Float).Mul(amount2Amount, issuedLiquidity)
-
-		a := new(big.Float).Quo(helper1, asset1Reserves)
-		b := new(big.Float).Quo(helper2, asset2Reserves)
-
-		if a.Cmp(b) < 0 {
-
-			a, _ := a.Int(nil)
-			liquidityAssetAmount = a.String()
-
-		} else {
-
-			b, _ := b.Int(nil)
-			liquidityAssetAmount = b.String()
-
-		}
+		liquidityAssetAmount = s.mintLiquidity(utils.NewBigIntString(amount1.Amount), utils.NewBigIntString(amount2.Amount)).String()
 
 	} else {
 
 		if amount1 == nil || amount2 == nil {
-			err = fmt.Errorf("amounts required for both assets for first mint")
+			err = &PoolStatusError{Status: s.Status, Operation: "mint", Reason: "amounts required for both assets for first mint"}
 			return
 		}
 
@@ -645,7 +735,7 @@ func (s *Pool) FetchMintQuote(amountA, amountB *types.AssetAmount, slippage floa
 		helper := new(big.Float)
 		helper.Mul(amount1Amount, amount2Amount)
 		helper.Sqrt(helper)
-		helper.Sub(helper, big.NewFloat(1000))
+		helper.Sub(helper, big.NewFloat(constants.LOCKED_LIQUIDITY))
 
 		helperInt, _ := helper.Int(nil)
 		liquidityAssetAmount = helperInt.String()
@@ -663,6 +753,13 @@ func (s *Pool) FetchMintQuote(amountA, amountB *types.AssetAmount, slippage floa
 		Slippage:             slippage,
 	}
 
+	err = s.setMintExcess(quote)
+	if err != nil {
+		return
+	}
+
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
 	return
 
 }
@@ -680,19 +777,12 @@ func (s *Pool) FetchBurnQuote(liquidityAssetIn *types.AssetAmount, slippage floa
 		return
 	}
 
-	liquidityAssetInAmount := utils.NewBigFloatString(liquidityAssetIn.Amount)
-	asset1Reserves := utils.NewBigFloatString(s.Asset1Reserves)
-	asset2Reserves := utils.NewBigFloatString(s.Asset2Reserves)
-	issuedLiquidity := utils.NewBigFloatString(s.IssuedLiquidity)
-
-	helper1 := new(big.Float).Mul(liquidityAssetInAmount, asset1Reserves)
-	helper2 := new(big.Float).Mul(liquidityAssetInAmount, asset2Reserves)
-
-	asset1Amount := new(big.Float).Quo(helper1, issuedLiquidity)
-	asset2Amount := new(big.Float).Quo(helper2, issuedLiquidity)
+	err = s.checkStatus("burn", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
 
-	asset1AmountInt, _ := asset1Amount.Int(nil)
-	asset2AmountInt, _ := asset2Amount.Int(nil)
+	asset1AmountInt, asset2AmountInt := s.burnAmounts(utils.NewBigIntString(liquidityAssetIn.Amount))
 
 	quote = &BurnQuote{
 		amountsOut: map[int]string{
@@ -703,6 +793,8 @@ func (s *Pool) FetchBurnQuote(liquidityAssetIn *types.AssetAmount, slippage floa
 		Slippage:             slippage,
 	}
 
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
 	return
 
 }
@@ -715,6 +807,18 @@ func (s *Pool) FetchBurnQuoteWithDefaultSlippage(liquidityAssetIn *types.AssetAm
 
 func (s *Pool) FetchFixedInputSwapQuote(amountIn *types.AssetAmount, slippage float64) (quote *SwapQuote, err error) {
 
+	requestedAmount := amountIn
+	integratorFee := s.integratorFee()
+	var integratorFeeAmount *types.AssetAmount
+
+	if integratorFee != nil && integratorFee.Side == types.INTEGRATOR_FEE_INPUT {
+		integratorFeeAmount = integratorFee.Of(amountIn)
+		amountIn, err = amountIn.Sub(integratorFeeAmount)
+		if err != nil {
+			return
+		}
+	}
+
 	var assetOut *types.Asset
 	var inputSupply, outputSupply string
 
@@ -726,6 +830,11 @@ func (s *Pool) FetchFixedInputSwapQuote(amountIn *types.AssetAmount, slippage fl
 		return
 	}
 
+	err = s.checkStatus("swap", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
+
 	if *assetIn == *s.Asset1 {
 		assetOut = s.Asset2
 		inputSupply = s.Asset1Reserves
@@ -740,7 +849,7 @@ func (s *Pool) FetchFixedInputSwapQuote(amountIn *types.AssetAmount, slippage fl
 	outputSupplyBig := utils.NewBigFloatString(outputSupply)
 
 	if inputSupplyBig.Sign() == 0 || outputSupplyBig.Sign() == 0 {
-		err = fmt.Errorf("pool has no liquidity")
+		err = &PoolStatusError{Status: s.Status, Operation: "swap", Reason: "pool has no liquidity"}
 		return
 	}
 
@@ -762,13 +871,27 @@ func (s *Pool) FetchFixedInputSwapQuote(amountIn *types.AssetAmount, slippage fl
 	amountOut := types.AssetAmount{Asset: assetOut, Amount: assetOutAmountInt.String()}
 
 	quote = &SwapQuote{
-		SwapType:  "fixed-input",
-		AmountIn:  amountIn,
-		AmountOut: &amountOut,
-		SwapFees:  &types.AssetAmount{Asset: amountIn.Asset, Amount: swapFees.String()},
-		Slippage:  slippage,
+		SwapType:        "fixed-input",
+		AmountIn:        amountIn,
+		AmountOut:       &amountOut,
+		SwapFees:        &types.AssetAmount{Asset: amountIn.Asset, Amount: swapFees.String()},
+		Slippage:        slippage,
+		RequestedAmount: requestedAmount,
+	}
+
+	if integratorFee != nil && integratorFee.Side == types.INTEGRATOR_FEE_OUTPUT {
+		integratorFeeAmount = integratorFee.Of(quote.AmountOut)
+	}
+
+	quote.setIntegratorFee(integratorFee, integratorFeeAmount)
+
+	err = quote.setExcess()
+	if err != nil {
+		return
 	}
 
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
 	return
 
 }
@@ -779,6 +902,18 @@ func (s *Pool) FetchFixedInputSwapQuoteWithDefaultSlippage(amountIn *types.Asset
 
 func (s *Pool) FetchFixedOutputSwapQuote(amountOut *types.AssetAmount, slippage float64) (quote *SwapQuote, err error) {
 
+	requestedAmount := amountOut
+	integratorFee := s.integratorFee()
+	var integratorFeeAmount *types.AssetAmount
+
+	if integratorFee != nil && integratorFee.Side == types.INTEGRATOR_FEE_OUTPUT {
+		integratorFeeAmount = integratorFee.Of(amountOut)
+		amountOut, err = amountOut.Add(integratorFeeAmount)
+		if err != nil {
+			return
+		}
+	}
+
 	var assetIn *types.Asset
 	var inputSupply, outputSupply string
 
@@ -790,6 +925,11 @@ func (s *Pool) FetchFixedOutputSwapQuote(amountOut *types.AssetAmount, slippage
 		return
 	}
 
+	err = s.checkStatus("swap", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
+
 	if *assetOut == *s.Asset1 {
 		assetIn = s.Asset2
 		inputSupply = s.Asset2Reserves
@@ -804,6 +944,11 @@ func (s *Pool) FetchFixedOutputSwapQuote(amountOut *types.AssetAmount, slippage
 	outputSupplyBig := utils.NewBigFloatString(outputSupply)
 	assetOutAmountBig := utils.NewBigFloatString(assetOutAmount)
 
+	if inputSupplyBig.Sign() == 0 || outputSupplyBig.Cmp(assetOutAmountBig) <= 0 {
+		err = &PoolStatusError{Status: s.Status, Operation: "swap", Reason: "pool has not enough liquidity"}
+		return
+	}
+
 	k := new(big.Float).Mul(inputSupplyBig, outputSupplyBig)
 
 	helper := new(big.Float).Sub(outputSupplyBig, assetOutAmountBig)
@@ -822,13 +967,27 @@ func (s *Pool) FetchFixedOutputSwapQuote(amountOut *types.AssetAmount, slippage
 	amountIn := types.AssetAmount{Asset: assetIn, Amount: assetInAmountInt.String()}
 
 	quote = &SwapQuote{
-		SwapType:  "fixed-output",
-		AmountIn:  &amountIn,
-		AmountOut: amountOut,
-		SwapFees:  &types.AssetAmount{Asset: amountIn.Asset, Amount: swapFeesInt.String()},
-		Slippage:  slippage,
+		SwapType:        "fixed-output",
+		AmountIn:        &amountIn,
+		AmountOut:       amountOut,
+		SwapFees:        &types.AssetAmount{Asset: amountIn.Asset, Amount: swapFeesInt.String()},
+		Slippage:        slippage,
+		RequestedAmount: requestedAmount,
+	}
+
+	if integratorFee != nil && integratorFee.Side == types.INTEGRATOR_FEE_INPUT {
+		integratorFeeAmount = integratorFee.Of(quote.AmountIn)
 	}
 
+	quote.setIntegratorFee(integratorFee, integratorFeeAmount)
+
+	err = quote.setExcess()
+	if err != nil {
+		return
+	}
+
+	quote.Round, quote.Timestamp, quote.PoolAddress, err = s.quoteSource()
+
 	return
 
 }
@@ -837,7 +996,12 @@ func (s *Pool) FetchFixedOutputSwapQuoteWithDefaultSlippage(amountOut *types.Ass
 	return s.FetchFixedOutputSwapQuote(amountOut, 0.05)
 }
 
-func (s *Pool) PrepareSwapTransactions(amountIn, amountOut *types.AssetAmount, swapType string, swapperAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareSwapTransactions(amountIn, amountOut *types.AssetAmount, swapType string, swapperAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	err = s.checkStatus("swap", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
 
 	if len(swapperAddress) == 0 {
 		swapperAddress = s.Client.UserAddress
@@ -848,22 +1012,11 @@ func (s *Pool) PrepareSwapTransactions(amountIn, amountOut *types.AssetAmount, s
 		return
 	}
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = swap.PrepareSwapTransactions(
 		s.ValidatorAppId,
 		s.Asset1.Id,
@@ -875,13 +1028,19 @@ func (s *Pool) PrepareSwapTransactions(amountIn, amountOut *types.AssetAmount, s
 		swapType,
 		swapper.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 
 }
 
-func (s *Pool) PrepareSwapTransactionsFromQuote(quote *SwapQuote, swapperAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareSwapTransactionsFromQuote(quote *SwapQuote, swapperAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	options, err = s.bindQuote(quote.Round, quote.PoolAddress, options)
+	if err != nil {
+		return
+	}
 
 	amountIn, err := quote.AmountInWithSlippage()
 
@@ -895,11 +1054,82 @@ func (s *Pool) PrepareSwapTransactionsFromQuote(quote *SwapQuote, swapperAddress
 		return
 	}
 
-	return s.PrepareSwapTransactions(amountIn, amountOut, quote.SwapType, swapperAddress)
+	return s.PrepareSwapTransactions(amountIn, amountOut, quote.SwapType, swapperAddress, options)
+
+}
+
+func (s *Pool) integratorFee() *types.IntegratorFee {
+
+	if s.Client == nil {
+		return nil
+	}
+
+	return s.Client.IntegratorFee
+
+}
+
+// addIntegratorFeeStep appends the integrator fee transfer of quote to plan as a non-atomic step, if the quote has one.
+func (s *Pool) addIntegratorFeeStep(plan *utils.TransactionPlan, quote *SwapQuote, swapperAddress string, options *types.TxnOptions) (err error) {
+
+	if quote.IntegratorFee == nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := swap.PrepareIntegratorFeeTransactions(
+		quote.IntegratorFee.Asset.Id,
+		quote.IntegratorFee.Amount,
+		quote.IntegratorFeeAddress,
+		swapperAddress,
+		suggestedParams,
+		withoutLease(options),
+	)
+	if err != nil {
+		return
+	}
+
+	plan.AddNonAtomicStep("non-atomic-integrator-fee", fmt.Sprintf("pay the integrator fee of %s after the swap", quote.IntegratorFee.String()), txnGroup)
+
+	return
+
+}
+
+// PrepareSwapPlanFromQuote returns the swap group followed by the non-atomic integrator fee transfer of the quote, if any.
+func (s *Pool) PrepareSwapPlanFromQuote(quote *SwapQuote, swapperAddress string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	if len(swapperAddress) == 0 {
+		swapperAddress = s.Client.UserAddress
+	}
+
+	swapper, err := algoTypes.DecodeAddress(swapperAddress)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := s.PrepareSwapTransactionsFromQuote(quote, swapper.String(), options)
+	if err != nil {
+		return
+	}
+
+	plan = utils.NewTransactionPlan()
+	plan.AddStep("swap", "swap assets", txnGroup)
+
+	err = s.addIntegratorFeeStep(plan, quote, swapper.String(), options)
+
+	return
 
 }
 
-func (s *Pool) PrepareBootstrapTransactions(poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareBootstrapTransactions(poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	if s.Status != POOL_STATUS_UNKNOWN && s.Status != POOL_STATUS_NOT_CREATED {
+		err = &PoolStatusError{Status: s.Status, Operation: "bootstrap", Reason: "pool has already been bootstrapped"}
+		return
+	}
 
 	if len(poolerAddress) == 0 {
 		poolerAddress = s.Client.UserAddress
@@ -910,36 +1140,31 @@ func (s *Pool) PrepareBootstrapTransactions(poolerAddress string) (txnGroup *uti
 		return
 	}
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = bootstrap.PrepareBootstrapTransactions(s.ValidatorAppId,
 		s.Asset1.Id,
 		s.Asset2.Id,
 		s.Asset1.UnitName,
 		s.Asset2.UnitName,
 		pooler.String(),
-		suggestedParams)
+		suggestedParams,
+		options)
 
 	return
 
 }
 
-//TODO: type dic[Asset] is dict[Asset,AssetAmount] in python code
-func (s *Pool) PrepareMintTransactions(amountsInStr string, liquidityAssetAmount *types.AssetAmount, poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+// TODO: type dic[Asset] is dict[Asset,AssetAmount] in python code
+func (s *Pool) PrepareMintTransactions(amountsInStr string, liquidityAssetAmount *types.AssetAmount, poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	err = s.checkStatus("mint", POOL_STATUS_NOT_CREATED)
+	if err != nil {
+		return
+	}
 
 	amountsIn := make(map[int]string)
 	err = json.Unmarshal([]byte(amountsInStr), &amountsIn)
@@ -959,22 +1184,11 @@ func (s *Pool) PrepareMintTransactions(amountsInStr string, liquidityAssetAmount
 	asset1Amount := amountsIn[s.Asset1.Id]
 	asset2Amount := amountsIn[s.Asset2.Id]
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = mint.PrepareMintTransactions(s.ValidatorAppId,
 		s.Asset1.Id,
 		s.Asset2.Id,
@@ -984,13 +1198,19 @@ func (s *Pool) PrepareMintTransactions(amountsInStr string, liquidityAssetAmount
 		liquidityAssetAmount.Amount,
 		pooler.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 
 }
 
-func (s *Pool) PrepareMintTransactionsFromQuote(quote *MintQuote, poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareMintTransactionsFromQuote(quote *MintQuote, poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	options, err = s.bindQuote(quote.Round, quote.PoolAddress, options)
+	if err != nil {
+		return
+	}
 
 	liquidityAssetAmount, err := quote.LiquidityAssetAmountWithSlippage()
 	if err != nil {
@@ -1002,10 +1222,15 @@ func (s *Pool) PrepareMintTransactionsFromQuote(quote *MintQuote, poolerAddress
 		return
 	}
 
-	return s.PrepareMintTransactions(amountsIn, liquidityAssetAmount, poolerAddress)
+	return s.PrepareMintTransactions(amountsIn, liquidityAssetAmount, poolerAddress, options)
 }
 
-func (s *Pool) PrepareBurnTransactions(liquidityAssetAmount *types.AssetAmount, amountsOut map[int]string, poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareBurnTransactions(liquidityAssetAmount *types.AssetAmount, amountsOut map[int]string, poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	err = s.checkStatus("burn", POOL_STATUS_NOT_CREATED, POOL_STATUS_EMPTY)
+	if err != nil {
+		return
+	}
 
 	if len(poolerAddress) == 0 {
 		poolerAddress = s.Client.UserAddress
@@ -1019,22 +1244,11 @@ func (s *Pool) PrepareBurnTransactions(liquidityAssetAmount *types.AssetAmount,
 	asset1Amount := amountsOut[s.Asset1.Id]
 	asset2Amount := amountsOut[s.Asset2.Id]
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = burn.PrepareBurnTransactions(
 		s.ValidatorAppId,
 		s.Asset1.Id,
@@ -1045,13 +1259,14 @@ func (s *Pool) PrepareBurnTransactions(liquidityAssetAmount *types.AssetAmount,
 		liquidityAssetAmount.Amount,
 		pooler.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 
 }
 
-func (s *Pool) PrepareBurnTransactionsWithAmountsOutStr(liquidityAssetAmount *types.AssetAmount, amountsOutStr, poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareBurnTransactionsWithAmountsOutStr(liquidityAssetAmount *types.AssetAmount, amountsOutStr, poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	amountsOut := make(map[int]string)
 	err = json.Unmarshal([]byte(amountsOutStr), &amountsOut)
@@ -1059,11 +1274,16 @@ func (s *Pool) PrepareBurnTransactionsWithAmountsOutStr(liquidityAssetAmount *ty
 		return
 	}
 
-	return s.PrepareBurnTransactions(liquidityAssetAmount, amountsOut, poolerAddress)
+	return s.PrepareBurnTransactions(liquidityAssetAmount, amountsOut, poolerAddress, options)
 
 }
 
-func (s *Pool) PrepareBurnTransactionsFromQuote(quote *BurnQuote, poolerAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareBurnTransactionsFromQuote(quote *BurnQuote, poolerAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	options, err = s.bindQuote(quote.Round, quote.PoolAddress, options)
+	if err != nil {
+		return
+	}
 
 	amountsOut, err := quote.AmountsOutWithSlippage()
 
@@ -1075,11 +1295,12 @@ func (s *Pool) PrepareBurnTransactionsFromQuote(quote *BurnQuote, poolerAddress
 		quote.LiquidityAssetAmount,
 		amountsOut,
 		poolerAddress,
+		options,
 	)
 
 }
 
-func (s *Pool) PrepareRedeemTransactions(amountOut *types.AssetAmount, userAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareRedeemTransactions(amountOut *types.AssetAmount, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	if len(userAddress) == 0 {
 		userAddress = s.Client.UserAddress
@@ -1090,22 +1311,11 @@ func (s *Pool) PrepareRedeemTransactions(amountOut *types.AssetAmount, userAddre
 		return
 	}
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = redeem.PrepareRedeemTransactions(
 		s.ValidatorAppId,
 		s.Asset1.Id,
@@ -1115,13 +1325,14 @@ func (s *Pool) PrepareRedeemTransactions(amountOut *types.AssetAmount, userAddre
 		amountOut.Amount,
 		user.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 
 }
 
-func (s *Pool) PrepareLiquidityAssetOptinTransactions(userAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareLiquidityAssetOptinTransactions(userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	if len(userAddress) == 0 {
 		userAddress = s.Client.UserAddress
@@ -1132,33 +1343,23 @@ func (s *Pool) PrepareLiquidityAssetOptinTransactions(userAddress string) (txnGr
 		return
 	}
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = optin.PrepareAssetOptinTransactions(
 		s.LiquidityAsset.Id,
 		user.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 
 }
 
-func (s *Pool) PrepareRedeemFeesTransactions(amount, creatorAddress, userAddress string) (txnGroup *utils.TransactionGroup, err error) {
+func (s *Pool) PrepareRedeemFeesTransactions(amount, creatorAddress, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	if len(userAddress) == 0 {
 		userAddress = s.Client.UserAddress
@@ -1169,27 +1370,16 @@ func (s *Pool) PrepareRedeemFeesTransactions(amount, creatorAddress, userAddress
 		return
 	}
 
-	creator, err := algoTypes.DecodeAddress(userAddress)
+	creator, err := algoTypes.DecodeAddress(creatorAddress)
 	if err != nil {
 		return
 	}
 
-	algoSuggestedParams, err := s.Client.SuggestedParams()
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
 	if err != nil {
 		return
 	}
 
-	suggestedParams := &types.SuggestedParams{
-		Fee:              int(algoSuggestedParams.Fee),
-		GenesisID:        algoSuggestedParams.GenesisID,
-		GenesisHash:      algoSuggestedParams.GenesisHash,
-		FirstRoundValid:  int(algoSuggestedParams.FirstRoundValid),
-		LastRoundValid:   int(algoSuggestedParams.LastRoundValid),
-		ConsensusVersion: algoSuggestedParams.ConsensusVersion,
-		FlatFee:          algoSuggestedParams.FlatFee,
-		MinFee:           int(algoSuggestedParams.MinFee),
-	}
-
 	txnGroup, err = fees.PrepareRedeemFeesTransactions(
 		s.ValidatorAppId,
 		s.Asset1.Id,
@@ -1199,35 +1389,17 @@ func (s *Pool) PrepareRedeemFeesTransactions(amount, creatorAddress, userAddress
 		creator.String(),
 		user.String(),
 		suggestedParams,
+		options,
 	)
 
 	return
 }
 
-func (s *Pool) GetMinimumBalance() int {
-
-	const (
-		MIN_BALANCE_PER_ACCOUNT       int = 100000
-		MIN_BALANCE_PER_ASSET         int = 100000
-		MIN_BALANCE_PER_APP           int = 100000
-		MIN_BALANCE_PER_APP_BYTESLICE int = 50000
-		MIN_BALANCE_PER_APP_UINT      int = 28500
-	)
-
-	var numAssets int
-	if s.Asset2.Id == 0 {
-		numAssets = 2
-	} else {
-		numAssets = 3
-	}
+// GetMinimumBalance returns the minimum balance of the pool account with the validator app schema of the pool.
+func (s *Pool) GetMinimumBalance() (minBalance int, err error) {
 
-	var numCreatedApps int = 0
-	var numLocalApps int = 1
-	var totalUnits int = 16
-	var totalByteslices int = 0
+	return bootstrap.GetPoolMinBalance(s.ValidatorAppId, s.Asset2.Id, "")
 
-	total := MIN_BALANCE_PER_ACCOUNT + (MIN_BALANCE_PER_ASSET * numAssets) + (MIN_BALANCE_PER_APP * (numCreatedApps + numLocalApps)) + MIN_BALANCE_PER_APP_UINT*totalUnits + MIN_BALANCE_PER_APP_BYTESLICE*totalByteslices
-	return total
 }
 
 func (s *Pool) FetchExcessAmounts(userAddress string) (excessAmounts map[int]string, err error) {
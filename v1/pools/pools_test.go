@@ -0,0 +1,392 @@
+package pools
+
+import (
+	b64 "encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/swap"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPoolInfoFromAccountInfo(t *testing.T) {
+
+	poolAddress, err := contracts.PoolAddress(1, 2, 0)
+	assert.Nil(t, err)
+
+	poolState := &state.PoolState{
+		Asset1Id:              2,
+		Asset2Id:              0,
+		Asset1Reserves:        1000000,
+		Asset2Reserves:        4000000,
+		IssuedLiquidity:       2000000,
+		UnclaimedProtocolFees: 7,
+		OutstandingAmounts:    map[int]uint64{2: 10, 0: 20, 3: 30},
+	}
+
+	accountInfo := models.Account{
+		Address:        poolAddress,
+		Amount:         5000000,
+		AppsLocalState: []models.ApplicationLocalState{{Id: 1, KeyValue: poolState.Encode()}},
+		CreatedAssets:  []models.Asset{{Index: 3, Params: models.AssetParams{Name: "TinymanPool1.1 TEST-ALGO"}}},
+	}
+
+	poolInfo, err := GetPoolInfoFromAccountInfo(accountInfo)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, poolInfo.LiquidityAssetId)
+	assert.Equal(t, "1000000", poolInfo.Asset1Reserves)
+	assert.Equal(t, "4000000", poolInfo.Asset2Reserves)
+	assert.Equal(t, "2000000", poolInfo.IssuedLiquidity)
+	assert.Equal(t, "7", poolInfo.UnclaimedProtocolFees)
+	assert.Equal(t, "10", poolInfo.OutstandingAsset1Amount)
+	assert.Equal(t, "20", poolInfo.OutstandingAsset2Amount)
+	assert.Equal(t, "30", poolInfo.OutstandingLiquidityAssetAmount)
+	assert.Equal(t, POOL_STATUS_ACTIVE, poolInfo.Status)
+
+}
+
+func TestPoolStatus(t *testing.T) {
+
+	assert.Equal(t, POOL_STATUS_NOT_CREATED, poolStatus(nil))
+	assert.Equal(t, POOL_STATUS_NOT_CREATED, poolStatus(&PoolInfo{IssuedLiquidity: "0"}))
+	assert.Equal(t, POOL_STATUS_EMPTY, poolStatus(&PoolInfo{LiquidityAssetId: 3, IssuedLiquidity: "0"}))
+	assert.Equal(t, POOL_STATUS_DRAINED, poolStatus(&PoolInfo{LiquidityAssetId: 3, IssuedLiquidity: "1000"}))
+	assert.Equal(t, POOL_STATUS_ACTIVE, poolStatus(&PoolInfo{LiquidityAssetId: 3, IssuedLiquidity: "1001"}))
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(nil, asset1, asset2, nil, false, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, POOL_STATUS_UNKNOWN, pool.Status)
+
+	err = pool.UpdateFromInfo(&PoolInfo{LiquidityAssetId: 3, IssuedLiquidity: "0", Asset1Reserves: "0", AlgoBalance: "0"})
+	assert.Nil(t, err)
+	assert.Equal(t, POOL_STATUS_EMPTY, pool.Status)
+	assert.True(t, pool.Exists)
+
+	_, err = pool.PrepareSwapTransactions(&types.AssetAmount{Asset: asset1, Amount: "10"}, &types.AssetAmount{Asset: asset2, Amount: "10"}, "fixed-input", "", nil)
+	statusErr := AsPoolStatusError(err)
+	assert.NotNil(t, statusErr)
+	assert.Equal(t, POOL_STATUS_EMPTY, statusErr.Status)
+	assert.Equal(t, "swap", statusErr.Operation)
+
+	_, err = pool.PrepareBootstrapTransactions("", nil)
+	assert.NotNil(t, AsPoolStatusError(err))
+
+	assert.Nil(t, AsPoolStatusError(fmt.Errorf("other error")))
+
+}
+
+func TestInitialMintAmounts(t *testing.T) {
+
+	asset1Amount, asset2Amount, err := initialMintAmounts(4, big.NewInt(1000000))
+	assert.Nil(t, err)
+	assert.Equal(t, "500500", asset1Amount.String())
+	assert.Equal(t, "2002000", asset2Amount.String())
+
+	for _, price := range []float64{0.3, 1, 7.77, 1e-6, 123456.789} {
+
+		asset1Amount, asset2Amount, err = initialMintAmounts(price, big.NewInt(999999))
+		assert.Nil(t, err)
+
+		issuedLiquidity := new(big.Int).Sqrt(new(big.Int).Mul(asset1Amount, asset2Amount))
+		assert.True(t, issuedLiquidity.Cmp(big.NewInt(999999+1000)) >= 0)
+
+		actualPrice, _ := new(big.Float).Quo(new(big.Float).SetInt(asset2Amount), new(big.Float).SetInt(asset1Amount)).Float64()
+		assert.InEpsilon(t, price, actualPrice, 0.01)
+
+	}
+
+	_, _, err = initialMintAmounts(0, big.NewInt(1))
+	assert.NotNil(t, err)
+
+	_, _, err = initialMintAmounts(1, big.NewInt(0))
+	assert.NotNil(t, err)
+
+}
+
+func TestInverseQuotes(t *testing.T) {
+
+	asset1 := &types.Asset{Id: 2}
+	asset2 := &types.Asset{Id: 0}
+	liquidityAsset := &types.Asset{Id: 3}
+
+	pool := &Pool{
+		Asset1:          asset1,
+		Asset2:          asset2,
+		LiquidityAsset:  liquidityAsset,
+		Asset1Reserves:  "1000003",
+		Asset2Reserves:  "4000007",
+		IssuedLiquidity: "2000001",
+		Status:          POOL_STATUS_ACTIVE,
+	}
+
+	burnQuote, err := pool.burnQuoteForAssetAmount(&types.AssetAmount{Asset: asset1, Amount: "500"}, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, "1000", burnQuote.LiquidityAssetAmount.Amount)
+	assert.Equal(t, "500", burnQuote.GetAmountsOut()[2])
+	assert.Equal(t, "2000", burnQuote.GetAmountsOut()[0])
+
+	// one liquidity less returns less than asked for
+	asset1Amount, _ := pool.burnAmounts(big.NewInt(999))
+	assert.Equal(t, "499", asset1Amount.String())
+
+	_, err = pool.burnQuoteForAssetAmount(&types.AssetAmount{Asset: asset1, Amount: "1000003"}, 0.01)
+	assert.NotNil(t, AsPoolStatusError(err))
+
+	_, err = pool.burnQuoteForAssetAmount(&types.AssetAmount{Asset: liquidityAsset, Amount: "500"}, 0.01)
+	assert.NotNil(t, err)
+
+	mintQuote, err := pool.mintQuoteForLiquidity(&types.AssetAmount{Asset: liquidityAsset, Amount: "20000"}, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, "10001", mintQuote.GetAmountsIn()[2])
+	assert.Equal(t, "40001", mintQuote.GetAmountsIn()[0])
+	assert.Equal(t, "20000", mintQuote.LiquidityAssetAmount.Amount)
+
+	// one less of either asset issues less than asked for
+	assert.Equal(t, "19999", pool.mintLiquidity(big.NewInt(10000), big.NewInt(40001)).String())
+	assert.Equal(t, "19999", pool.mintLiquidity(big.NewInt(10001), big.NewInt(40000)).String())
+
+}
+
+func TestExcessPrediction(t *testing.T) {
+
+	asset1 := &types.Asset{Id: 2}
+	asset2 := &types.Asset{Id: 0}
+	liquidityAsset := &types.Asset{Id: 3}
+
+	pool := &Pool{
+		Asset1:          asset1,
+		Asset2:          asset2,
+		LiquidityAsset:  liquidityAsset,
+		Asset1Reserves:  "1000000",
+		Asset2Reserves:  "4000000",
+		IssuedLiquidity: "2000000",
+		Status:          POOL_STATUS_ACTIVE,
+	}
+
+	mintQuote := &MintQuote{
+		amountsIn:            map[int]string{2: "10000", 0: "50000"},
+		LiquidityAssetAmount: &types.AssetAmount{Asset: liquidityAsset, Amount: pool.mintLiquidity(big.NewInt(10000), big.NewInt(50000)).String()},
+		Slippage:             0.01,
+	}
+
+	err := pool.setMintExcess(mintQuote)
+	assert.Nil(t, err)
+	assert.Equal(t, "20000", mintQuote.LiquidityAssetAmount.Amount)
+	assert.Equal(t, map[int]string{2: "0", 0: "10000", 3: "200"}, mintQuote.GetExpectedExcess())
+	assert.Equal(t, map[int]string{2: "2160", 0: "10796", 3: "0"}, mintQuote.GetWorstCaseExcess())
+
+	pool.IssuedLiquidity = "0"
+	err = pool.setMintExcess(mintQuote)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]string{2: "0", 0: "0", 3: "0"}, mintQuote.GetExpectedExcess())
+
+	swapQuote := &SwapQuote{
+		SwapType:  "fixed-input",
+		AmountIn:  &types.AssetAmount{Asset: asset1, Amount: "1000"},
+		AmountOut: &types.AssetAmount{Asset: asset2, Amount: "3000"},
+		Slippage:  0.01,
+	}
+
+	err = swapQuote.setExcess()
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AssetAmount{Asset: asset2, Amount: "30"}, swapQuote.ExpectedExcess)
+	assert.Equal(t, &types.AssetAmount{Asset: asset2, Amount: "0"}, swapQuote.WorstCaseExcess)
+
+	swapQuote.SwapType = "fixed-output"
+	err = swapQuote.setExcess()
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AssetAmount{Asset: asset1, Amount: "10"}, swapQuote.ExpectedExcess)
+
+}
+
+func TestExecutionReport(t *testing.T) {
+
+	asset1 := &types.Asset{Id: 2}
+	asset2 := &types.Asset{Id: 0}
+	liquidityAsset := &types.Asset{Id: 3}
+
+	pool := &Pool{
+		ValidatorAppId:  1,
+		Asset1:          asset1,
+		Asset2:          asset2,
+		LiquidityAsset:  liquidityAsset,
+		Asset1Reserves:  "1000000",
+		Asset2Reserves:  "4000000",
+		IssuedLiquidity: "2000000",
+		Status:          POOL_STATUS_ACTIVE,
+	}
+
+	poolAddress, err := pool.Address()
+	assert.Nil(t, err)
+
+	user := crypto.GenerateAccount().Address.String()
+
+	genesisHash, _ := b64.StdEncoding.DecodeString("f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk=")
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisHash,
+	}
+
+	txnGroup, err := swap.PrepareSwapTransactions(1, asset1.Id, asset2.Id, liquidityAsset.Id, asset2.Id, "10000", "990", "fixed-input", user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	quote := &SwapQuote{
+		SwapType:  "fixed-input",
+		AmountIn:  &types.AssetAmount{Asset: asset2, Amount: "10000"},
+		AmountOut: &types.AssetAmount{Asset: asset1, Amount: "1000"},
+		Slippage:  0.01,
+	}
+
+	excessKey, err := state.ExcessKey(poolAddress, asset1.Id)
+	assert.Nil(t, err)
+
+	// the validator computed 995, transferred 990 and added the difference to an excess of 2
+	appCall := models.Transaction{
+		ConfirmedRound: 10,
+		LocalStateDelta: []models.AccountStateDelta{
+			{Address: poolAddress, Delta: []models.EvalDeltaKeyValue{
+				{Key: state.EncodeKey([]byte(state.ASSET_1_RESERVES_KEY)), Value: models.EvalDelta{Action: 2, Uint: 999005}},
+				{Key: state.EncodeKey([]byte(state.ASSET_2_RESERVES_KEY)), Value: models.EvalDelta{Action: 2, Uint: 4010000}},
+			}},
+			{Address: user, Delta: []models.EvalDeltaKeyValue{
+				{Key: state.EncodeKey(excessKey), Value: models.EvalDelta{Action: 2, Uint: 7}},
+			}},
+		},
+	}
+
+	report, err := pool.executionReport("swap", txnGroup.GetTransactions(), appCall, map[int]string{asset1.Id: "2"})
+	assert.Nil(t, err)
+	setSwapRealized(report, quote)
+
+	assert.Equal(t, crypto.TransactionIDString(txnGroup.GetTransactions()[1]), report.TxId)
+	assert.Equal(t, 10, report.ConfirmedRound)
+	assert.Equal(t, map[int]string{0: "10000"}, report.GetAmountsIn())
+	assert.Equal(t, map[int]string{2: "990"}, report.GetAmountsOut())
+	assert.Equal(t, map[int]string{2: "5", 0: "0", 3: "0"}, report.GetExcess())
+	assert.Equal(t, "4000", report.Fees)
+	assert.Equal(t, "995", report.RealizedAmount.Amount)
+	assert.InDelta(t, 0.005, report.RealizedSlippage, 1e-9)
+	assert.Equal(t, "999005", report.Asset1Reserves)
+	assert.Equal(t, "4010000", report.Asset2Reserves)
+	assert.Equal(t, "2000000", report.IssuedLiquidity)
+	assert.Equal(t, user, report.UserAddress)
+
+	// a fee payer sends the Tinyman fee payment and pays the fees of the user transactions
+	feePayer := crypto.GenerateAccount().Address.String()
+
+	txnGroup, err = swap.PrepareSwapTransactions(1, asset1.Id, asset2.Id, liquidityAsset.Id, asset2.Id, "10000", "990", "fixed-input", user, suggestedParams, &types.TxnOptions{FeePayer: feePayer})
+	assert.Nil(t, err)
+	assert.Equal(t, feePayer, txnGroup.GetTransactions()[0].Sender.String())
+
+	report, err = pool.executionReport("swap", txnGroup.GetTransactions(), appCall, map[int]string{asset1.Id: "2"})
+	assert.Nil(t, err)
+	setSwapRealized(report, quote)
+
+	assert.Equal(t, user, report.UserAddress)
+	assert.Equal(t, crypto.TransactionIDString(txnGroup.GetTransactions()[1]), report.TxId)
+	assert.Equal(t, map[int]string{0: "10000"}, report.GetAmountsIn())
+	assert.Equal(t, map[int]string{2: "990"}, report.GetAmountsOut())
+	assert.Equal(t, map[int]string{2: "5", 0: "0", 3: "0"}, report.GetExcess())
+	assert.Equal(t, "4000", report.Fees)
+	assert.Equal(t, "995", report.RealizedAmount.Amount)
+
+	stats := &SlippageStats{}
+	stats.Add(report)
+	stats.Add(&ExecutionReport{RealizedSlippage: -0.001})
+	assert.Equal(t, 2, stats.Count)
+	assert.InDelta(t, 0.002, stats.Mean, 1e-9)
+	assert.InDelta(t, -0.001, stats.Min, 1e-9)
+	assert.InDelta(t, 0.005, stats.Max, 1e-9)
+
+}
+
+func TestClassifySwapFailure(t *testing.T) {
+
+	assert.Equal(t, "", ClassifySwapFailure(nil, 552635992))
+
+	slippage := errors.New("HTTP 400: TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=1211. Details: app=552635992, pc=1211, opcodes=")
+	assert.Equal(t, SWAP_FAILURE_SLIPPAGE, ClassifySwapFailure(slippage, 552635992))
+	assert.Equal(t, SWAP_FAILURE_OTHER, ClassifySwapFailure(slippage, 350338509))
+
+	legacy := errors.New("TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=1211. Details: pc=1211, opcodes=")
+	assert.Equal(t, SWAP_FAILURE_SLIPPAGE, ClassifySwapFailure(legacy, 552635992))
+
+	// only a failed assert of the validator can be slippage
+	reference := errors.New("HTTP 400: TransactionPool.Remember: transaction ABC: logic eval error: invalid Account reference XYZ. Details: app=552635992, pc=120, opcodes=")
+	assert.Equal(t, SWAP_FAILURE_OTHER, ClassifySwapFailure(reference, 552635992))
+
+	assert.Equal(t, SWAP_FAILURE_INSUFFICIENT_FUNDS, ClassifySwapFailure(errors.New("transaction ABC: overspend (account XYZ, data {...})"), 552635992))
+	assert.Equal(t, SWAP_FAILURE_EXPIRED, ClassifySwapFailure(errors.New("transaction ABC: txn dead: round 100 outside of 1--99"), 552635992))
+	assert.Equal(t, SWAP_FAILURE_OTHER, ClassifySwapFailure(errors.New("transaction ABC: rejected by logic"), 552635992))
+	assert.Equal(t, SWAP_FAILURE_OTHER, ClassifySwapFailure(errors.New("connection refused"), 552635992))
+
+}
+
+func TestBindQuote(t *testing.T) {
+
+	pool := &Pool{
+		ValidatorAppId: 1,
+		Asset1:         &types.Asset{Id: 2},
+		Asset2:         &types.Asset{Id: 0},
+	}
+
+	poolAddress, err := pool.Address()
+	assert.Nil(t, err)
+
+	suggestedParams := &types.SuggestedParams{FirstRoundValid: 100, LastRoundValid: 1100}
+	options := &types.TxnOptions{SuggestedParams: suggestedParams}
+
+	boundOptions, err := pool.bindQuote(90, poolAddress, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 100, boundOptions.SuggestedParams.FirstRoundValid)
+	assert.Equal(t, 120, boundOptions.SuggestedParams.LastRoundValid)
+	assert.Equal(t, 1100, suggestedParams.LastRoundValid)
+
+	options.ValidityWindow = 50
+	boundOptions, err = pool.bindQuote(90, poolAddress, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, boundOptions.ValidityWindow)
+
+	options.ValidityWindow = 10
+	boundOptions, err = pool.bindQuote(90, poolAddress, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, boundOptions.ValidityWindow)
+
+	_, err = pool.bindQuote(60, poolAddress, options)
+	assert.NotNil(t, err)
+
+	options.MaxQuoteAge = 50
+	_, err = pool.bindQuote(60, poolAddress, options)
+	assert.Nil(t, err)
+
+	options.MaxQuoteAge = -1
+	boundOptions, err = pool.bindQuote(60, poolAddress, options)
+	assert.Nil(t, err)
+	assert.Equal(t, options, boundOptions)
+
+	// quotes without a round are not bound
+	boundOptions, err = pool.bindQuote(0, "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, boundOptions)
+
+	otherAddress, err := contracts.PoolAddress(1, 5, 0)
+	assert.Nil(t, err)
+	_, err = pool.bindQuote(90, otherAddress, options)
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,204 @@
+package pools
+
+import (
+	"strconv"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// preparePrerequisiteTransactions returns one atomic group with the validator app and asset opt-ins
+// that userAddress is missing, and the minimum balance increase they cause.
+// txnGroup is nil when the account has nothing to opt into.
+// The lease of options is kept for the operation group and is not set on the opt-ins.
+func (s *Pool) preparePrerequisiteTransactions(userAddress string, assetIDs []int, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, minBalanceIncrease int, err error) {
+
+	_, accountInfo, err := s.Client.LookupAccountByID(userAddress)
+	if err != nil {
+		return
+	}
+
+	optinOptions := withoutLease(options)
+
+	appOptedIn := false
+	for _, a := range accountInfo.AppsLocalState {
+		if a.Id == uint64(s.ValidatorAppId) {
+			appOptedIn = true
+		}
+	}
+
+	assetsOptedIn := make(map[int]bool)
+	for _, a := range accountInfo.Assets {
+		assetsOptedIn[int(a.AssetId)] = true
+	}
+
+	var groups []*utils.TransactionGroup
+
+	if !appOptedIn {
+
+		var appOptinGroup *utils.TransactionGroup
+		appOptinGroup, err = s.Client.PrepareAppOptinTransactions(userAddress, optinOptions)
+		if err != nil {
+			return
+		}
+
+		groups = append(groups, appOptinGroup)
+
+		var increase int
+		increase, err = appOptinMinBalance(s.ValidatorAppId)
+		if err != nil {
+			return
+		}
+
+		minBalanceIncrease += increase
+
+	}
+
+	for _, assetID := range assetIDs {
+
+		if assetID == 0 || assetsOptedIn[assetID] {
+			continue
+		}
+
+		var assetOptinGroup *utils.TransactionGroup
+		assetOptinGroup, err = s.Client.PrepareAssetOptinTransactions(assetID, userAddress, optinOptions)
+		if err != nil {
+			return
+		}
+
+		groups = append(groups, assetOptinGroup)
+		assetsOptedIn[assetID] = true
+		minBalanceIncrease += types.GetConsensusParams("").AssetMinBalance()
+
+	}
+
+	if len(groups) == 0 {
+		return
+	}
+
+	txnGroup, err = utils.MergeTransactionGroups(groups...)
+
+	return
+
+}
+
+// withoutLease returns a copy of options without the lease, for the groups of a plan other than the operation group.
+// A lease can only be used by one transaction of the sender until it expires.
+func withoutLease(options *types.TxnOptions) *types.TxnOptions {
+
+	if options == nil {
+		return nil
+	}
+
+	optionsCopy := *options
+	optionsCopy.Lease = nil
+
+	return &optionsCopy
+
+}
+
+func appOptinMinBalance(validatorAppId int) (minBalance int, err error) {
+
+	validatorApp, err := contracts.GetValidatorAppOf(validatorAppId)
+	if err != nil {
+		return
+	}
+
+	schema := validatorApp.LocalStateSchema
+	minBalance = types.GetConsensusParams("").AppOptinMinBalance(schema.NumUints, schema.NumByteSlices)
+
+	return
+
+}
+
+// preparePlanWithPrerequisites returns an ordered plan with the missing opt-ins, as one atomic group,
+// followed by the operation group.
+func (s *Pool) preparePlanWithPrerequisites(userAddress string, assetIDs []int, name, description string, txnGroup *utils.TransactionGroup, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	prerequisites, minBalanceIncrease, err := s.preparePrerequisiteTransactions(userAddress, assetIDs, options)
+	if err != nil {
+		return
+	}
+
+	plan = utils.NewTransactionPlan()
+
+	if prerequisites != nil {
+		plan.AddStep("optin", "opt into the validator app and assets", prerequisites)
+		plan.MinBalanceIncrease = strconv.Itoa(minBalanceIncrease)
+	}
+
+	plan.AddStep(name, description, txnGroup)
+
+	return
+
+}
+
+func (s *Pool) PrepareSwapTransactionsFromQuoteWithOptins(quote *SwapQuote, swapperAddress string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	if len(swapperAddress) == 0 {
+		swapperAddress = s.Client.UserAddress
+	}
+
+	swapper, err := algoTypes.DecodeAddress(swapperAddress)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := s.PrepareSwapTransactionsFromQuote(quote, swapper.String(), options)
+	if err != nil {
+		return
+	}
+
+	plan, err = s.preparePlanWithPrerequisites(swapper.String(), []int{quote.AmountOut.Asset.Id}, "swap", "swap assets", txnGroup, options)
+	if err != nil {
+		return
+	}
+
+	err = s.addIntegratorFeeStep(plan, quote, swapper.String(), options)
+
+	return
+
+}
+
+func (s *Pool) PrepareMintTransactionsFromQuoteWithOptins(quote *MintQuote, poolerAddress string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	if len(poolerAddress) == 0 {
+		poolerAddress = s.Client.UserAddress
+	}
+
+	pooler, err := algoTypes.DecodeAddress(poolerAddress)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := s.PrepareMintTransactionsFromQuote(quote, pooler.String(), options)
+	if err != nil {
+		return
+	}
+
+	return s.preparePlanWithPrerequisites(pooler.String(), []int{s.LiquidityAsset.Id}, "mint", "add liquidity", txnGroup, options)
+
+}
+
+func (s *Pool) PrepareBurnTransactionsFromQuoteWithOptins(quote *BurnQuote, poolerAddress string, options *types.TxnOptions) (plan *utils.TransactionPlan, err error) {
+
+	if len(poolerAddress) == 0 {
+		poolerAddress = s.Client.UserAddress
+	}
+
+	pooler, err := algoTypes.DecodeAddress(poolerAddress)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err := s.PrepareBurnTransactionsFromQuote(quote, pooler.String(), options)
+	if err != nil {
+		return
+	}
+
+	return s.preparePlanWithPrerequisites(pooler.String(), []int{s.Asset1.Id, s.Asset2.Id}, "burn", "remove liquidity", txnGroup, options)
+
+}
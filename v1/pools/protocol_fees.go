@@ -0,0 +1,250 @@
+package pools
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/fees"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// CollectedProtocolFees are the protocol fees redeemed from a pool so far.
+type CollectedProtocolFees struct {
+	PoolAddress      string `json:"pool-address"`
+	LiquidityAssetId int    `json:"liquidity-asset-id"`
+	// Amount is the total liquidity asset amount transferred to the fee recipient by redeem fees groups.
+	Amount      string `json:"amount"`
+	Redemptions int    `json:"redemptions"`
+	LastRound   int    `json:"last-round"`
+}
+
+// ProtocolFees are the unclaimed protocol fees of a pool.
+type ProtocolFees struct {
+	Pool *PoolInfo `json:"pool"`
+	// Unclaimed is the liquidity asset amount that a redeem fees group transfers to the fee recipient.
+	Unclaimed string `json:"unclaimed"`
+	// Asset1Amount and Asset2Amount are the amounts burning Unclaimed would return at the current reserves.
+	Asset1Amount string `json:"asset1-amount"`
+	Asset2Amount string `json:"asset2-amount"`
+	// Collected is nil unless the history was asked for.
+	Collected *CollectedProtocolFees `json:"collected"`
+}
+
+type ProtocolFeesScanResult struct {
+	// Plan has one redeem fees group per pool with unclaimed fees. The steps do not depend on each other.
+	Plan  *utils.TransactionPlan `json:"-"`
+	Pools []*ProtocolFees        `json:"pools"`
+}
+
+func (s *ProtocolFeesScanResult) PoolsLen() int {
+	return len(s.Pools)
+}
+
+func (s *ProtocolFeesScanResult) GetPool(index int) *ProtocolFees {
+	return s.Pools[index]
+}
+
+// protocolFeesValue returns the amounts of the pool assets that burning the unclaimed protocol fees would return,
+// rounded down like the burn of the validator.
+func protocolFeesValue(poolInfo *PoolInfo) (asset1Amount, asset2Amount *big.Int) {
+
+	unclaimed := utils.NewBigIntString(poolInfo.UnclaimedProtocolFees)
+	issuedLiquidity := utils.NewBigIntString(poolInfo.IssuedLiquidity)
+
+	if issuedLiquidity.Sign() == 0 {
+		return big.NewInt(0), big.NewInt(0)
+	}
+
+	asset1Amount = new(big.Int).Mul(unclaimed, utils.NewBigIntString(poolInfo.Asset1Reserves))
+	asset1Amount.Quo(asset1Amount, issuedLiquidity)
+
+	asset2Amount = new(big.Int).Mul(unclaimed, utils.NewBigIntString(poolInfo.Asset2Reserves))
+	asset2Amount.Quo(asset2Amount, issuedLiquidity)
+
+	return
+
+}
+
+// collectedProtocolFees sums the liquidity asset transfers of the pool to creatorAddress that are in the same group
+// as a "fees" call of the validator app.
+func collectedProtocolFees(poolInfo *PoolInfo, creatorAddress string, appCalls, assetTransfers []models.Transaction) (collected *CollectedProtocolFees) {
+
+	feesGroups := make(map[string]bool)
+
+	for _, txn := range appCalls {
+
+		args := txn.ApplicationTransaction.ApplicationArgs
+		if len(txn.Group) > 0 && len(args) > 0 && string(args[0]) == "fees" {
+			feesGroups[string(txn.Group)] = true
+		}
+
+	}
+
+	amount := new(big.Int)
+
+	collected = &CollectedProtocolFees{
+		PoolAddress:      poolInfo.Address,
+		LiquidityAssetId: poolInfo.LiquidityAssetId,
+	}
+
+	for _, txn := range assetTransfers {
+
+		transfer := txn.AssetTransferTransaction
+
+		if !feesGroups[string(txn.Group)] || transfer.AssetId != uint64(poolInfo.LiquidityAssetId) || transfer.Receiver != creatorAddress {
+			continue
+		}
+
+		amount.Add(amount, new(big.Int).SetUint64(transfer.Amount))
+		collected.Redemptions++
+
+		if int(txn.ConfirmedRound) > collected.LastRound {
+			collected.LastRound = int(txn.ConfirmedRound)
+		}
+
+	}
+
+	collected.Amount = amount.String()
+
+	return
+
+}
+
+// FetchCollectedProtocolFees returns the protocol fees redeemed from the pool of poolInfo to creatorAddress so far,
+// from the transaction history of the indexer.
+func FetchCollectedProtocolFees(tinymanClient *client.TinymanClient, poolInfo *PoolInfo, creatorAddress string) (collected *CollectedProtocolFees, err error) {
+
+	creator, err := algoTypes.DecodeAddress(creatorAddress)
+	if err != nil {
+		return
+	}
+
+	// the app call and the liquidity asset transfer of a redeem fees group are both sent by the pool
+	appCalls, err := tinymanClient.SearchAccountTransactions(poolInfo.Address, "appl", poolInfo.ValidatorAppId, 0)
+	if err != nil {
+		return
+	}
+
+	assetTransfers, err := tinymanClient.SearchAccountTransactions(poolInfo.Address, "axfer", 0, poolInfo.LiquidityAssetId)
+	if err != nil {
+		return
+	}
+
+	collected = collectedProtocolFees(poolInfo, creator.String(), appCalls, assetTransfers)
+
+	return
+
+}
+
+// ScanProtocolFees finds the pools whose protocol fees go to creatorAddress and prepares one redeem fees group
+// per pool with unclaimed fees, sent by creatorAddress.
+//
+// The validator transfers the protocol fees of every pool to the creator of the validator app, so the pools are
+// the pools of the versions of the client validator that creatorAddress created. They are found among all the accounts
+// opted in to the validator, which is a long scan on a public network. Pools whose contract definitions are not
+// available (v1.0 pools, unless registered with contracts.RegisterContracts) are not recognized.
+// includeHistory also fetches the fees collected so far from each pool.
+func ScanProtocolFees(tinymanClient *client.TinymanClient, creatorAddress string, includeHistory bool, options *types.TxnOptions) (result *ProtocolFeesScanResult, err error) {
+
+	creator, err := algoTypes.DecodeAddress(creatorAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := tinymanClient.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	// a lease can only be used by one group, so the redeem fees groups are prepared without it
+	options = withoutLease(options)
+
+	result = &ProtocolFeesScanResult{Plan: utils.NewTransactionPlan()}
+
+	for _, validatorAppId := range validatorAppIds(tinymanClient.ValidatorAppId) {
+
+		var appCreator string
+		appCreator, err = tinymanClient.FetchAppCreator(validatorAppId)
+		if err != nil {
+			return
+		}
+
+		if appCreator != creator.String() {
+			continue
+		}
+
+		var accounts []models.Account
+		accounts, err = tinymanClient.SearchAppAccounts(validatorAppId)
+		if err != nil {
+			return
+		}
+
+		for _, accountInfo := range accounts {
+
+			// users are opted in to the validator too, their accounts are not pools
+			poolInfo, infoErr := GetPoolInfoFromAccountInfo(accountInfo)
+			if infoErr != nil || poolInfo == nil || poolInfo.ValidatorAppId != validatorAppId || poolInfo.Status == POOL_STATUS_NOT_CREATED {
+				continue
+			}
+
+			asset1Amount, asset2Amount := protocolFeesValue(poolInfo)
+
+			result.Pools = append(result.Pools, &ProtocolFees{
+				Pool:         poolInfo,
+				Unclaimed:    poolInfo.UnclaimedProtocolFees,
+				Asset1Amount: asset1Amount.String(),
+				Asset2Amount: asset2Amount.String(),
+			})
+
+		}
+
+	}
+
+	sort.Slice(result.Pools, func(i, j int) bool {
+		return result.Pools[i].Pool.Address < result.Pools[j].Pool.Address
+	})
+
+	for _, poolFees := range result.Pools {
+
+		poolInfo := poolFees.Pool
+
+		if includeHistory {
+			poolFees.Collected, err = FetchCollectedProtocolFees(tinymanClient, poolInfo, creator.String())
+			if err != nil {
+				return
+			}
+		}
+
+		if utils.NewBigIntString(poolFees.Unclaimed).Sign() == 0 {
+			continue
+		}
+
+		var txnGroup *utils.TransactionGroup
+		txnGroup, err = fees.PrepareRedeemFeesTransactions(
+			poolInfo.ValidatorAppId,
+			poolInfo.Asset1Id,
+			poolInfo.Asset2Id,
+			poolInfo.LiquidityAssetId,
+			poolFees.Unclaimed,
+			creator.String(),
+			creator.String(),
+			suggestedParams,
+			options,
+		)
+		if err != nil {
+			return
+		}
+
+		result.Plan.AddStep("redeem-fees", fmt.Sprintf("redeem %s of asset %d from pool %s", poolFees.Unclaimed, poolInfo.LiquidityAssetId, poolInfo.Address), txnGroup)
+
+	}
+
+	return
+
+}
@@ -0,0 +1,49 @@
+package pools
+
+import (
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtocolFees(t *testing.T) {
+
+	poolInfo := &PoolInfo{
+		Address:               "POOL",
+		LiquidityAssetId:      3,
+		Asset1Reserves:        "1000000",
+		Asset2Reserves:        "4000001",
+		IssuedLiquidity:       "2000000",
+		UnclaimedProtocolFees: "1001",
+	}
+
+	asset1Amount, asset2Amount := protocolFeesValue(poolInfo)
+	assert.Equal(t, "500", asset1Amount.String())
+	assert.Equal(t, "2002", asset2Amount.String())
+
+	feesGroup := []byte("fees-group")
+	mintGroup := []byte("mint-group")
+
+	appCalls := []models.Transaction{
+		{Group: feesGroup, ApplicationTransaction: models.TransactionApplication{ApplicationArgs: [][]byte{[]byte("fees")}}},
+		{Group: mintGroup, ApplicationTransaction: models.TransactionApplication{ApplicationArgs: [][]byte{[]byte("mint")}}},
+	}
+
+	assetTransfers := []models.Transaction{
+		{Group: feesGroup, ConfirmedRound: 10, AssetTransferTransaction: models.TransactionAssetTransfer{AssetId: 3, Amount: 700, Receiver: "CREATOR"}},
+		{Group: feesGroup, ConfirmedRound: 12, AssetTransferTransaction: models.TransactionAssetTransfer{AssetId: 3, Amount: 300, Receiver: "CREATOR"}},
+		// a mint of the creator is not a fee redemption
+		{Group: mintGroup, ConfirmedRound: 15, AssetTransferTransaction: models.TransactionAssetTransfer{AssetId: 3, Amount: 5000, Receiver: "CREATOR"}},
+		{Group: feesGroup, ConfirmedRound: 16, AssetTransferTransaction: models.TransactionAssetTransfer{AssetId: 3, Amount: 100, Receiver: "OTHER"}},
+	}
+
+	collected := collectedProtocolFees(poolInfo, "CREATOR", appCalls, assetTransfers)
+	assert.Equal(t, &CollectedProtocolFees{PoolAddress: "POOL", LiquidityAssetId: 3, Amount: "1000", Redemptions: 2, LastRound: 12}, collected)
+
+	poolInfo.IssuedLiquidity = "0"
+	asset1Amount, asset2Amount = protocolFeesValue(poolInfo)
+	assert.Equal(t, "0", asset1Amount.String())
+	assert.Equal(t, "0", asset2Amount.String())
+
+}
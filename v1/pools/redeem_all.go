@@ -0,0 +1,369 @@
+package pools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/redeem"
+
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// RedeemProgress receives the progress of PrepareRedeemAllTransactions, e.g. to update a progress bar.
+type RedeemProgress interface {
+	OnProgress(done, total int, message string)
+}
+
+type SkippedRedeem struct {
+	ValidatorAppId int    `json:"validator-app-id"`
+	PoolAddress    string `json:"pool-address"`
+	AssetId        int    `json:"asset-id"`
+	Amount         string `json:"amount"`
+	Reason         string `json:"reason"`
+}
+
+type RedeemAllResult struct {
+	// Plan has one redeem group per step. The steps do not depend on each other. They can not be packed in fewer
+	// groups: the pool logicsig asserts that a redeem group has exactly 3 transactions, and algod takes one group
+	// per submission.
+	Plan    *utils.TransactionPlan `json:"-"`
+	Skipped []*SkippedRedeem       `json:"skipped"`
+	// Dust has the excess amounts that are worth less than the fees of redeeming them.
+	Dust []*SkippedRedeem `json:"dust"`
+}
+
+func (s *RedeemAllResult) SkippedLen() int {
+	return len(s.Skipped)
+}
+
+func (s *RedeemAllResult) GetSkipped(index int) *SkippedRedeem {
+	return s.Skipped[index]
+}
+
+func (s *RedeemAllResult) DustLen() int {
+	return len(s.Dust)
+}
+
+func (s *RedeemAllResult) GetDust(index int) *SkippedRedeem {
+	return s.Dust[index]
+}
+
+// redeemCost is what a redeem group costs the user: the Tinyman fee payment and the fees of its two transactions.
+func redeemCost(suggestedParams *types.SuggestedParams, options *types.TxnOptions) int64 {
+
+	fee := types.GetConsensusParams(suggestedParams.ConsensusVersion).MinTxnFee
+
+	if options != nil && options.FlatFee > 0 {
+		fee = options.FlatFee
+	} else if suggestedParams.FlatFee && suggestedParams.Fee > fee {
+		fee = suggestedParams.Fee
+	}
+
+	return int64(2000 + 2*fee)
+
+}
+
+// validatorAppIds returns all the known versions of the validator of validatorAppId.
+func validatorAppIds(validatorAppId int) []int {
+
+	switch validatorAppId {
+	case constants.TESTNET_VALIDATOR_APP_ID_V1_0, constants.TESTNET_VALIDATOR_APP_ID_V1_1:
+		return []int{constants.TESTNET_VALIDATOR_APP_ID_V1_0, constants.TESTNET_VALIDATOR_APP_ID_V1_1}
+	case constants.MAINNET_VALIDATOR_APP_ID_V1_0, constants.MAINNET_VALIDATOR_APP_ID_V1_1:
+		return []int{constants.MAINNET_VALIDATOR_APP_ID_V1_0, constants.MAINNET_VALIDATOR_APP_ID_V1_1}
+	}
+
+	return []int{validatorAppId}
+
+}
+
+// excessValueInAlgo estimates the value of an excess amount in microAlgos from the pool reserves.
+// ok is false when the pool has no ALGO side to price the asset with.
+func excessValueInAlgo(poolInfo *PoolInfo, assetID int, amount *big.Int) (value *big.Int, ok bool) {
+
+	if assetID == 0 {
+		return amount, true
+	}
+
+	if poolInfo.Asset2Id != 0 {
+		return nil, false
+	}
+
+	algoReserves := utils.NewBigIntString(poolInfo.Asset2Reserves)
+
+	var supply *big.Int
+	if assetID == poolInfo.Asset1Id {
+		supply = utils.NewBigIntString(poolInfo.Asset1Reserves)
+	} else if assetID == poolInfo.LiquidityAssetId {
+		// one liquidity token is worth its share of both sides, i.e. twice its share of the ALGO side
+		supply = utils.NewBigIntString(poolInfo.IssuedLiquidity)
+		algoReserves = new(big.Int).Mul(algoReserves, big.NewInt(2))
+	} else {
+		return nil, false
+	}
+
+	if supply.Sign() == 0 {
+		return big.NewInt(0), true
+	}
+
+	value = new(big.Int).Mul(amount, algoReserves)
+	value.Quo(value, supply)
+
+	return value, true
+
+}
+
+// priceInAlgo is excessValueInAlgo for any pool: an asset of a pool without an ALGO side is priced through the
+// pool of the asset and ALGO returned by algoPool, a liquidity asset through both of its assets.
+// ok is false when an asset has no ALGO pool.
+func priceInAlgo(poolInfo *PoolInfo, assetID int, amount *big.Int, algoPool func(assetID int) *PoolInfo) (value *big.Int, ok bool) {
+
+	value, ok = excessValueInAlgo(poolInfo, assetID, amount)
+	if ok {
+		return
+	}
+
+	if assetID == poolInfo.Asset1Id || assetID == poolInfo.Asset2Id {
+
+		pool := algoPool(assetID)
+		if pool == nil {
+			return nil, false
+		}
+
+		return excessValueInAlgo(pool, assetID, amount)
+
+	}
+
+	if assetID != poolInfo.LiquidityAssetId {
+		return nil, false
+	}
+
+	issuedLiquidity := utils.NewBigIntString(poolInfo.IssuedLiquidity)
+	if issuedLiquidity.Sign() == 0 {
+		return big.NewInt(0), true
+	}
+
+	// one liquidity token is worth its share of both sides
+	asset1Amount := new(big.Int).Mul(amount, utils.NewBigIntString(poolInfo.Asset1Reserves))
+	asset1Amount.Quo(asset1Amount, issuedLiquidity)
+
+	asset2Amount := new(big.Int).Mul(amount, utils.NewBigIntString(poolInfo.Asset2Reserves))
+	asset2Amount.Quo(asset2Amount, issuedLiquidity)
+
+	asset1Value, ok := priceInAlgo(poolInfo, poolInfo.Asset1Id, asset1Amount, algoPool)
+	if !ok {
+		return nil, false
+	}
+
+	asset2Value, ok := priceInAlgo(poolInfo, poolInfo.Asset2Id, asset2Amount, algoPool)
+	if !ok {
+		return nil, false
+	}
+
+	return asset1Value.Add(asset1Value, asset2Value), true
+
+}
+
+// PrepareRedeemAllTransactions scans the excess amounts of userAddress in every version of the client validator
+// and prepares one redeem group per excess amount that is worth more than the fees of redeeming it. The other
+// excess amounts are reported as Dust.
+// Amounts in pools without an ALGO side are priced through the pool of their asset and ALGO of the client validator,
+// they are reported as skipped when there is no such pool.
+// The logicsigs of v1.0 pools are recovered from the transactions the pools signed, pools whose logicsig can not be
+// recovered are reported as skipped.
+// progress may be nil.
+func PrepareRedeemAllTransactions(tinymanClient *client.TinymanClient, userAddress string, progress RedeemProgress, options *types.TxnOptions) (result *RedeemAllResult, err error) {
+
+	if len(userAddress) == 0 {
+		userAddress = tinymanClient.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := tinymanClient.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	cost := big.NewInt(redeemCost(suggestedParams, options))
+
+	// a lease can only be used by one group, so the redeem groups are prepared without it
+	options = withoutLease(options)
+
+	type excess struct {
+		validatorAppId int
+		poolAddress    string
+		assetID        int
+		amount         string
+	}
+
+	var excesses []excess
+
+	for _, validatorAppId := range validatorAppIds(tinymanClient.ValidatorAppId) {
+
+		var excessAmountsStr string
+		excessAmountsStr, err = tinymanClient.FetchValidatorExcessAmounts(user.String(), validatorAppId)
+		if err != nil {
+			return
+		}
+
+		if len(excessAmountsStr) == 0 {
+			continue
+		}
+
+		excessAmounts := make(map[string]map[int]string)
+		err = json.Unmarshal([]byte(excessAmountsStr), &excessAmounts)
+		if err != nil {
+			return
+		}
+
+		for poolAddress, amounts := range excessAmounts {
+			for assetID, amount := range amounts {
+				excesses = append(excesses, excess{validatorAppId, poolAddress, assetID, amount})
+			}
+		}
+
+	}
+
+	sort.Slice(excesses, func(i, j int) bool {
+		if excesses[i].poolAddress != excesses[j].poolAddress {
+			return excesses[i].poolAddress < excesses[j].poolAddress
+		}
+		return excesses[i].assetID < excesses[j].assetID
+	})
+
+	result = &RedeemAllResult{Plan: utils.NewTransactionPlan()}
+	poolInfos := make(map[string]*PoolInfo)
+	algoPools := make(map[int]*PoolInfo)
+
+	// algoPool returns the active pool of assetID and ALGO, nil when there is none
+	algoPool := func(assetID int) *PoolInfo {
+
+		poolInfo, ok := algoPools[assetID]
+		if ok {
+			return poolInfo
+		}
+
+		poolInfo, lookupErr := GetPoolInfo(tinymanClient, tinymanClient.ValidatorAppId, assetID, 0)
+		if lookupErr != nil || poolInfo == nil || poolStatus(poolInfo) != POOL_STATUS_ACTIVE {
+			poolInfo = nil
+		}
+
+		algoPools[assetID] = poolInfo
+
+		return poolInfo
+
+	}
+
+	for i, e := range excesses {
+
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, &SkippedRedeem{e.validatorAppId, e.poolAddress, e.assetID, e.amount, reason})
+		}
+
+		poolInfo, ok := poolInfos[e.poolAddress]
+		if !ok {
+
+			_, accountInfo, lookupErr := tinymanClient.LookupAccountByID(e.poolAddress)
+			if lookupErr != nil {
+				err = lookupErr
+				return
+			}
+
+			// the pool address only matches when the contract definitions of its validator version are available
+			// or its logicsig is recovered from the chain
+			lookupErr = recoverPoolContracts(tinymanClient, accountInfo)
+			if lookupErr == nil {
+				poolInfo, lookupErr = GetPoolInfoFromAccountInfo(accountInfo)
+			}
+			if lookupErr != nil {
+				poolInfo = nil
+			}
+
+			poolInfos[e.poolAddress] = poolInfo
+
+		}
+
+		amount := utils.NewBigIntString(e.amount)
+
+		if poolInfo == nil {
+			skip(fmt.Sprintf("contract definition of validator %d is not available", e.validatorAppId))
+		} else if value, priced := priceInAlgo(poolInfo, e.assetID, amount, algoPool); !priced {
+			skip(fmt.Sprintf("asset %d can not be priced in ALGO, it has no ALGO pool", e.assetID))
+		} else if value.Cmp(cost) <= 0 {
+			result.Dust = append(result.Dust, &SkippedRedeem{e.validatorAppId, e.poolAddress, e.assetID, e.amount, fmt.Sprintf("worth %s microAlgos, redeeming costs %s", value.String(), cost.String())})
+		} else {
+
+			var txnGroup *utils.TransactionGroup
+			txnGroup, err = redeem.PrepareRedeemTransactions(
+				poolInfo.ValidatorAppId,
+				poolInfo.Asset1Id,
+				poolInfo.Asset2Id,
+				poolInfo.LiquidityAssetId,
+				e.assetID,
+				e.amount,
+				user.String(),
+				suggestedParams,
+				options,
+			)
+			if err != nil {
+				return
+			}
+
+			result.Plan.AddStep("redeem", fmt.Sprintf("redeem %s of asset %d from pool %s", e.amount, e.assetID, e.poolAddress), txnGroup)
+
+		}
+
+		if progress != nil {
+			progress.OnProgress(i+1, len(excesses), fmt.Sprintf("pool %s asset %d", e.poolAddress, e.assetID))
+		}
+
+	}
+
+	return
+
+}
+
+// ExecuteRedeemAll prepares the redeem groups of PrepareRedeemAllTransactions, signs them with signer and submits
+// them one by one, waiting for each one. progress receives the scan of the excess amounts and then every submitted
+// step. It stops at the first step that fails, result has the steps of the plan. progress may be nil.
+func ExecuteRedeemAll(tinymanClient *client.TinymanClient, userAddress string, signer utils.Signer, progress RedeemProgress, options *types.TxnOptions) (result *RedeemAllResult, err error) {
+
+	result, err = PrepareRedeemAllTransactions(tinymanClient, userAddress, progress, options)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < result.Plan.Len(); i++ {
+
+		step := result.Plan.GetStep(i)
+
+		err = step.GetTransactionGroup().Sign(signer)
+		if err != nil {
+			return
+		}
+
+		_, err = tinymanClient.Submit(step.GetTransactionGroup(), true)
+		if err != nil {
+			err = fmt.Errorf("step %d (%s) failed: %s", i, step.Name, err)
+			return
+		}
+
+		if progress != nil {
+			progress.OnProgress(i+1, result.Plan.Len(), step.Description)
+		}
+
+	}
+
+	return
+
+}
@@ -0,0 +1,181 @@
+package pools
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestExcessValueInAlgo(t *testing.T) {
+
+	poolInfo := &PoolInfo{
+		Asset1Id:         2,
+		Asset2Id:         0,
+		LiquidityAssetId: 3,
+		Asset1Reserves:   "1000000",
+		Asset2Reserves:   "4000000",
+		IssuedLiquidity:  "2000000",
+	}
+
+	value, ok := excessValueInAlgo(poolInfo, 0, big.NewInt(1500))
+	assert.True(t, ok)
+	assert.Equal(t, "1500", value.String())
+
+	value, ok = excessValueInAlgo(poolInfo, 2, big.NewInt(1000))
+	assert.True(t, ok)
+	assert.Equal(t, "4000", value.String())
+
+	value, ok = excessValueInAlgo(poolInfo, 3, big.NewInt(1000))
+	assert.True(t, ok)
+	assert.Equal(t, "4000", value.String())
+
+	poolInfo.Asset2Id = 5
+	_, ok = excessValueInAlgo(poolInfo, 2, big.NewInt(1000))
+	assert.False(t, ok)
+
+	// the assets of a pool without an ALGO side are priced through their ALGO pools
+	algoPools := map[int]*PoolInfo{
+		2: {Asset1Id: 2, Asset2Id: 0, LiquidityAssetId: 6, Asset1Reserves: "1000000", Asset2Reserves: "3000000", IssuedLiquidity: "1000000"},
+	}
+	algoPool := func(assetID int) *PoolInfo {
+		return algoPools[assetID]
+	}
+
+	value, ok = priceInAlgo(poolInfo, 2, big.NewInt(1000), algoPool)
+	assert.True(t, ok)
+	assert.Equal(t, "3000", value.String())
+
+	_, ok = priceInAlgo(poolInfo, 5, big.NewInt(1000), algoPool)
+	assert.False(t, ok)
+
+	_, ok = priceInAlgo(poolInfo, 3, big.NewInt(1000), algoPool)
+	assert.False(t, ok)
+
+	algoPools[5] = &PoolInfo{Asset1Id: 5, Asset2Id: 0, LiquidityAssetId: 7, Asset1Reserves: "1000000", Asset2Reserves: "500000", IssuedLiquidity: "1000000"}
+
+	value, ok = priceInAlgo(poolInfo, 5, big.NewInt(1000), algoPool)
+	assert.True(t, ok)
+	assert.Equal(t, "500", value.String())
+
+	// 1000 liquidity tokens are 500 of asset 2 and 2000 of asset 5
+	value, ok = priceInAlgo(poolInfo, 3, big.NewInt(1000), algoPool)
+	assert.True(t, ok)
+	assert.Equal(t, "2500", value.String())
+
+	suggestedParams := &types.SuggestedParams{Fee: 0, MinFee: 1000}
+	assert.Equal(t, int64(4000), redeemCost(suggestedParams, nil))
+	assert.Equal(t, int64(6000), redeemCost(suggestedParams, &types.TxnOptions{FlatFee: 2000}))
+
+}
+
+type progressRecorder struct {
+	messages []string
+}
+
+func (s *progressRecorder) OnProgress(done, total int, message string) {
+	s.messages = append(s.messages, fmt.Sprintf("%d/%d %s", done, total, message))
+}
+
+func TestExecuteRedeemAll(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	account := crypto.GenerateAccount()
+	user := account.Address.String()
+
+	legacyProgram := legacyPoolProgram(constants.TESTNET_VALIDATOR_APP_ID_V1_0, 2, 0)
+	legacyPoolAddress := crypto.AddressFromProgram(legacyProgram).String()
+
+	// an excess amount in a v1.0 pool, whose logicsig is recovered from a transaction the pool signed
+	userState := &state.UserState{ExcessAmounts: map[string]map[int]uint64{legacyPoolAddress: {0: 1000000}}}
+	keyValues, err := userState.Encode()
+	assert.Nil(t, err)
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", user)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        user,
+			AppsLocalState: []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID_V1_0, KeyValue: keyValues}},
+		},
+	})
+
+	poolState := &state.PoolState{Asset1Id: 2, Asset2Id: 0, Asset1Reserves: 1000000, Asset2Reserves: 1000000, IssuedLiquidity: 1000000}
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", legacyPoolAddress)).
+		Reply(200).JSON(map[string]interface{}{
+		"account": models.Account{
+			Address:        legacyPoolAddress,
+			AppsLocalState: []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID_V1_0, KeyValue: poolState.Encode()}},
+			CreatedAssets:  []models.Asset{{Index: 5}},
+		},
+	})
+
+	gock.New(indexerURL).Get("/v2/transactions").MatchParam("address", legacyPoolAddress).
+		Reply(200).JSON(map[string]interface{}{
+		"transactions": []models.Transaction{{Signature: models.TransactionSignature{Logicsig: models.TransactionSignatureLogicsig{Logic: legacyProgram}}}},
+	})
+
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/applications/%d", constants.TESTNET_VALIDATOR_APP_ID_V1_0)).
+		Reply(200).JSON(models.Application{
+		Id:     constants.TESTNET_VALIDATOR_APP_ID_V1_0,
+		Params: models.ApplicationParams{LocalStateSchema: models.ApplicationStateSchema{NumUint: 16}},
+	})
+
+	gock.New(algodURL).Post("/v2/transactions").
+		Reply(200).JSON(map[string]string{"txId": "TXID"})
+
+	gock.New(algodURL).Get("/v2/status").
+		Reply(200).JSON(map[string]interface{}{"last-round": 100})
+
+	gock.New(algodURL).Get("/v2/transactions/pending/TXID").
+		Reply(200).Body(bytes.NewReader(msgpack.Encode(models.PendingTransactionInfoResponse{ConfirmedRound: 101})))
+
+	tinymanClient, err := client.NewTinymanTestnetClient(algodURL, indexerURL, user)
+	assert.Nil(t, err)
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}}
+
+	progress := &progressRecorder{}
+	signer := &fakeSigner{account: account}
+
+	result, err := ExecuteRedeemAll(tinymanClient, user, signer, progress, options)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 0, result.SkippedLen())
+	assert.Equal(t, 1, result.Plan.Len())
+	assert.Equal(t, "redeem", result.Plan.GetStep(0).Name)
+	assert.Equal(t, 1, signer.signed)
+
+	// the pool transactions of the redeem are signed with the recovered logicsig
+	txnGroup := result.Plan.GetStep(0).GetTransactionGroup()
+	poolSigned := 0
+	for i, txn := range txnGroup.GetTransactions() {
+		if txn.Sender.String() == legacyPoolAddress {
+			assert.NotNil(t, txnGroup.GetSignedTransactions()[i])
+			poolSigned++
+		}
+	}
+	assert.Equal(t, 2, poolSigned)
+
+	// the scan of the excess amount and then the submitted step
+	assert.Equal(t, []string{
+		fmt.Sprintf("1/1 pool %s asset 0", legacyPoolAddress),
+		"1/1 " + result.Plan.GetStep(0).Description,
+	}, progress.messages)
+
+}
@@ -0,0 +1,243 @@
+package pools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+)
+
+// Classes of the rejection of a swap group by algod.
+const (
+	SWAP_FAILURE_SLIPPAGE           = "slippage"
+	SWAP_FAILURE_INSUFFICIENT_FUNDS = "insufficient-funds"
+	SWAP_FAILURE_EXPIRED            = "expired"
+	SWAP_FAILURE_OTHER              = "other"
+)
+
+// ClassifySwapFailure classifies the error algod returned for a swap group of the validator app validatorAppId.
+// The validator rejects a swap whose amounts are not within the reserves it computes with an assert of the app call,
+// so a failed assert of the validator app is classified as slippage. The validator also asserts the pool and the
+// arguments of the group, SwapWithRetry re-quotes a slippage failure to tell them apart. Other logic eval errors of the
+// validator, e.g. an invalid reference, and errors of the pool logicsig are not slippage.
+func ClassifySwapFailure(err error, validatorAppId int) string {
+
+	if err == nil {
+		return ""
+	}
+
+	message := err.Error()
+
+	switch {
+	case strings.Contains(message, "overspend"), strings.Contains(message, "below min"), strings.Contains(message, "underflow on subtracting"):
+		return SWAP_FAILURE_INSUFFICIENT_FUNDS
+	case strings.Contains(message, "txn dead"):
+		return SWAP_FAILURE_EXPIRED
+	case strings.Contains(message, "rejected by logic"):
+		return SWAP_FAILURE_OTHER
+	case strings.Contains(message, "logic eval error: assert failed"):
+		// older algod versions do not name the app that failed
+		if strings.Contains(message, "app=") && !strings.Contains(message, fmt.Sprintf("app=%d", validatorAppId)) {
+			return SWAP_FAILURE_OTHER
+		}
+		return SWAP_FAILURE_SLIPPAGE
+	}
+
+	return SWAP_FAILURE_OTHER
+
+}
+
+// quoteMoved reports whether the swap of quote is not within the slippage of previous, i.e. whether the reserves
+// moved enough between the two quotes for the validator to reject the swap of previous.
+func quoteMoved(previous, quote *SwapQuote) (moved bool, err error) {
+
+	if quote.SwapType == "fixed-output" {
+
+		var maxAmountIn *types.AssetAmount
+		maxAmountIn, err = previous.AmountInWithSlippage()
+		if err != nil {
+			return
+		}
+
+		moved = utils.NewBigIntString(quote.AmountIn.Amount).Cmp(utils.NewBigIntString(maxAmountIn.Amount)) > 0
+		return
+
+	}
+
+	minAmountOut, err := previous.AmountOutWithSlippage()
+	if err != nil {
+		return
+	}
+
+	moved = utils.NewBigIntString(quote.AmountOut.Amount).Cmp(utils.NewBigIntString(minAmountOut.Amount)) < 0
+
+	return
+
+}
+
+// SwapAttempt is an attempt of SwapWithRetry. Failure is empty when the attempt succeeded.
+type SwapAttempt struct {
+	Attempt int        `json:"attempt"`
+	Quote   *SwapQuote `json:"quote"`
+	TxId    string     `json:"tx-id,omitempty"`
+	Failure string     `json:"failure,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+// SwapAttemptCallback receives every attempt of SwapWithRetry, e.g. to show that the swap is retried.
+type SwapAttemptCallback interface {
+	OnAttempt(attempt *SwapAttempt)
+}
+
+type SwapRetryResult struct {
+	// Quote is the quote of the successful attempt.
+	Quote                  *SwapQuote                    `json:"quote"`
+	TransactionInformation *types.TransactionInformation `json:"transaction-information"`
+	Attempts               []*SwapAttempt                `json:"attempts"`
+}
+
+func (s *SwapRetryResult) AttemptsLen() int {
+	return len(s.Attempts)
+}
+
+func (s *SwapRetryResult) GetAttempt(index int) *SwapAttempt {
+	return s.Attempts[index]
+}
+
+// requote fetches a quote of the same swap as quote at the current reserves. It requests the amount quote was
+// requested for, the fixed amount of quote already has the integrator fee taken off or added.
+func (s *Pool) requote(quote *SwapQuote) (*SwapQuote, error) {
+
+	if quote.SwapType == "fixed-output" {
+		if quote.RequestedAmount != nil {
+			return s.FetchFixedOutputSwapQuote(quote.RequestedAmount, quote.Slippage)
+		}
+		return s.FetchFixedOutputSwapQuote(quote.AmountOut, quote.Slippage)
+	}
+
+	if quote.RequestedAmount != nil {
+		return s.FetchFixedInputSwapQuote(quote.RequestedAmount, quote.Slippage)
+	}
+
+	return s.FetchFixedInputSwapQuote(quote.AmountIn, quote.Slippage)
+
+}
+
+// SwapWithRetry prepares the swap of quote, signs it with signer and submits it. When the validator rejects the swap
+// and the re-quoted swap at the refreshed reserves is no longer within the slippage of the rejected one, the swap is
+// re-signed and resubmitted with the new quote, up to maxRetries times. A quote that is too old to be prepared is
+// re-quoted without counting as an attempt. A quote whose price with slippage (output per input) is below worstPrice
+// is not submitted. Other failures are returned right away. The integrator fee of the quote, if any, is paid after
+// the swap is confirmed, not atomically with it. callback may be nil.
+func (s *Pool) SwapWithRetry(quote *SwapQuote, worstPrice float64, maxRetries int, swapperAddress string, signer utils.Signer, callback SwapAttemptCallback, options *types.TxnOptions) (result *SwapRetryResult, err error) {
+
+	result = &SwapRetryResult{}
+
+	for attempt := 1; attempt <= maxRetries+1; attempt++ {
+
+		var plan *utils.TransactionPlan
+		plan, err = s.PrepareSwapPlanFromQuote(quote, swapperAddress, options)
+
+		if AsStaleQuoteError(err) != nil {
+			quote, err = s.requote(quote)
+			if err != nil {
+				return
+			}
+			plan, err = s.PrepareSwapPlanFromQuote(quote, swapperAddress, options)
+		}
+
+		if err != nil {
+			return
+		}
+
+		var price float64
+		price, err = quote.PriceWithSlippage()
+		if err != nil {
+			return
+		}
+
+		if price < worstPrice {
+			err = fmt.Errorf("price %g of attempt %d is below the worst acceptable price %g", price, attempt, worstPrice)
+			return
+		}
+
+		for i := 0; i < plan.Len(); i++ {
+			err = plan.GetStep(i).GetTransactionGroup().Sign(signer)
+			if err != nil {
+				return
+			}
+		}
+
+		swapAttempt := &SwapAttempt{Attempt: attempt, Quote: quote}
+		result.Attempts = append(result.Attempts, swapAttempt)
+
+		var transactionInformation *types.TransactionInformation
+		transactionInformation, err = s.Client.Submit(plan.GetStep(0).GetTransactionGroup(), true)
+
+		var requoted *SwapQuote
+
+		if err != nil {
+
+			swapAttempt.Failure = ClassifySwapFailure(err, s.ValidatorAppId)
+			swapAttempt.Message = err.Error()
+
+			if swapAttempt.Failure == SWAP_FAILURE_SLIPPAGE {
+
+				var requoteErr error
+				requoted, requoteErr = s.requote(quote)
+				if requoteErr != nil {
+					err = requoteErr
+					return
+				}
+
+				// a swap that is still within the slippage at the current reserves failed another assert
+				var moved bool
+				moved, requoteErr = quoteMoved(quote, requoted)
+				if requoteErr != nil {
+					err = requoteErr
+					return
+				}
+
+				if !moved {
+					swapAttempt.Failure = SWAP_FAILURE_OTHER
+				}
+
+			}
+
+		} else {
+			swapAttempt.TxId = transactionInformation.TxId
+		}
+
+		if callback != nil {
+			callback.OnAttempt(swapAttempt)
+		}
+
+		if err != nil {
+			if swapAttempt.Failure != SWAP_FAILURE_SLIPPAGE {
+				return
+			}
+			quote = requoted
+			continue
+		}
+
+		result.Quote = quote
+		result.TransactionInformation = transactionInformation
+
+		// the remaining steps, i.e. the integrator fee, only make sense once the swap is confirmed
+		for i := 1; i < plan.Len(); i++ {
+			_, err = s.Client.Submit(plan.GetStep(i).GetTransactionGroup(), true)
+			if err != nil {
+				return
+			}
+		}
+
+		return
+
+	}
+
+	err = fmt.Errorf("swap failed after %d attempts: %s", maxRetries+1, err)
+
+	return
+
+}
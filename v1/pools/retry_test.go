@@ -0,0 +1,260 @@
+package pools
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+type fakeSigner struct {
+	account crypto.Account
+	signed  int
+}
+
+func (s *fakeSigner) SignTransactionGroup(transactionGroup *utils.TransactionGroup) error {
+
+	s.signed++
+	return transactionGroup.SignWithPrivateKey(s.account.Address.String(), string(s.account.PrivateKey))
+
+}
+
+type attemptRecorder struct {
+	attempts []*SwapAttempt
+}
+
+func (s *attemptRecorder) OnAttempt(attempt *SwapAttempt) {
+	s.attempts = append(s.attempts, attempt)
+}
+
+// mockRetryPool serves the pool account from the indexer, with asset2Reserves for the first `times` lookups
+// and movedAsset2Reserves afterwards.
+func mockRetryPool(t *testing.T, indexerURL string, times int, asset2Reserves, movedAsset2Reserves uint64) {
+
+	poolAddress, err := contracts.PoolAddress(1, 2, 0)
+	assert.Nil(t, err)
+
+	for i, reserves := range []uint64{asset2Reserves, movedAsset2Reserves} {
+
+		poolState := &state.PoolState{Asset1Id: 2, Asset2Id: 0, Asset1Reserves: 1000000, Asset2Reserves: reserves, IssuedLiquidity: 1000000}
+
+		mock := gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", poolAddress))
+		if i == 0 {
+			mock.Times(times)
+		} else {
+			mock.Persist()
+		}
+
+		mock.Reply(200).JSON(map[string]interface{}{
+			"current-round": 100,
+			"account": models.Account{
+				Address:        poolAddress,
+				Amount:         reserves + 1000000,
+				Round:          100,
+				AppsLocalState: []models.ApplicationLocalState{{Id: 1, KeyValue: poolState.Encode()}},
+				CreatedAssets:  []models.Asset{{Index: 3, Params: models.AssetParams{Name: "TinymanPool1.1 TEST-ALGO"}}},
+			},
+		})
+
+	}
+
+}
+
+func TestSwapWithRetry(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	account := crypto.GenerateAccount()
+	user := account.Address.String()
+
+	// the pool is read by NewPool, the quote and the re-quote of the stale quote before the reserves move
+	mockRetryPool(t, indexerURL, 3, 2000000, 2200000)
+
+	gock.New(algodURL).Post("/v2/transactions").
+		Reply(400).JSON(map[string]string{"message": "TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=1211. Details: app=1, pc=1211, opcodes="})
+
+	gock.New(algodURL).Post("/v2/transactions").
+		Reply(200).JSON(map[string]string{"txId": "TXID"})
+
+	gock.New(algodURL).Get("/v2/status").
+		Reply(200).JSON(map[string]interface{}{"last-round": 100})
+
+	gock.New(algodURL).Get("/v2/transactions/pending/TXID").
+		Reply(200).Body(bytes.NewReader(msgpack.Encode(models.PendingTransactionInfoResponse{ConfirmedRound: 101})))
+
+	tinymanClient, err := client.NewTinymanClient(algodURL, indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(tinymanClient, asset1, asset2, nil, true, 1)
+	assert.Nil(t, err)
+
+	quote, err := pool.FetchFixedInputSwapQuote(asset2.Call("10000"), 0.01)
+	assert.Nil(t, err)
+
+	// the quote is older than the max quote age at the first valid round, it is re-quoted rather than failed
+	quote.Round = 10
+
+	genesisHash := make([]byte, 32)
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: genesisHash, FirstRoundValid: 100, LastRoundValid: 1000}}
+
+	signer := &fakeSigner{account: account}
+	recorder := &attemptRecorder{}
+
+	result, err := pool.SwapWithRetry(quote, 0, 1, user, signer, recorder, options)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, result.AttemptsLen())
+	assert.Equal(t, recorder.attempts, result.Attempts)
+	assert.Equal(t, 2, signer.signed)
+
+	assert.Equal(t, 100, result.GetAttempt(0).Quote.Round)
+	assert.Equal(t, SWAP_FAILURE_SLIPPAGE, result.GetAttempt(0).Failure)
+	assert.Equal(t, "", result.GetAttempt(1).Failure)
+	assert.Equal(t, "TXID", result.GetAttempt(1).TxId)
+
+	// the second attempt is quoted at the moved reserves
+	assert.Equal(t, result.GetAttempt(1).Quote, result.Quote)
+	moved, err := quoteMoved(result.GetAttempt(0).Quote, result.Quote)
+	assert.Nil(t, err)
+	assert.True(t, moved)
+
+	assert.Equal(t, "TXID", result.TransactionInformation.TxId)
+
+}
+
+func TestSwapWithRetryOtherAssert(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	account := crypto.GenerateAccount()
+	user := account.Address.String()
+
+	// the reserves do not move, the rejected swap is still within its slippage
+	mockRetryPool(t, indexerURL, 1, 2000000, 2000000)
+
+	gock.New(algodURL).Post("/v2/transactions").
+		Reply(400).JSON(map[string]string{"message": "TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=80. Details: app=1, pc=80, opcodes="})
+
+	tinymanClient, err := client.NewTinymanClient(algodURL, indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(tinymanClient, asset1, asset2, nil, true, 1)
+	assert.Nil(t, err)
+
+	quote, err := pool.FetchFixedInputSwapQuote(asset2.Call("10000"), 0.01)
+	assert.Nil(t, err)
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 100, LastRoundValid: 1000}}
+
+	result, err := pool.SwapWithRetry(quote, 0, 3, user, &fakeSigner{account: account}, nil, options)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, 1, result.AttemptsLen())
+	assert.Equal(t, SWAP_FAILURE_OTHER, result.GetAttempt(0).Failure)
+	assert.Nil(t, result.Quote)
+
+	// a quote whose price with slippage is below the worst price is not submitted
+	_, err = pool.SwapWithRetry(quote, 1, 3, user, &fakeSigner{account: account}, nil, options)
+	assert.NotNil(t, err)
+
+}
+
+func TestSwapWithRetryIntegratorFee(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	account := crypto.GenerateAccount()
+	user := account.Address.String()
+	integrator := crypto.GenerateAccount().Address.String()
+
+	// the pool is read by NewPool, the quote and the re-quote of the stale quote before the reserves move
+	mockRetryPool(t, indexerURL, 3, 2000000, 2200000)
+
+	gock.New(algodURL).Post("/v2/transactions").
+		Reply(400).JSON(map[string]string{"message": "TransactionPool.Remember: transaction ABC: logic eval error: assert failed pc=1211. Details: app=1, pc=1211, opcodes="})
+
+	// the swap and then the integrator fee
+	gock.New(algodURL).Post("/v2/transactions").Times(2).
+		Reply(200).JSON(map[string]string{"txId": "TXID"})
+
+	gock.New(algodURL).Get("/v2/status").Times(2).
+		Reply(200).JSON(map[string]interface{}{"last-round": 100})
+
+	gock.New(algodURL).Get("/v2/transactions/pending/TXID").Times(2).
+		Reply(200).Body(bytes.NewReader(msgpack.Encode(models.PendingTransactionInfoResponse{ConfirmedRound: 101})))
+
+	tinymanClient, err := client.NewTinymanClient(algodURL, indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	err = tinymanClient.SetIntegratorFee(integrator, 100, types.INTEGRATOR_FEE_INPUT)
+	assert.Nil(t, err)
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(tinymanClient, asset1, asset2, nil, true, 1)
+	assert.Nil(t, err)
+
+	quote, err := pool.FetchFixedInputSwapQuote(asset2.Call("10000"), 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, "10000", quote.RequestedAmount.Amount)
+	assert.Equal(t, "9900", quote.AmountIn.Amount)
+	assert.Equal(t, "100", quote.IntegratorFee.Amount)
+
+	quote.Round = 10
+
+	options := &types.TxnOptions{SuggestedParams: &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 100, LastRoundValid: 1000}}
+
+	result, err := pool.SwapWithRetry(quote, 0, 1, user, &fakeSigner{account: account}, nil, options)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, result.AttemptsLen())
+
+	// the fee is taken once from the requested amount, however many times the swap is re-quoted
+	for i := 0; i < result.AttemptsLen(); i++ {
+		attemptQuote := result.GetAttempt(i).Quote
+		assert.Equal(t, "10000", attemptQuote.RequestedAmount.Amount)
+		assert.Equal(t, "9900", attemptQuote.AmountIn.Amount)
+		assert.Equal(t, "100", attemptQuote.IntegratorFee.Amount)
+	}
+
+	// an output side fee is added once to the requested amount
+	err = tinymanClient.SetIntegratorFee(integrator, 100, types.INTEGRATOR_FEE_OUTPUT)
+	assert.Nil(t, err)
+
+	quote, err = pool.FetchFixedOutputSwapQuote(asset1.Call("10000"), 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, "10100", quote.AmountOut.Amount)
+
+	requoted, err := pool.requote(quote)
+	assert.Nil(t, err)
+	assert.Equal(t, "10000", requoted.RequestedAmount.Amount)
+	assert.Equal(t, "10100", requoted.AmountOut.Amount)
+	assert.Equal(t, "100", requoted.IntegratorFee.Amount)
+
+}
@@ -0,0 +1,90 @@
+package pools
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/constants"
+)
+
+// Statuses of a pool.
+const (
+	// POOL_STATUS_UNKNOWN is the status of a pool that has not been fetched yet.
+	POOL_STATUS_UNKNOWN = "unknown"
+	// POOL_STATUS_NOT_CREATED is the status of a pool whose account is not opted into the validator app.
+	POOL_STATUS_NOT_CREATED = "not-created"
+	// POOL_STATUS_EMPTY is the status of a bootstrapped pool that has never been minted into.
+	POOL_STATUS_EMPTY = "empty"
+	// POOL_STATUS_ACTIVE is the status of a pool with liquidity.
+	POOL_STATUS_ACTIVE = "active"
+	// POOL_STATUS_DRAINED is the status of a pool whose liquidity has all been burnt, but the locked liquidity of the first mint.
+	POOL_STATUS_DRAINED = "drained"
+)
+
+// poolStatus returns the status of the pool of info, info is nil when the pool account has no local state.
+func poolStatus(info *PoolInfo) string {
+
+	if info == nil || info.LiquidityAssetId == 0 {
+		return POOL_STATUS_NOT_CREATED
+	}
+
+	issuedLiquidity := utils.NewBigIntString(info.IssuedLiquidity)
+
+	if issuedLiquidity.Sign() == 0 {
+		return POOL_STATUS_EMPTY
+	}
+
+	if issuedLiquidity.IsInt64() && issuedLiquidity.Int64() <= constants.LOCKED_LIQUIDITY {
+		return POOL_STATUS_DRAINED
+	}
+
+	return POOL_STATUS_ACTIVE
+
+}
+
+// PoolStatusError is the error of the quote and prepare methods of a pool whose status does not allow the operation.
+type PoolStatusError struct {
+	Status    string `json:"status"`
+	Operation string `json:"operation"`
+	Reason    string `json:"reason"`
+}
+
+func (s *PoolStatusError) Error() string {
+	return fmt.Sprintf("cannot %s: %s", s.Operation, s.Reason)
+}
+
+// AsPoolStatusError returns err as a PoolStatusError, nil if it is not one.
+func AsPoolStatusError(err error) *PoolStatusError {
+
+	var statusErr *PoolStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr
+	}
+
+	return nil
+
+}
+
+var poolStatusReasons = map[string]string{
+	POOL_STATUS_NOT_CREATED: "pool has not been bootstrapped yet",
+	POOL_STATUS_EMPTY:       "pool has no liquidity",
+	POOL_STATUS_DRAINED:     "pool has no liquidity but its locked liquidity",
+}
+
+// checkStatus returns a PoolStatusError when the status of the pool is one of statuses.
+// An unknown status is never rejected, the validator app rejects the operation instead.
+func (s *Pool) checkStatus(operation string, statuses ...string) (err error) {
+
+	for _, status := range statuses {
+
+		if s.Status == status {
+			err = &PoolStatusError{Status: s.Status, Operation: operation, Reason: poolStatusReasons[s.Status]}
+			return
+		}
+
+	}
+
+	return
+
+}
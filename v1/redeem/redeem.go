@@ -10,7 +10,7 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareRedeemTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID, assetID int, assetAmount, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareRedeemTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID, assetID int, assetAmount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
@@ -25,15 +25,9 @@ func PrepareRedeemTransactions(validatorAppId, asset1ID, asset2ID, liquidityAsse
 		return
 	}
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
@@ -76,6 +70,11 @@ func PrepareRedeemTransactions(validatorAppId, asset1ID, asset2ID, liquidityAsse
 
 	txns := []algoTypes.Transaction{paymentTxn, applicationNoOpTxn, assetTransferTxn}
 
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(txns)
 
 	if err != nil {
@@ -0,0 +1,285 @@
+package state
+
+import (
+	b64 "encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// Keys of the local state of a pool account in the validator app.
+const (
+	ASSET_1_ID_KEY              = "a1"
+	ASSET_2_ID_KEY              = "a2"
+	ASSET_1_RESERVES_KEY        = "s1"
+	ASSET_2_RESERVES_KEY        = "s2"
+	ISSUED_LIQUIDITY_KEY        = "ilt"
+	UNCLAIMED_PROTOCOL_FEES_KEY = "p"
+)
+
+const (
+	outstandingKeyPrefix = 'o'
+	excessKeySeparator   = 'e'
+)
+
+// teal value types of models.TealValue
+const (
+	tealBytesType = 1
+	tealUintType  = 2
+)
+
+// OutstandingKey is the key of the amount of assetID the pool owes to its users, "o" followed by the asset ID.
+func OutstandingKey(assetID int) []byte {
+
+	return append([]byte{outstandingKeyPrefix}, utils.IntToBytes(assetID)...)
+
+}
+
+// ExcessKey is the key of the excess amount of assetID a user can redeem from poolAddress,
+// the pool address followed by "e" and the asset ID.
+func ExcessKey(poolAddress string, assetID int) (key []byte, err error) {
+
+	address, err := algoTypes.DecodeAddress(poolAddress)
+	if err != nil {
+		return
+	}
+
+	key = append(key, address[:]...)
+	key = append(key, excessKeySeparator)
+	key = append(key, utils.IntToBytes(assetID)...)
+
+	return
+
+}
+
+// EncodeKey is key as it appears in the local state returned by algod and the indexer.
+func EncodeKey(key []byte) string {
+	return b64.StdEncoding.EncodeToString(key)
+}
+
+// decodeKeyValues returns the uint values of keyValues keyed by their decoded keys.
+func decodeKeyValues(keyValues []models.TealKeyValue) (values map[string]uint64, err error) {
+
+	values = make(map[string]uint64)
+
+	for _, kv := range keyValues {
+
+		var key []byte
+		key, err = b64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			err = fmt.Errorf("invalid local state key %s: %s", kv.Key, err)
+			return
+		}
+
+		if kv.Value.Type == tealBytesType {
+			continue
+		}
+
+		values[string(key)] = kv.Value.Uint
+
+	}
+
+	return
+
+}
+
+// actions of models.EvalDelta
+const (
+	deltaSetUintAction = 2
+	deltaDeleteAction  = 3
+)
+
+// DecodeDelta returns the uint values set by a local state delta of a confirmed transaction keyed by their decoded keys,
+// deleted keys have a zero value. Keys that the delta does not change are missing.
+// not compatible with go-mobile
+func DecodeDelta(delta []models.EvalDeltaKeyValue) (values map[string]uint64, err error) {
+
+	values = make(map[string]uint64)
+
+	for _, kv := range delta {
+
+		var key []byte
+		key, err = b64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			err = fmt.Errorf("invalid local state key %s: %s", kv.Key, err)
+			return
+		}
+
+		switch kv.Value.Action {
+		case deltaSetUintAction:
+			values[string(key)] = kv.Value.Uint
+		case deltaDeleteAction:
+			values[string(key)] = 0
+		}
+
+	}
+
+	return
+
+}
+
+func encodeKeyValue(key []byte, value uint64) models.TealKeyValue {
+
+	return models.TealKeyValue{
+		Key:   EncodeKey(key),
+		Value: models.TealValue{Type: tealUintType, Uint: value},
+	}
+
+}
+
+// PoolState is the local state of a pool account in the validator app.
+// not compatible with go-mobile
+type PoolState struct {
+	Asset1Id              int
+	Asset2Id              int
+	Asset1Reserves        uint64
+	Asset2Reserves        uint64
+	IssuedLiquidity       uint64
+	UnclaimedProtocolFees uint64
+	// OutstandingAmounts are the amounts the pool owes to its users, keyed by asset ID.
+	OutstandingAmounts map[int]uint64
+}
+
+// DecodePoolState decodes the local state of a pool account.
+// not compatible with go-mobile
+func DecodePoolState(keyValues []models.TealKeyValue) (poolState *PoolState, err error) {
+
+	values, err := decodeKeyValues(keyValues)
+	if err != nil {
+		return
+	}
+
+	poolState = &PoolState{
+		Asset1Id:              int(values[ASSET_1_ID_KEY]),
+		Asset2Id:              int(values[ASSET_2_ID_KEY]),
+		Asset1Reserves:        values[ASSET_1_RESERVES_KEY],
+		Asset2Reserves:        values[ASSET_2_RESERVES_KEY],
+		IssuedLiquidity:       values[ISSUED_LIQUIDITY_KEY],
+		UnclaimedProtocolFees: values[UNCLAIMED_PROTOCOL_FEES_KEY],
+		OutstandingAmounts:    make(map[int]uint64),
+	}
+
+	for key, value := range values {
+
+		if len(key) == 9 && key[0] == outstandingKeyPrefix {
+			poolState.OutstandingAmounts[int(binary.BigEndian.Uint64([]byte(key[1:])))] = value
+		}
+
+	}
+
+	return
+
+}
+
+// OutstandingAmount returns the amount of assetID the pool owes to its users.
+func (s *PoolState) OutstandingAmount(assetID int) uint64 {
+	return s.OutstandingAmounts[assetID]
+}
+
+// Encode is the reverse of DecodePoolState, ordered by key.
+// not compatible with go-mobile
+func (s *PoolState) Encode() (keyValues []models.TealKeyValue) {
+
+	keyValues = []models.TealKeyValue{
+		encodeKeyValue([]byte(ASSET_1_ID_KEY), uint64(s.Asset1Id)),
+		encodeKeyValue([]byte(ASSET_2_ID_KEY), uint64(s.Asset2Id)),
+		encodeKeyValue([]byte(ASSET_1_RESERVES_KEY), s.Asset1Reserves),
+		encodeKeyValue([]byte(ASSET_2_RESERVES_KEY), s.Asset2Reserves),
+		encodeKeyValue([]byte(ISSUED_LIQUIDITY_KEY), s.IssuedLiquidity),
+		encodeKeyValue([]byte(UNCLAIMED_PROTOCOL_FEES_KEY), s.UnclaimedProtocolFees),
+	}
+
+	for assetID, amount := range s.OutstandingAmounts {
+		keyValues = append(keyValues, encodeKeyValue(OutstandingKey(assetID), amount))
+	}
+
+	sortKeyValues(keyValues)
+
+	return
+
+}
+
+// UserState is the local state of a user account in the validator app.
+// not compatible with go-mobile
+type UserState struct {
+	// ExcessAmounts are the amounts the user can redeem, keyed by pool address and asset ID.
+	ExcessAmounts map[string]map[int]uint64
+}
+
+// DecodeUserState decodes the local state of a user account.
+// not compatible with go-mobile
+func DecodeUserState(keyValues []models.TealKeyValue) (userState *UserState, err error) {
+
+	values, err := decodeKeyValues(keyValues)
+	if err != nil {
+		return
+	}
+
+	userState = &UserState{ExcessAmounts: make(map[string]map[int]uint64)}
+
+	for key, value := range values {
+
+		b := []byte(key)
+		if len(b) != 41 || b[32] != excessKeySeparator {
+			continue
+		}
+
+		var address algoTypes.Address
+		copy(address[:], b[:32])
+		poolAddress := address.String()
+
+		if userState.ExcessAmounts[poolAddress] == nil {
+			userState.ExcessAmounts[poolAddress] = make(map[int]uint64)
+		}
+
+		userState.ExcessAmounts[poolAddress][int(binary.BigEndian.Uint64(b[33:]))] = value
+
+	}
+
+	return
+
+}
+
+// ExcessAmount returns the excess amount of assetID the user can redeem from poolAddress.
+func (s *UserState) ExcessAmount(poolAddress string, assetID int) uint64 {
+	return s.ExcessAmounts[poolAddress][assetID]
+}
+
+// Encode is the reverse of DecodeUserState, ordered by key.
+// not compatible with go-mobile
+func (s *UserState) Encode() (keyValues []models.TealKeyValue, err error) {
+
+	for poolAddress, amounts := range s.ExcessAmounts {
+
+		for assetID, amount := range amounts {
+
+			var key []byte
+			key, err = ExcessKey(poolAddress, assetID)
+			if err != nil {
+				return
+			}
+
+			keyValues = append(keyValues, encodeKeyValue(key, amount))
+
+		}
+
+	}
+
+	sortKeyValues(keyValues)
+
+	return
+
+}
+
+func sortKeyValues(keyValues []models.TealKeyValue) {
+
+	sort.Slice(keyValues, func(i, j int) bool {
+		return keyValues[i].Key < keyValues[j].Key
+	})
+
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	b64 "encoding/base64"
+	"testing"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolStateRoundTrip(t *testing.T) {
+
+	poolState := &PoolState{
+		Asset1Id:              31566704,
+		Asset2Id:              0,
+		Asset1Reserves:        1000000,
+		Asset2Reserves:        2000000,
+		IssuedLiquidity:       1414213,
+		UnclaimedProtocolFees: 25,
+		OutstandingAmounts: map[int]uint64{
+			31566704:  10,
+			0:         20,
+			552647097: 30,
+		},
+	}
+
+	keyValues := poolState.Encode()
+	assert.Equal(t, 9, len(keyValues))
+
+	decoded, err := DecodePoolState(keyValues)
+	assert.Nil(t, err)
+	assert.Equal(t, poolState, decoded)
+
+	assert.Equal(t, uint64(30), decoded.OutstandingAmount(552647097))
+	assert.Equal(t, uint64(0), decoded.OutstandingAmount(1))
+
+}
+
+func TestUserStateRoundTrip(t *testing.T) {
+
+	pool1 := crypto.GenerateAccount().Address.String()
+	pool2 := crypto.GenerateAccount().Address.String()
+
+	userState := &UserState{
+		ExcessAmounts: map[string]map[int]uint64{
+			pool1: {0: 1, 31566704: 2},
+			pool2: {552647097: 3},
+		},
+	}
+
+	keyValues, err := userState.Encode()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(keyValues))
+
+	// keys of other kinds are not excess amounts
+	keyValues = append(keyValues, models.TealKeyValue{Key: EncodeKey([]byte(ASSET_1_ID_KEY)), Value: models.TealValue{Type: 2, Uint: 5}})
+
+	decoded, err := DecodeUserState(keyValues)
+	assert.Nil(t, err)
+	assert.Equal(t, userState, decoded)
+
+	assert.Equal(t, uint64(2), decoded.ExcessAmount(pool1, 31566704))
+
+}
+
+func TestKeys(t *testing.T) {
+
+	assert.Equal(t, []byte{'o', 0, 0, 0, 0, 1, 225, 171, 112}, OutstandingKey(31566704))
+
+	poolAddress := crypto.GenerateAccount().Address
+
+	key, err := ExcessKey(poolAddress.String(), 31566704)
+	assert.Nil(t, err)
+	assert.Equal(t, append(append(poolAddress[:], 'e'), 0, 0, 0, 0, 1, 225, 171, 112), key)
+
+	_, err = ExcessKey("invalid", 1)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, b64.StdEncoding.EncodeToString([]byte("ilt")), EncodeKey([]byte(ISSUED_LIQUIDITY_KEY)))
+
+	_, err = DecodePoolState([]models.TealKeyValue{{Key: "!"}})
+	assert.NotNil(t, err)
+
+}
+
+func TestDecodeDelta(t *testing.T) {
+
+	delta := []models.EvalDeltaKeyValue{
+		{Key: EncodeKey([]byte(ASSET_1_RESERVES_KEY)), Value: models.EvalDelta{Action: 2, Uint: 1000}},
+		{Key: EncodeKey([]byte(ISSUED_LIQUIDITY_KEY)), Value: models.EvalDelta{Action: 3}},
+		{Key: EncodeKey([]byte("b")), Value: models.EvalDelta{Action: 1, Bytes: "AA=="}},
+	}
+
+	values, err := DecodeDelta(delta)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]uint64{ASSET_1_RESERVES_KEY: 1000, ISSUED_LIQUIDITY_KEY: 0}, values)
+
+	_, err = DecodeDelta([]models.EvalDeltaKeyValue{{Key: "!"}})
+	assert.NotNil(t, err)
+
+}
@@ -10,7 +10,7 @@ import (
 	algoTypes "github.com/algorand/go-algorand-sdk/types"
 )
 
-func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID, assetInID int, assetInAmount, assetOutAmount, swapType, senderAddress string, suggestedParams *types.SuggestedParams) (txnGroup *utils.TransactionGroup, err error) {
+func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetID, assetInID int, assetInAmount, assetOutAmount, swapType, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
 
 	sender, err := algoTypes.DecodeAddress(senderAddress)
 	if err != nil {
@@ -26,15 +26,9 @@ func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 	assetInAmountBig := utils.NewBigIntString(assetInAmount)
 	assetOutAmountBig := utils.NewBigIntString(assetOutAmount)
 
-	algoSuggestedParams := algoTypes.SuggestedParams{
-		Fee:              algoTypes.MicroAlgos(suggestedParams.Fee),
-		GenesisID:        suggestedParams.GenesisID,
-		GenesisHash:      suggestedParams.GenesisHash,
-		FirstRoundValid:  algoTypes.Round(suggestedParams.FirstRoundValid),
-		LastRoundValid:   algoTypes.Round(suggestedParams.LastRoundValid),
-		ConsensusVersion: suggestedParams.ConsensusVersion,
-		FlatFee:          suggestedParams.FlatFee,
-		MinFee:           uint64(suggestedParams.MinFee),
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
 	}
 
 	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
@@ -98,6 +92,11 @@ func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 
 	txns := []algoTypes.Transaction{paymentTxn, applicationNoOpTxn, assetTransferInTxn, assetTransferOutTxn}
 
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
 	txnGroup, err = utils.NewTransactionGroup(txns)
 
 	if err != nil {
@@ -112,3 +111,54 @@ func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, liquidityAssetI
 	return
 
 }
+
+// PrepareIntegratorFeeTransactions transfers the integrator fee from the swapper to the integrator,
+// in a group submitted after the swap. It is not atomic with the swap.
+func PrepareIntegratorFeeTransactions(assetID int, amount, integratorAddress, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	return prepareTransferTransactions(assetID, amount, integratorAddress, senderAddress, []byte(types.INTEGRATOR_FEE_NOTE), suggestedParams, options)
+
+}
+
+// PreparePaymentTransactions transfers the output of a swap from the swapper to the recipient of a payment,
+// in a group submitted after the swap. It is not atomic with the swap.
+func PreparePaymentTransactions(assetID int, amount, recipientAddress, senderAddress string, note []byte, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	return prepareTransferTransactions(assetID, amount, recipientAddress, senderAddress, note, suggestedParams, options)
+
+}
+
+func prepareTransferTransactions(assetID int, amount, receiverAddress, senderAddress string, note []byte, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	receiver, err := algoTypes.DecodeAddress(receiverAddress)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	txn, err := utils.MakeTransferTxn(sender.String(), receiver.String(), assetID, amount, note, algoSuggestedParams)
+	if err != nil {
+		return
+	}
+
+	txns := []algoTypes.Transaction{txn}
+
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+
+	return
+
+}
@@ -0,0 +1,165 @@
+package verify
+
+import (
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// MAX_TINYMAN_FEE_PAYMENT is the largest Tinyman fee payment, used by burn groups.
+const MAX_TINYMAN_FEE_PAYMENT = 3000
+
+// defaultMaxSponsorFees is the cap of VerifySponsorTransactions when none is given: the min fee of every transaction
+// of the group plus the largest Tinyman fee payment, which pays the fees of the pool transactions.
+func defaultMaxSponsorFees(groupSize int) int {
+	return groupSize*types.GetConsensusParams("").MinTxnFee + MAX_TINYMAN_FEE_PAYMENT
+}
+
+// VerifySponsorTransactions checks that the fee payer only pays fees in txnGroup before it signs.
+// Every transaction of the fee payer must be either a Tinyman fee payment to the pool of the validator app call
+// of the group, or a zero amount payment to itself. maxFees caps the total of fees and fee payments, when it is
+// not positive the cap is the min fee of every transaction of the group plus MAX_TINYMAN_FEE_PAYMENT.
+func VerifySponsorTransactions(txnGroup *utils.TransactionGroup, feePayerAddress string, maxFees int) (report *Report, err error) {
+
+	feePayer, err := algoTypes.DecodeAddress(feePayerAddress)
+	if err != nil {
+		return
+	}
+
+	transactions := txnGroup.GetTransactions()
+
+	s := &verifier{
+		report: &Report{
+			Operation: "sponsor",
+		},
+		transactions: transactions,
+		signed:       txnGroup.GetSignedTransactions(),
+	}
+
+	if len(transactions) == 0 {
+		s.fail(-1, "group is empty")
+		return s.finish(), nil
+	}
+
+	group := transactions[0].Group
+	if group == (algoTypes.Digest{}) && len(transactions) > 1 {
+		s.fail(0, "transaction has no group ID")
+	}
+
+	if maxFees <= 0 {
+		maxFees = defaultMaxSponsorFees(len(transactions))
+	}
+
+	pool, poolFound := s.validatorAppCallPool()
+
+	var total uint64
+	sponsored := false
+
+	for i, txn := range transactions {
+
+		if txn.Group != group {
+			s.fail(i, "transaction group ID does not match the rest of the group")
+		}
+
+		if txn.Sender != feePayer {
+			continue
+		}
+
+		sponsored = true
+		total += uint64(txn.Fee)
+
+		if txn.Type != algoTypes.PaymentTx {
+			s.fail(i, "fee payer can only send payments, got %s", txn.Type)
+			continue
+		}
+
+		if !txn.RekeyTo.IsZero() {
+			s.fail(i, "transaction rekeys the fee payer to %s", txn.RekeyTo.String())
+		}
+
+		if !txn.CloseRemainderTo.IsZero() {
+			s.fail(i, "transaction closes the fee payer to %s", txn.CloseRemainderTo.String())
+		}
+
+		if txn.Receiver == feePayer {
+
+			if txn.Amount != 0 {
+				s.fail(i, "fee payer payment to itself must have a zero amount")
+			}
+
+			continue
+
+		}
+
+		if string(txn.Note) != "fee" || !poolFound || txn.Receiver != pool {
+			s.fail(i, "fee payer can only pay the Tinyman fee to the pool of the validator app call of the group")
+			continue
+		}
+
+		if uint64(txn.Amount) > MAX_TINYMAN_FEE_PAYMENT {
+			s.fail(i, "fee payment amount is %d, max is %d", txn.Amount, MAX_TINYMAN_FEE_PAYMENT)
+		}
+
+		total += uint64(txn.Amount)
+
+	}
+
+	if !sponsored {
+		s.fail(-1, "group has no transaction from the fee payer")
+	}
+
+	if total > uint64(maxFees) {
+		s.fail(-1, "fee payer pays %d, max is %d", total, maxFees)
+	}
+
+	return s.finish(), nil
+
+}
+
+// validatorAppCallPool returns the pool of the validator app call of the group: the sender of an app call signed
+// with the pool logicsig of the called app. found is false when the group has no such app call.
+func (s *verifier) validatorAppCallPool() (pool algoTypes.Address, found bool) {
+
+	for i, txn := range s.transactions {
+
+		if txn.Type != algoTypes.ApplicationCallTx {
+			continue
+		}
+
+		logic := s.logicsig(i)
+		if len(logic) == 0 || crypto.AddressFromProgram(logic) != txn.Sender {
+			continue
+		}
+
+		variables, err := contracts.ParsePoolLogicsig(logic)
+		if err != nil || variables.ValidatorAppId != int(txn.ApplicationID) {
+			continue
+		}
+
+		return txn.Sender, true
+
+	}
+
+	return
+
+}
+
+// logicsig returns the logicsig program transaction index is signed with, nil when it is not logicsig signed.
+func (s *verifier) logicsig(index int) []byte {
+
+	if len(s.signed[index]) == 0 {
+		return nil
+	}
+
+	var stx algoTypes.SignedTxn
+	if msgpack.Decode(s.signed[index], &stx) != nil {
+		return nil
+	}
+
+	return stx.Lsig.Logic
+
+}
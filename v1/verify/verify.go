@@ -0,0 +1,478 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/pools"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/encoding/msgpack"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type Issue struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+type Report struct {
+	Operation   string   `json:"operation"`
+	PoolAddress string   `json:"pool-address"`
+	Passed      bool     `json:"passed"`
+	Issues      []*Issue `json:"issues"`
+}
+
+func (s *Report) IssuesLen() int {
+	return len(s.Issues)
+}
+
+func (s *Report) GetIssue(index int) *Issue {
+	return s.Issues[index]
+}
+
+func (s *Report) JSON() (reportStr string, err error) {
+
+	reportBytes, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	reportStr = string(reportBytes)
+	return
+
+}
+
+type verifier struct {
+	report         *Report
+	transactions   []algoTypes.Transaction
+	signed         [][]byte
+	user           algoTypes.Address
+	pool           algoTypes.Address
+	poolLogic      []byte
+	validatorAppId int
+	foreignAssets  []uint64
+	maxFee         uint64
+}
+
+// newVerifier returns a verifier of a group of userAddress on pool. maxFee is the expected flat fee of each
+// transaction of the user, 0 is the min fee of the network.
+func newVerifier(operation string, txnGroup *utils.TransactionGroup, pool *pools.Pool, userAddress string, maxFee int) (s *verifier, err error) {
+
+	if pool.LiquidityAsset == nil {
+		err = fmt.Errorf("pool has not been bootstrapped yet")
+		return
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	poolLogicsig, err := contracts.GetPoolLogicsig(pool.ValidatorAppId, pool.Asset1.Id, pool.Asset2.Id)
+	if err != nil {
+		return
+	}
+
+	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
+
+	var foreignAssets []uint64
+	if pool.Asset2.Id == 0 {
+		foreignAssets = []uint64{uint64(pool.Asset1.Id), uint64(pool.LiquidityAsset.Id)}
+	} else {
+		foreignAssets = []uint64{uint64(pool.Asset1.Id), uint64(pool.Asset2.Id), uint64(pool.LiquidityAsset.Id)}
+	}
+
+	s = &verifier{
+		report: &Report{
+			Operation:   operation,
+			PoolAddress: poolAddress.String(),
+		},
+		transactions:   txnGroup.GetTransactions(),
+		signed:         txnGroup.GetSignedTransactions(),
+		user:           user,
+		pool:           poolAddress,
+		poolLogic:      poolLogicsig.Logic,
+		validatorAppId: pool.ValidatorAppId,
+		foreignAssets:  foreignAssets,
+		maxFee:         uint64(maxFee),
+	}
+
+	if maxFee <= 0 {
+		s.maxFee = uint64(types.GetConsensusParams("").MinTxnFee)
+	}
+
+	return
+
+}
+
+func (s *verifier) fail(index int, format string, a ...interface{}) {
+	s.report.Issues = append(s.report.Issues, &Issue{Index: index, Message: fmt.Sprintf(format, a...)})
+}
+
+func (s *verifier) finish() *Report {
+	s.report.Passed = len(s.report.Issues) == 0
+	return s.report
+}
+
+// checkGroup verifies the group size, the group ID, the fees of the user transactions and the fields that must never
+// be set in a Tinyman operation group.
+func (s *verifier) checkGroup(expectedLen int) bool {
+
+	if len(s.transactions) != expectedLen {
+		s.fail(-1, "expected %d transactions, got %d", expectedLen, len(s.transactions))
+		return false
+	}
+
+	group := s.transactions[0].Group
+	if group == (algoTypes.Digest{}) {
+		s.fail(0, "transaction has no group ID")
+	}
+
+	for i, txn := range s.transactions {
+
+		if txn.Group != group {
+			s.fail(i, "transaction group ID does not match the rest of the group")
+		}
+
+		if !txn.RekeyTo.IsZero() {
+			s.fail(i, "transaction rekeys %s to %s", txn.Sender.String(), txn.RekeyTo.String())
+		}
+
+		if !txn.CloseRemainderTo.IsZero() {
+			s.fail(i, "transaction closes remaining ALGO to %s", txn.CloseRemainderTo.String())
+		}
+
+		if !txn.AssetCloseTo.IsZero() {
+			s.fail(i, "transaction closes remaining asset to %s", txn.AssetCloseTo.String())
+		}
+
+		if !txn.AssetSender.IsZero() {
+			s.fail(i, "transaction is a clawback from %s", txn.AssetSender.String())
+		}
+
+		if txn.Sender == s.user && uint64(txn.Fee) > s.maxFee {
+			s.fail(i, "transaction fee is %d, expected at most %d", txn.Fee, s.maxFee)
+		}
+
+		if txn.Sender == s.pool {
+			s.checkPoolSignature(i)
+		} else if txn.Sender != s.user && !(i == 0 && txn.Type == algoTypes.PaymentTx) {
+			s.fail(i, "unexpected sender %s", txn.Sender.String())
+		}
+
+	}
+
+	return true
+
+}
+
+// checkPoolSignature makes sure a pre-signed pool transaction is signed by the expected pool logicsig.
+func (s *verifier) checkPoolSignature(index int) {
+
+	if len(s.signed[index]) == 0 {
+		return
+	}
+
+	var stx algoTypes.SignedTxn
+	err := msgpack.Decode(s.signed[index], &stx)
+	if err != nil {
+		s.fail(index, "invalid signed transaction: %v", err)
+		return
+	}
+
+	if !bytes.Equal(stx.Lsig.Logic, s.poolLogic) {
+		s.fail(index, "transaction is not signed by the pool logicsig")
+	}
+
+	if crypto.TransactionIDString(stx.Txn) != crypto.TransactionIDString(s.transactions[index]) {
+		s.fail(index, "signed transaction does not match the group transaction")
+	}
+
+}
+
+func (s *verifier) checkFeePayment(index int, amount uint64) {
+
+	txn := s.transactions[index]
+
+	if txn.Type != algoTypes.PaymentTx {
+		s.fail(index, "expected fee payment transaction, got %s", txn.Type)
+		return
+	}
+
+	// the fee payment may come from a fee payer sponsoring the user
+	if txn.Sender == s.pool || txn.Receiver != s.pool {
+		s.fail(index, "fee payment must be sent to the pool")
+	}
+
+	if uint64(txn.Amount) != amount {
+		s.fail(index, "fee payment amount is %d, expected %d", txn.Amount, amount)
+	}
+
+	if string(txn.Note) != "fee" {
+		s.fail(index, "fee payment note is %q, expected %q", string(txn.Note), "fee")
+	}
+
+}
+
+func (s *verifier) checkAppCall(index int, args [][]byte, withUserAccount bool) {
+
+	txn := s.transactions[index]
+
+	if txn.Type != algoTypes.ApplicationCallTx {
+		s.fail(index, "expected application call transaction, got %s", txn.Type)
+		return
+	}
+
+	if txn.Sender != s.pool {
+		s.fail(index, "application call must be sent from the pool")
+	}
+
+	if int(txn.ApplicationID) != s.validatorAppId {
+		s.fail(index, "application ID is %d, expected validator %d", txn.ApplicationID, s.validatorAppId)
+	}
+
+	if txn.OnCompletion != algoTypes.NoOpOC {
+		s.fail(index, "unexpected on-completion %d", txn.OnCompletion)
+	}
+
+	if len(txn.ApplicationArgs) != len(args) {
+		s.fail(index, "expected %d application args, got %d", len(args), len(txn.ApplicationArgs))
+	} else {
+		for i := range args {
+			if !bytes.Equal(txn.ApplicationArgs[i], args[i]) {
+				s.fail(index, "application arg %d is %q, expected %q", i, string(txn.ApplicationArgs[i]), string(args[i]))
+			}
+		}
+	}
+
+	if withUserAccount {
+		if len(txn.Accounts) != 1 || txn.Accounts[0] != s.user {
+			s.fail(index, "application call accounts must contain only the user")
+		}
+	} else if len(txn.Accounts) != 0 {
+		s.fail(index, "unexpected application call accounts")
+	}
+
+	if len(txn.ForeignApps) != 0 {
+		s.fail(index, "unexpected foreign apps")
+	}
+
+	if len(txn.ForeignAssets) != len(s.foreignAssets) {
+		s.fail(index, "unexpected foreign assets")
+		return
+	}
+
+	for i := range s.foreignAssets {
+		if uint64(txn.ForeignAssets[i]) != s.foreignAssets[i] {
+			s.fail(index, "foreign asset %d is %d, expected %d", i, txn.ForeignAssets[i], s.foreignAssets[i])
+		}
+	}
+
+}
+
+// checkTransfer verifies a payment (assetID 0) or an asset transfer and returns its amount.
+func (s *verifier) checkTransfer(index, assetID int, sender, receiver algoTypes.Address) (amount *big.Int) {
+
+	txn := s.transactions[index]
+	amount = new(big.Int)
+
+	if assetID == 0 {
+
+		if txn.Type != algoTypes.PaymentTx {
+			s.fail(index, "expected ALGO payment, got %s", txn.Type)
+			return
+		}
+
+		if txn.Sender != sender || txn.Receiver != receiver {
+			s.fail(index, "ALGO payment must be sent from %s to %s", sender.String(), receiver.String())
+		}
+
+		return amount.SetUint64(uint64(txn.Amount))
+
+	}
+
+	if txn.Type != algoTypes.AssetTransferTx {
+		s.fail(index, "expected asset transfer, got %s", txn.Type)
+		return
+	}
+
+	if int(txn.XferAsset) != assetID {
+		s.fail(index, "transferred asset is %d, expected %d", txn.XferAsset, assetID)
+	}
+
+	if txn.Sender != sender || txn.AssetReceiver != receiver {
+		s.fail(index, "asset transfer must be sent from %s to %s", sender.String(), receiver.String())
+	}
+
+	return amount.SetUint64(txn.AssetAmount)
+
+}
+
+func (s *verifier) expectEqual(index int, actual *big.Int, expected string) {
+
+	if actual.Cmp(utils.NewBigIntString(expected)) != 0 {
+		s.fail(index, "amount is %s, expected %s", actual.String(), expected)
+	}
+
+}
+
+func (s *verifier) expectBetween(index int, actual *big.Int, min, max string) {
+
+	if actual.Cmp(utils.NewBigIntString(min)) < 0 || actual.Cmp(utils.NewBigIntString(max)) > 0 {
+		s.fail(index, "amount is %s, expected between %s and %s", actual.String(), min, max)
+	}
+
+}
+
+// VerifySwapTransactions checks that txnGroup is exactly the swap described by quote on the given pool.
+// maxFee is the flat fee the user expects to pay for each of its transactions, 0 is the min fee of the network.
+func VerifySwapTransactions(txnGroup *utils.TransactionGroup, pool *pools.Pool, quote *pools.SwapQuote, userAddress string, maxFee int) (report *Report, err error) {
+
+	s, err := newVerifier("swap", txnGroup, pool, userAddress, maxFee)
+	if err != nil {
+		return
+	}
+
+	if !s.checkGroup(4) {
+		return s.finish(), nil
+	}
+
+	swapTypes := map[string]string{
+		"fixed-input":  "fi",
+		"fixed-output": "fo",
+	}
+
+	amountInWithSlippage, err := quote.AmountInWithSlippage()
+	if err != nil {
+		return
+	}
+
+	amountOutWithSlippage, err := quote.AmountOutWithSlippage()
+	if err != nil {
+		return
+	}
+
+	if *quote.AmountIn.Asset != *pool.Asset1 && *quote.AmountIn.Asset != *pool.Asset2 {
+		s.fail(-1, "quote input asset %d does not belong to the pool", quote.AmountIn.Asset.Id)
+	}
+
+	if *quote.AmountOut.Asset != *pool.Asset1 && *quote.AmountOut.Asset != *pool.Asset2 {
+		s.fail(-1, "quote output asset %d does not belong to the pool", quote.AmountOut.Asset.Id)
+	}
+
+	s.checkFeePayment(0, 2000)
+	s.checkAppCall(1, [][]byte{[]byte("swap"), []byte(swapTypes[quote.SwapType])}, true)
+
+	amountIn := s.checkTransfer(2, quote.AmountIn.Asset.Id, s.user, s.pool)
+	amountOut := s.checkTransfer(3, quote.AmountOut.Asset.Id, s.pool, s.user)
+
+	if quote.SwapType == "fixed-input" {
+		s.expectEqual(2, amountIn, quote.AmountIn.Amount)
+		s.expectBetween(3, amountOut, amountOutWithSlippage.Amount, quote.AmountOut.Amount)
+	} else {
+		s.expectBetween(2, amountIn, quote.AmountIn.Amount, amountInWithSlippage.Amount)
+		s.expectEqual(3, amountOut, quote.AmountOut.Amount)
+	}
+
+	return s.finish(), nil
+
+}
+
+// VerifyMintTransactions checks that txnGroup is exactly the mint described by quote on the given pool.
+// maxFee is the expected flat fee of each user transaction, as for VerifySwapTransactions.
+func VerifyMintTransactions(txnGroup *utils.TransactionGroup, pool *pools.Pool, quote *pools.MintQuote, userAddress string, maxFee int) (report *Report, err error) {
+
+	s, err := newVerifier("mint", txnGroup, pool, userAddress, maxFee)
+	if err != nil {
+		return
+	}
+
+	if !s.checkGroup(5) {
+		return s.finish(), nil
+	}
+
+	liquidityAssetAmountWithSlippage, err := quote.LiquidityAssetAmountWithSlippage()
+	if err != nil {
+		return
+	}
+
+	amountsIn := quote.GetAmountsIn()
+
+	s.checkFeePayment(0, 2000)
+	s.checkAppCall(1, [][]byte{[]byte("mint")}, true)
+
+	asset1Amount := s.checkTransfer(2, pool.Asset1.Id, s.user, s.pool)
+	asset2Amount := s.checkTransfer(3, pool.Asset2.Id, s.user, s.pool)
+	liquidityAssetAmount := s.checkTransfer(4, pool.LiquidityAsset.Id, s.pool, s.user)
+
+	s.expectEqual(2, asset1Amount, amountsIn[pool.Asset1.Id])
+	s.expectEqual(3, asset2Amount, amountsIn[pool.Asset2.Id])
+	s.expectBetween(4, liquidityAssetAmount, liquidityAssetAmountWithSlippage.Amount, quote.LiquidityAssetAmount.Amount)
+
+	return s.finish(), nil
+
+}
+
+// VerifyBurnTransactions checks that txnGroup is exactly the burn described by quote on the given pool.
+// maxFee is the expected flat fee of each user transaction, as for VerifySwapTransactions.
+func VerifyBurnTransactions(txnGroup *utils.TransactionGroup, pool *pools.Pool, quote *pools.BurnQuote, userAddress string, maxFee int) (report *Report, err error) {
+
+	s, err := newVerifier("burn", txnGroup, pool, userAddress, maxFee)
+	if err != nil {
+		return
+	}
+
+	if !s.checkGroup(5) {
+		return s.finish(), nil
+	}
+
+	amountsOutWithSlippage, err := quote.AmountsOutWithSlippage()
+	if err != nil {
+		return
+	}
+
+	amountsOut := quote.GetAmountsOut()
+
+	s.checkFeePayment(0, 3000)
+	s.checkAppCall(1, [][]byte{[]byte("burn")}, true)
+
+	asset1Amount := s.checkTransfer(2, pool.Asset1.Id, s.pool, s.user)
+	asset2Amount := s.checkTransfer(3, pool.Asset2.Id, s.pool, s.user)
+	liquidityAssetAmount := s.checkTransfer(4, pool.LiquidityAsset.Id, s.user, s.pool)
+
+	s.expectBetween(2, asset1Amount, amountsOutWithSlippage[pool.Asset1.Id], amountsOut[pool.Asset1.Id])
+	s.expectBetween(3, asset2Amount, amountsOutWithSlippage[pool.Asset2.Id], amountsOut[pool.Asset2.Id])
+	s.expectEqual(4, liquidityAssetAmount, quote.LiquidityAssetAmount.Amount)
+
+	return s.finish(), nil
+
+}
+
+// VerifyRedeemTransactions checks that txnGroup only redeems amountOut from the given pool.
+// maxFee is the expected flat fee of each user transaction, as for VerifySwapTransactions.
+func VerifyRedeemTransactions(txnGroup *utils.TransactionGroup, pool *pools.Pool, amountOut *types.AssetAmount, userAddress string, maxFee int) (report *Report, err error) {
+
+	s, err := newVerifier("redeem", txnGroup, pool, userAddress, maxFee)
+	if err != nil {
+		return
+	}
+
+	if !s.checkGroup(3) {
+		return s.finish(), nil
+	}
+
+	s.checkFeePayment(0, 2000)
+	s.checkAppCall(1, [][]byte{[]byte("redeem")}, true)
+
+	amount := s.checkTransfer(2, amountOut.Asset.Id, s.pool, s.user)
+	s.expectEqual(2, amount, amountOut.Amount)
+
+	return s.finish(), nil
+
+}
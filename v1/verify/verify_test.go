@@ -0,0 +1,265 @@
+package verify
+
+import (
+	b64 "encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/burn"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/mint"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/pools"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/redeem"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/state"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/swap"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestVerifySwapTransactions(t *testing.T) {
+
+	user := crypto.GenerateAccount()
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	info := &pools.PoolInfo{
+		LiquidityAssetId:   3,
+		LiquidityAssetName: "TinymanPool1.1 TEST-ALGO",
+		Asset1Reserves:     "1000000",
+		AlgoBalance:        "2000000",
+		IssuedLiquidity:    "1000000",
+	}
+
+	pool, err := pools.NewPool(nil, asset1, asset2, info, false, 1)
+	assert.Nil(t, err)
+
+	quote := &pools.SwapQuote{
+		SwapType:  "fixed-input",
+		AmountIn:  asset2.Call("10000"),
+		AmountOut: asset1.Call("1000"),
+		SwapFees:  asset2.Call("30"),
+		Slippage:  0.01,
+	}
+
+	genesisHash, _ := b64.StdEncoding.DecodeString("f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk=")
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisHash,
+	}
+
+	txnGroup, err := swap.PrepareSwapTransactions(1, asset1.Id, asset2.Id, 3, asset2.Id, "10000", "990", "fixed-input", user.Address.String(), suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err := VerifySwapTransactions(txnGroup, pool, quote, user.Address.String(), 0)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+	txnGroup, err = swap.PrepareSwapTransactions(1, asset1.Id, asset2.Id, 3, asset2.Id, "20000", "900", "fixed-input", user.Address.String(), suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err = VerifySwapTransactions(txnGroup, pool, quote, user.Address.String(), 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, 2, report.IssuesLen())
+
+	// the user transactions pay more than the expected fee
+	txnGroup, err = swap.PrepareSwapTransactions(1, asset1.Id, asset2.Id, 3, asset2.Id, "10000", "990", "fixed-input", user.Address.String(), suggestedParams, &types.TxnOptions{FlatFee: 5000})
+	assert.Nil(t, err)
+
+	report, err = VerifySwapTransactions(txnGroup, pool, quote, user.Address.String(), 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, 2, report.IssuesLen())
+
+	report, err = VerifySwapTransactions(txnGroup, pool, quote, user.Address.String(), 5000)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+}
+
+func TestVerifySponsorTransactions(t *testing.T) {
+
+	user := crypto.GenerateAccount()
+	sponsor := crypto.GenerateAccount()
+
+	genesisHash, _ := b64.StdEncoding.DecodeString("f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk=")
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisHash,
+	}
+
+	options := &types.TxnOptions{FeePayer: sponsor.Address.String()}
+
+	txnGroup, err := swap.PrepareSwapTransactions(1, 2, 0, 3, 2, "10000", "990", "fixed-input", user.Address.String(), suggestedParams, options)
+	assert.Nil(t, err)
+
+	transactions := txnGroup.GetTransactions()
+	assert.Equal(t, sponsor.Address, transactions[0].Sender)
+	assert.Equal(t, 2000, int(transactions[0].Fee))
+	assert.Equal(t, 0, int(transactions[2].Fee))
+
+	report, err := VerifySponsorTransactions(txnGroup, sponsor.Address.String(), 5000)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+	report, err = VerifySponsorTransactions(txnGroup, sponsor.Address.String(), 3000)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+
+	report, err = VerifySponsorTransactions(txnGroup, user.Address.String(), 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+
+	// without a cap the fee payer pays at most the min fee of every transaction and the largest Tinyman fee payment
+	report, err = VerifySponsorTransactions(txnGroup, sponsor.Address.String(), 0)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+	assert.Equal(t, 7000, defaultMaxSponsorFees(len(transactions)))
+
+	// a fee payment to an account that is not the pool of the app call
+	tamperedTransactions := append([]algoTypes.Transaction{}, transactions...)
+	tamperedTransactions[0].Receiver = crypto.GenerateAccount().Address
+	for i := range tamperedTransactions {
+		tamperedTransactions[i].Group = algoTypes.Digest{}
+	}
+
+	tamperedGroup, err := utils.NewTransactionGroup(tamperedTransactions)
+	assert.Nil(t, err)
+
+	poolLogicsig, err := contracts.GetPoolLogicsig(1, 2, 0)
+	assert.Nil(t, err)
+
+	err = tamperedGroup.SignWithLogicsig(poolLogicsig)
+	assert.Nil(t, err)
+
+	report, err = VerifySponsorTransactions(tamperedGroup, sponsor.Address.String(), 5000)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, 1, report.IssuesLen())
+	assert.Equal(t, 0, report.GetIssue(0).Index)
+
+}
+
+// mockPool returns a pool of asset 2 and ALGO with liquidity asset 3 that refreshes from a mocked indexer.
+func mockPool(t *testing.T, user string) *pools.Pool {
+
+	indexerURL := "https://indexer.mockserver.com"
+
+	poolAddress, err := contracts.PoolAddress(1, 2, 0)
+	assert.Nil(t, err)
+
+	poolState := &state.PoolState{Asset1Id: 2, Asset2Id: 0, Asset1Reserves: 1000000, Asset2Reserves: 2000000, IssuedLiquidity: 1000000}
+
+	gock.New(indexerURL).Get(fmt.Sprintf("/v2/accounts/%s", poolAddress)).Persist().
+		Reply(200).JSON(map[string]interface{}{
+		"current-round": 10,
+		"account": models.Account{
+			Address:        poolAddress,
+			Amount:         2000000,
+			AppsLocalState: []models.ApplicationLocalState{{Id: 1, KeyValue: poolState.Encode()}},
+			CreatedAssets:  []models.Asset{{Index: 3, Params: models.AssetParams{Name: "TinymanPool1.1 TEST-ALGO"}}},
+		},
+	})
+
+	tinymanClient, err := client.NewTinymanClient("https://algod.mockserver.com", indexerURL, 1, user)
+	assert.Nil(t, err)
+
+	asset1 := &types.Asset{Id: 2, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := pools.NewPool(tinymanClient, asset1, asset2, nil, true, 1)
+	assert.Nil(t, err)
+
+	return pool
+
+}
+
+func TestVerifyLiquidityTransactions(t *testing.T) {
+
+	defer gock.Off()
+
+	user := crypto.GenerateAccount().Address.String()
+	pool := mockPool(t, user)
+
+	genesisHash, _ := b64.StdEncoding.DecodeString("f4OxZX/x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk=")
+	suggestedParams := &types.SuggestedParams{
+		Fee:             1000,
+		FlatFee:         true,
+		FirstRoundValid: 1,
+		LastRoundValid:  100,
+		GenesisHash:     genesisHash,
+	}
+
+	mintQuote, err := pool.FetchMintQuote(pool.Asset1.Call("10000"), nil, 0.01)
+	assert.Nil(t, err)
+
+	amountsIn := mintQuote.GetAmountsIn()
+
+	txnGroup, err := mint.PrepareMintTransactions(1, 2, 0, 3, amountsIn[2], amountsIn[0], mintQuote.LiquidityAssetAmount.Amount, user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err := VerifyMintTransactions(txnGroup, pool, mintQuote, user, 0)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+	// the group takes more of asset 2 than quoted
+	txnGroup, err = mint.PrepareMintTransactions(1, 2, 0, 3, amountsIn[2], "50000", mintQuote.LiquidityAssetAmount.Amount, user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err = VerifyMintTransactions(txnGroup, pool, mintQuote, user, 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, 3, report.GetIssue(0).Index)
+
+	burnQuote, err := pool.FetchBurnQuote(pool.LiquidityAsset.Call("10000"), 0.01)
+	assert.Nil(t, err)
+
+	amountsOut := burnQuote.GetAmountsOut()
+
+	txnGroup, err = burn.PrepareBurnTransactions(1, 2, 0, 3, amountsOut[2], amountsOut[0], "10000", user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err = VerifyBurnTransactions(txnGroup, pool, burnQuote, user, 0)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+	// the group burns more liquidity than quoted
+	txnGroup, err = burn.PrepareBurnTransactions(1, 2, 0, 3, amountsOut[2], amountsOut[0], "20000", user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err = VerifyBurnTransactions(txnGroup, pool, burnQuote, user, 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+	assert.Equal(t, 4, report.GetIssue(0).Index)
+
+	txnGroup, err = redeem.PrepareRedeemTransactions(1, 2, 0, 3, 2, "500", user, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	report, err = VerifyRedeemTransactions(txnGroup, pool, pool.Asset1.Call("500"), user, 0)
+	assert.Nil(t, err)
+	assert.True(t, report.Passed)
+
+	report, err = VerifyRedeemTransactions(txnGroup, pool, pool.Asset1.Call("400"), user, 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+
+	// the redeem of another user
+	report, err = VerifyRedeemTransactions(txnGroup, pool, pool.Asset1.Call("500"), crypto.GenerateAccount().Address.String(), 0)
+	assert.Nil(t, err)
+	assert.False(t, report.Passed)
+
+}
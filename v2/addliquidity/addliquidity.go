@@ -0,0 +1,150 @@
+package addliquidity
+
+import (
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// PrepareInitialAddLiquidityTransactions adds the first liquidity of a bootstrapped pool.
+// The ratio of the amounts sets the initial price, LOCKED_POOL_TOKENS of the pool tokens stay in the pool.
+func PrepareInitialAddLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID int, asset1Amount, asset2Amount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	return prepareAddLiquidityTransactions(
+		validatorAppId,
+		asset1ID,
+		asset2ID,
+		poolTokenAssetID,
+		asset1Amount,
+		asset2Amount,
+		[][]byte{[]byte(constants.ADD_INITIAL_LIQUIDITY_APP_ARGUMENT)},
+		constants.ADD_INITIAL_LIQUIDITY_INNER_TRANSACTIONS,
+		senderAddress,
+		suggestedParams,
+		options,
+	)
+
+}
+
+// PrepareFlexibleAddLiquidityTransactions adds any amounts of both assets, the pool swaps the surplus of one asset internally.
+func PrepareFlexibleAddLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID int, asset1Amount, asset2Amount, minPoolTokenAssetAmount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	appArgs := [][]byte{
+		[]byte(constants.ADD_LIQUIDITY_APP_ARGUMENT),
+		[]byte(constants.ADD_LIQUIDITY_FLEXIBLE_MODE),
+		utils.IntToBytes(int(utils.NewBigIntString(minPoolTokenAssetAmount).Uint64())),
+	}
+
+	return prepareAddLiquidityTransactions(
+		validatorAppId,
+		asset1ID,
+		asset2ID,
+		poolTokenAssetID,
+		asset1Amount,
+		asset2Amount,
+		appArgs,
+		constants.ADD_LIQUIDITY_INNER_TRANSACTIONS,
+		senderAddress,
+		suggestedParams,
+		options,
+	)
+
+}
+
+// PrepareSingleAssetAddLiquidityTransactions adds an amount of one asset, the pool swaps part of it internally.
+func PrepareSingleAssetAddLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID, assetInID int, assetInAmount, minPoolTokenAssetAmount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	asset1Amount, asset2Amount := "0", "0"
+	if assetInID == asset1ID {
+		asset1Amount = assetInAmount
+	} else {
+		asset2Amount = assetInAmount
+	}
+
+	appArgs := [][]byte{
+		[]byte(constants.ADD_LIQUIDITY_APP_ARGUMENT),
+		[]byte(constants.ADD_LIQUIDITY_SINGLE_MODE),
+		utils.IntToBytes(int(utils.NewBigIntString(minPoolTokenAssetAmount).Uint64())),
+	}
+
+	return prepareAddLiquidityTransactions(
+		validatorAppId,
+		asset1ID,
+		asset2ID,
+		poolTokenAssetID,
+		asset1Amount,
+		asset2Amount,
+		appArgs,
+		constants.ADD_LIQUIDITY_INNER_TRANSACTIONS,
+		senderAddress,
+		suggestedParams,
+		options,
+	)
+
+}
+
+// prepareAddLiquidityTransactions transfers the non zero amounts to the pool followed by the app call.
+func prepareAddLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID int, asset1Amount, asset2Amount string, appArgs [][]byte, innerTransactions int, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppId, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	var txns []algoTypes.Transaction
+
+	amounts := []struct {
+		assetID int
+		amount  string
+	}{
+		{asset1ID, asset1Amount},
+		{asset2ID, asset2Amount},
+	}
+
+	for _, a := range amounts {
+
+		if utils.NewBigIntString(a.amount).Sign() == 0 {
+			continue
+		}
+
+		var txn algoTypes.Transaction
+		txn, err = utils.MakeTransferTxn(sender.String(), poolAddress, a.assetID, a.amount, nil, algoSuggestedParams)
+		if err != nil {
+			return
+		}
+
+		txns = append(txns, txn)
+
+	}
+
+	applicationNoOpTxn, err := future.MakeApplicationNoOpTx(uint64(validatorAppId), appArgs, []string{poolAddress}, nil, []uint64{uint64(poolTokenAssetID)}, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	txns = append(txns, applicationNoOpTxn)
+
+	txns, err = utils.ApplyTxnOptionsWithInnerTransactions(txns, sender, options, map[int]int{len(txns) - 1: innerTransactions})
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+
+	return
+
+}
@@ -0,0 +1,63 @@
+package addliquidity
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareAddLiquidityTransactions(t *testing.T) {
+
+	sender := crypto.GenerateAccount().Address.String()
+	suggestedParams := &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, 10, 0)
+	assert.Nil(t, err)
+
+	txnGroup, err := PrepareInitialAddLiquidityTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 20, "1000", "2000", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns := txnGroup.GetTransactions()
+	assert.Equal(t, 3, len(txns))
+
+	assert.Equal(t, algoTypes.AssetTransferTx, txns[0].Type)
+	assert.Equal(t, algoTypes.AssetIndex(10), txns[0].XferAsset)
+	assert.Equal(t, poolAddress, txns[0].AssetReceiver.String())
+	assert.Equal(t, uint64(1000), txns[0].AssetAmount)
+
+	assert.Equal(t, algoTypes.PaymentTx, txns[1].Type)
+	assert.Equal(t, poolAddress, txns[1].Receiver.String())
+	assert.Equal(t, algoTypes.MicroAlgos(2000), txns[1].Amount)
+
+	assert.Equal(t, [][]byte{[]byte(constants.ADD_INITIAL_LIQUIDITY_APP_ARGUMENT)}, txns[2].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{20}, txns[2].ForeignAssets)
+	assert.Equal(t, poolAddress, txns[2].Accounts[0].String())
+	assert.Equal(t, algoTypes.MicroAlgos((constants.ADD_INITIAL_LIQUIDITY_INNER_TRANSACTIONS+1)*1000), txns[2].Fee)
+
+	txnGroup, err = PrepareFlexibleAddLiquidityTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 20, "1000", "2000", "500", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, 3, len(txns))
+	assert.Equal(t, [][]byte{[]byte(constants.ADD_LIQUIDITY_APP_ARGUMENT), []byte(constants.ADD_LIQUIDITY_FLEXIBLE_MODE), utils.IntToBytes(500)}, txns[2].ApplicationArgs)
+	assert.Equal(t, algoTypes.MicroAlgos((constants.ADD_LIQUIDITY_INNER_TRANSACTIONS+1)*1000), txns[2].Fee)
+
+	// a single asset add only transfers the asset in
+	txnGroup, err = PrepareSingleAssetAddLiquidityTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 20, 0, "2000", "500", sender, suggestedParams, &types.TxnOptions{FlatFee: 1500})
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, 2, len(txns))
+	assert.Equal(t, algoTypes.PaymentTx, txns[0].Type)
+	assert.Equal(t, algoTypes.MicroAlgos(1500), txns[0].Fee)
+	assert.Equal(t, [][]byte{[]byte(constants.ADD_LIQUIDITY_APP_ARGUMENT), []byte(constants.ADD_LIQUIDITY_SINGLE_MODE), utils.IntToBytes(500)}, txns[1].ApplicationArgs)
+	assert.Equal(t, algoTypes.MicroAlgos((constants.ADD_LIQUIDITY_INNER_TRANSACTIONS+1)*1500), txns[1].Fee)
+
+}
@@ -0,0 +1,115 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// GetBootstrapAppCallFee returns the fee of the bootstrap app call, which pays for its inner transactions.
+func GetBootstrapAppCallFee(asset2ID int, suggestedParams *types.SuggestedParams) int {
+
+	innerTransactions := constants.BOOTSTRAP_INNER_TRANSACTIONS_ASA_PAIR
+	if asset2ID == 0 {
+		innerTransactions = constants.BOOTSTRAP_INNER_TRANSACTIONS_ALGO_PAIR
+	}
+
+	minFee := types.GetConsensusParams(suggestedParams.ConsensusVersion).MinTxnFee
+	if suggestedParams.MinFee > minFee {
+		minFee = suggestedParams.MinFee
+	}
+
+	return (innerTransactions + 1) * minFee
+
+}
+
+// GetPoolMinBalance returns the minimum balance of a bootstrapped pool account: its local state in the validator app,
+// the pool token it creates and its opt ins to the pool assets.
+func GetPoolMinBalance(asset2ID int, consensusVersion string) int {
+
+	params := types.GetConsensusParams(consensusVersion)
+
+	numAssets := 2
+	if asset2ID != 0 {
+		numAssets = 3
+	}
+
+	return params.AccountMinBalance(numAssets, 1, 0, constants.POOL_LOCAL_STATE_NUM_UINTS, constants.POOL_LOCAL_STATE_NUM_BYTE_SLICES, 0)
+
+}
+
+// PrepareBootstrapTransactions funds the pool account with requiredAlgo and opts it in to the validator app,
+// which creates the pool token, opts the pool in to the assets and rekeys the pool account to the app.
+// requiredAlgo is the pool min balance plus the bootstrap app call fee, minus what the pool account already holds.
+func PrepareBootstrapTransactions(validatorAppId, asset1ID, asset2ID int, requiredAlgo, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	if asset1ID <= asset2ID {
+		err = fmt.Errorf("asset1ID must be greater than asset2ID")
+		return
+	}
+
+	poolLogicsig, err := contracts.GetPoolLogicsig(validatorAppId, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	poolAddress := crypto.AddressFromProgram(poolLogicsig.Logic)
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	var txns []algoTypes.Transaction
+
+	if utils.NewBigIntString(requiredAlgo).Sign() > 0 {
+
+		var paymentTxn algoTypes.Transaction
+		paymentTxn, err = utils.MakeTransferTxn(sender.String(), poolAddress.String(), 0, requiredAlgo, nil, algoSuggestedParams)
+		if err != nil {
+			return
+		}
+
+		txns = append(txns, paymentTxn)
+
+	}
+
+	applicationAddress := crypto.GetApplicationAddress(uint64(validatorAppId))
+
+	applicationOptInTxn, err := future.MakeApplicationOptInTx(uint64(validatorAppId), [][]byte{[]byte(constants.BOOTSTRAP_APP_ARGUMENT)}, nil, nil, []uint64{uint64(asset1ID), uint64(asset2ID)}, algoSuggestedParams, poolAddress, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	applicationOptInTxn.RekeyTo = applicationAddress
+	applicationOptInTxn.Fee = algoTypes.MicroAlgos(GetBootstrapAppCallFee(asset2ID, suggestedParams))
+
+	txns = append(txns, applicationOptInTxn)
+
+	txns, err = utils.ApplyTxnOptions(txns, sender, options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+	if err != nil {
+		return
+	}
+
+	err = txnGroup.SignWithLogicsig(poolLogicsig)
+
+	return
+
+}
@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPoolMinBalance(t *testing.T) {
+
+	assert.Equal(t, 842000, GetPoolMinBalance(0, ""))
+	assert.Equal(t, 942000, GetPoolMinBalance(5, ""))
+
+	suggestedParams := &types.SuggestedParams{MinFee: 2000}
+	assert.Equal(t, 12000, GetBootstrapAppCallFee(0, suggestedParams))
+	assert.Equal(t, 14000, GetBootstrapAppCallFee(5, suggestedParams))
+
+}
+
+func TestPrepareBootstrapTransactions(t *testing.T) {
+
+	sender := crypto.GenerateAccount().Address.String()
+	suggestedParams := &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, 10, 0)
+	assert.Nil(t, err)
+
+	txnGroup, err := PrepareBootstrapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, "848000", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns := txnGroup.GetTransactions()
+	assert.Equal(t, 2, len(txns))
+
+	assert.Equal(t, algoTypes.PaymentTx, txns[0].Type)
+	assert.Equal(t, sender, txns[0].Sender.String())
+	assert.Equal(t, poolAddress, txns[0].Receiver.String())
+	assert.Equal(t, algoTypes.MicroAlgos(848000), txns[0].Amount)
+
+	// the pool account opts in to the app, which rekeys it to the app account, and pays for the inner transactions
+	assert.Equal(t, algoTypes.ApplicationCallTx, txns[1].Type)
+	assert.Equal(t, algoTypes.OptInOC, txns[1].OnCompletion)
+	assert.Equal(t, poolAddress, txns[1].Sender.String())
+	assert.Equal(t, crypto.GetApplicationAddress(constants.TESTNET_VALIDATOR_APP_ID), txns[1].RekeyTo)
+	assert.Equal(t, [][]byte{[]byte(constants.BOOTSTRAP_APP_ARGUMENT)}, txns[1].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{10, 0}, txns[1].ForeignAssets)
+	assert.Equal(t, algoTypes.MicroAlgos((constants.BOOTSTRAP_INNER_TRANSACTIONS_ALGO_PAIR+1)*1000), txns[1].Fee)
+
+	assert.Equal(t, 0, len(txnGroup.GetSignedTransactions()[0]))
+	assert.NotEqual(t, 0, len(txnGroup.GetSignedTransactions()[1]))
+
+	// a funded pool account only opts in
+	txnGroup, err = PrepareBootstrapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 5, "0", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, 1, len(txns))
+	assert.Equal(t, algoTypes.MicroAlgos((constants.BOOTSTRAP_INNER_TRANSACTIONS_ASA_PAIR+1)*1000), txns[0].Fee)
+
+	_, err = PrepareBootstrapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 0, 10, "0", sender, suggestedParams, nil)
+	assert.NotNil(t, err)
+
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v1/optin"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/algod"
+	"github.com/algorand/go-algorand-sdk/client/v2/common"
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/client/v2/indexer"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// TinymanClient is the client of the Tinyman v2 protocol.
+// Unlike v1, users do not opt in to the v2 validator app, they only opt in to the pool tokens they hold.
+type TinymanClient struct {
+	algod          *algod.Client
+	indexer        *indexer.Client
+	ValidatorAppId int `json:"validator-app-id"`
+	assetsCache    map[int]*types.Asset
+	UserAddress    string `json:"user-address"`
+}
+
+func NewTinymanClient(algodClientURL, indexerClientURL string, validatorAppId int, userAddress string) (tinymanClient *TinymanClient, err error) {
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	headers := []*common.Header{
+		{
+			Key:   "User-Agent",
+			Value: "algosdk",
+		},
+	}
+
+	algodClient, err := algod.MakeClientWithHeaders(algodClientURL, "", headers)
+	if err != nil {
+		return
+	}
+
+	indexerClient, err := indexer.MakeClientWithHeaders(indexerClientURL, "", headers)
+	if err != nil {
+		return
+	}
+
+	return &TinymanClient{
+		algodClient,
+		indexerClient,
+		validatorAppId,
+		map[int]*types.Asset{},
+		user.String(),
+	}, nil
+}
+
+func NewTinymanTestnetClient(algodClientURL, indexerClientURL, userAddress string) (tinymanClient *TinymanClient, err error) {
+
+	return NewTinymanClient(algodClientURL, indexerClientURL, constants.TESTNET_VALIDATOR_APP_ID, userAddress)
+
+}
+
+func NewTinymanMainnetClient(algodClientURL, indexerClientURL, userAddress string) (tinymanClient *TinymanClient, err error) {
+
+	return NewTinymanClient(algodClientURL, indexerClientURL, constants.MAINNET_VALIDATOR_APP_ID, userAddress)
+
+}
+
+func (s *TinymanClient) FetchAsset(assetID int) (asset *types.Asset, err error) {
+
+	if _, ok := s.assetsCache[assetID]; !ok {
+
+		asset = &types.Asset{Id: assetID}
+		err = asset.Fetch(s.indexer)
+
+		if err != nil {
+			return
+		}
+
+		s.assetsCache[assetID] = asset
+
+	}
+
+	asset = s.assetsCache[assetID]
+	return
+
+}
+
+// not compatible with go-mobile
+func (s *TinymanClient) LookupAccountByID(address string) (validRound uint64, result models.Account, err error) {
+
+	return s.indexer.LookupAccountByID(address).Do(context.Background())
+
+}
+
+// not compatible with go-mobile
+func (s *TinymanClient) AccountInformation(address string) (response models.Account, err error) {
+	return s.algod.AccountInformation(address).Do(context.Background())
+}
+
+// not compatible with go-mobile
+func (s *TinymanClient) SuggestedParams() (params algoTypes.SuggestedParams, err error) {
+	return s.algod.SuggestedParams().Do(context.Background())
+}
+
+// GetSuggestedParams returns the cached suggested params of options, or fetches them from algod.
+func (s *TinymanClient) GetSuggestedParams(options *types.TxnOptions) (suggestedParams *types.SuggestedParams, err error) {
+
+	if options != nil && options.SuggestedParams != nil {
+		suggestedParams = options.SuggestedParams
+		return
+	}
+
+	algoSuggestedParams, err := s.SuggestedParams()
+	if err != nil {
+		return
+	}
+
+	suggestedParams = utils.FromAlgoSuggestedParams(algoSuggestedParams)
+	return
+
+}
+
+func (s *TinymanClient) Submit(transactionGroup *utils.TransactionGroup, wait bool) (transactionInformation *types.TransactionInformation, err error) {
+
+	signedGroup := transactionGroup.GetSignedGroup()
+
+	txid, err := s.algod.SendRawTransaction(signedGroup).Do(context.Background())
+	if err != nil {
+		return
+	}
+
+	if wait {
+		return utils.WaitForConfirmation(s.algod, txid)
+	}
+
+	transactionInformation = &types.TransactionInformation{
+		TxId: txid,
+	}
+	return
+
+}
+
+func (s *TinymanClient) PrepareAssetOptinTransactions(assetID int, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	if len(userAddress) == 0 {
+		userAddress = s.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = optin.PrepareAssetOptinTransactions(assetID, user.String(), suggestedParams, options)
+
+	return
+
+}
+
+func (s *TinymanClient) AssetIsOptedIn(assetID int, userAddress string) (bool, error) {
+
+	if len(userAddress) == 0 {
+		userAddress = s.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(userAddress)
+	if err != nil {
+		return false, err
+	}
+
+	if assetID == 0 {
+		return true, nil
+	}
+
+	accountInfo, err := s.AccountInformation(user.String())
+	if err != nil {
+		return false, err
+	}
+
+	for _, a := range accountInfo.Assets {
+		if a.AssetId == uint64(assetID) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+
+}
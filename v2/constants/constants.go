@@ -0,0 +1,46 @@
+package constants
+
+const (
+	BOOTSTRAP_APP_ARGUMENT             = "bootstrap"
+	ADD_INITIAL_LIQUIDITY_APP_ARGUMENT = "add_initial_liquidity"
+	ADD_LIQUIDITY_APP_ARGUMENT         = "add_liquidity"
+	REMOVE_LIQUIDITY_APP_ARGUMENT      = "remove_liquidity"
+	SWAP_APP_ARGUMENT                  = "swap"
+	FLASH_LOAN_APP_ARGUMENT            = "flash_loan"
+	VERIFY_FLASH_LOAN_APP_ARGUMENT     = "verify_flash_loan"
+
+	ADD_LIQUIDITY_FLEXIBLE_MODE = "flexible"
+	ADD_LIQUIDITY_SINGLE_MODE   = "single"
+
+	FIXED_INPUT_SWAP_TYPE  = "fixed-input"
+	FIXED_OUTPUT_SWAP_TYPE = "fixed-output"
+
+	TESTNET_VALIDATOR_APP_ID_V2 = 148607000
+	MAINNET_VALIDATOR_APP_ID_V2 = 1002541853
+
+	TESTNET_VALIDATOR_APP_ID = TESTNET_VALIDATOR_APP_ID_V2
+	MAINNET_VALIDATOR_APP_ID = MAINNET_VALIDATOR_APP_ID_V2
+
+	// LOCKED_POOL_TOKENS are the pool tokens of the first liquidity provider that stay in the pool forever.
+	LOCKED_POOL_TOKENS = 1000
+
+	// fees are in basis points of the input amount, the protocol takes 1/PROTOCOL_FEE_RATIO of them
+	DEFAULT_TOTAL_FEE_SHARE    = 30
+	DEFAULT_PROTOCOL_FEE_RATIO = 6
+
+	// the pool account keeps its state in its local state of the validator app
+	POOL_LOCAL_STATE_NUM_UINTS       = 12
+	POOL_LOCAL_STATE_NUM_BYTE_SLICES = 2
+
+	POOL_TOKEN_UNIT_NAME = "TMPOOL2"
+
+	// number of inner transactions of the app calls, the app call fee pays for them
+	BOOTSTRAP_INNER_TRANSACTIONS_ALGO_PAIR     = 5
+	BOOTSTRAP_INNER_TRANSACTIONS_ASA_PAIR      = 6
+	ADD_INITIAL_LIQUIDITY_INNER_TRANSACTIONS   = 1
+	ADD_LIQUIDITY_INNER_TRANSACTIONS           = 2
+	REMOVE_LIQUIDITY_INNER_TRANSACTIONS        = 2
+	SINGLE_REMOVE_LIQUIDITY_INNER_TRANSACTIONS = 1
+	FIXED_INPUT_SWAP_INNER_TRANSACTIONS        = 1
+	FIXED_OUTPUT_SWAP_INNER_TRANSACTIONS       = 2
+)
@@ -0,0 +1,227 @@
+package contracts
+
+import (
+	"embed"
+	b64 "encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+)
+
+//go:embed asc.json
+var f embed.FS
+
+func readContractsFile() (data types.ASC, err error) {
+
+	file, err := f.ReadFile("asc.json")
+
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(file, &data)
+
+	return
+
+}
+
+// pinnedPoolLogicsigTemplateAddress is the address of the pool logicsig template of tinyman-amm-contracts-v2
+// the pools of the mainnet validator are created with, see pinnedPool.
+const pinnedPoolLogicsigTemplateAddress = "IGZA244ELTOS76JOOFBUFBZK75ZN6WH556OTTJTS4G67R7XGVEHGYSH3AA"
+
+// pinnedPool is a mainnet pool the bundled template must derive, the ALGO/USDC pool.
+var pinnedPool = struct {
+	validatorAppID int
+	asset1ID       int
+	asset2ID       int
+	address        string
+}{constants.MAINNET_VALIDATOR_APP_ID_V2, 31566704, 0, "2PIFZW53RHCSFSYMCFUBW4XOCXOMB7XOYQSQ6KGT3KVGJTL4HM6COZRNMM"}
+
+var (
+	poolLogicsigDefinition   types.Logic
+	poolLogicsigTemplate     []byte
+	poolLogicsigVariables    []types.Variable
+	poolLogicsigTemplateErr  error
+	poolLogicsigTemplateOnce sync.Once
+)
+
+// readPoolLogicsigTemplate returns the decoded pool logicsig template and its variables sorted by index,
+// asc.json is only parsed by the first call. The template must be the pinned one and derive the pinned pool.
+func readPoolLogicsigTemplate() ([]byte, []types.Variable, error) {
+
+	poolLogicsigTemplateOnce.Do(func() {
+
+		contracts, err := readContractsFile()
+		if err != nil {
+			poolLogicsigTemplateErr = err
+			return
+		}
+
+		poolLogicsigDef := contracts.Contracts.PoolLogicsig.Logic
+		poolLogicsigDefinition = poolLogicsigDef
+
+		poolLogicsigTemplate, poolLogicsigTemplateErr = b64.StdEncoding.DecodeString(poolLogicsigDef.Bytecode)
+		if poolLogicsigTemplateErr != nil {
+			return
+		}
+
+		if address := crypto.AddressFromProgram(poolLogicsigTemplate).String(); address != poolLogicsigDef.Address || address != pinnedPoolLogicsigTemplateAddress {
+			poolLogicsigTemplateErr = fmt.Errorf("pool logicsig template address is %s, expected %s", address, pinnedPoolLogicsigTemplateAddress)
+			return
+		}
+
+		poolLogicsigVariables = append([]types.Variable{}, poolLogicsigDef.Variables...)
+		sort.SliceStable(poolLogicsigVariables, func(i, j int) bool {
+			return poolLogicsigVariables[i].Index < poolLogicsigVariables[j].Index
+		})
+
+		program, err := utils.GetProgramFromTemplate(poolLogicsigTemplate, poolLogicsigVariables, map[string]int{
+			"validator_app_id": pinnedPool.validatorAppID,
+			"asset_id_1":       pinnedPool.asset1ID,
+			"asset_id_2":       pinnedPool.asset2ID,
+		})
+		if err != nil {
+			poolLogicsigTemplateErr = err
+			return
+		}
+
+		if address := crypto.AddressFromProgram(program).String(); address != pinnedPool.address {
+			poolLogicsigTemplateErr = fmt.Errorf("pool logicsig template derives %s for the pinned pool %s", address, pinnedPool.address)
+		}
+
+	})
+
+	return poolLogicsigTemplate, poolLogicsigVariables, poolLogicsigTemplateErr
+
+}
+
+// POOL_LOGICSIG_CACHE_SIZE is the number of pool logicsigs kept compiled.
+const POOL_LOGICSIG_CACHE_SIZE = 1024
+
+type poolKey struct {
+	validatorAppID int
+	asset1ID       int
+	asset2ID       int
+}
+
+type poolLogicsig struct {
+	logic   []byte
+	address string
+}
+
+var poolLogicsigs = utils.NewLRUCache(POOL_LOGICSIG_CACHE_SIZE)
+
+// getPoolLogicsig returns the compiled pool logicsig of validatorAppID, asset1ID and asset2ID from the cache,
+// compiling it on a miss.
+func getPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (pool *poolLogicsig, err error) {
+
+	assetID1 := asset1ID
+	assetID2 := asset2ID
+	if assetID1 < assetID2 {
+		assetID1, assetID2 = assetID2, assetID1
+	}
+
+	key := poolKey{validatorAppID, assetID1, assetID2}
+
+	if cached, ok := poolLogicsigs.Get(key); ok {
+		pool = cached.(*poolLogicsig)
+		return
+	}
+
+	template, templateVariables, err := readPoolLogicsigTemplate()
+	if err != nil {
+		return
+	}
+
+	variables := map[string]int{
+		"validator_app_id": validatorAppID,
+		"asset_id_1":       assetID1,
+		"asset_id_2":       assetID2,
+	}
+
+	programBytes, err := utils.GetProgramFromTemplate(template, templateVariables, variables)
+	if err != nil {
+		return
+	}
+
+	pool = &poolLogicsig{
+		logic:   programBytes,
+		address: crypto.AddressFromProgram(programBytes).String(),
+	}
+
+	poolLogicsigs.Add(key, pool)
+
+	return
+
+}
+
+// GetPoolLogicsig returns the logicsig of the v2 pool of asset1ID and asset2ID.
+// Unlike v1, it only signs the opt in of the pool account to the validator app, which rekeys the account to the app.
+func GetPoolLogicsig(validatorAppID, asset1ID, asset2ID int) (lsig *types.LogicSig, err error) {
+
+	pool, err := getPoolLogicsig(validatorAppID, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	// the cached program is shared, callers get their own copy
+	lsig = &types.LogicSig{
+		Logic: append([]byte{}, pool.logic...),
+	}
+
+	return
+
+}
+
+// GetPoolAddress returns the address of the v2 pool of asset1ID and asset2ID.
+func GetPoolAddress(validatorAppID, asset1ID, asset2ID int) (poolAddress string, err error) {
+
+	pool, err := getPoolLogicsig(validatorAppID, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	poolAddress = pool.address
+
+	return
+
+}
+
+// PoolLogicsigVariables are the values of the template variables of a v2 pool logicsig.
+type PoolLogicsigVariables struct {
+	ValidatorAppId int `json:"validator_app_id"`
+	Asset1Id       int `json:"asset_1_id"`
+	Asset2Id       int `json:"asset_2_id"`
+}
+
+// ParsePoolLogicsig recognizes a v2 pool logicsig program, e.g. the logicsig of the bootstrap transaction,
+// and returns its validator app and assets.
+func ParsePoolLogicsig(program []byte) (variables *PoolLogicsigVariables, err error) {
+
+	_, _, err = readPoolLogicsigTemplate()
+	if err != nil {
+		return
+	}
+
+	values, err := utils.ExtractVariables(poolLogicsigDefinition, program)
+	if err != nil {
+		err = fmt.Errorf("program is not a pool logicsig: %s", err)
+		return
+	}
+
+	variables = &PoolLogicsigVariables{
+		ValidatorAppId: values["validator_app_id"].(int),
+		Asset1Id:       values["asset_id_1"].(int),
+		Asset2Id:       values["asset_id_2"].(int),
+	}
+
+	return
+
+}
@@ -0,0 +1,53 @@
+package contracts
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadContractsFile(t *testing.T) {
+
+	contracts, err := readContractsFile()
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, types.ASC{}, contracts)
+	assert.Equal(t, "IGZA244ELTOS76JOOFBUFBZK75ZN6WH556OTTJTS4G67R7XGVEHGYSH3AA", contracts.Contracts.PoolLogicsig.Logic.Address)
+
+	template, variables, err := readPoolLogicsigTemplate()
+	assert.Nil(t, err)
+	assert.Equal(t, contracts.Contracts.PoolLogicsig.Logic.Size, len(template))
+	assert.Equal(t, 3, len(variables))
+	assert.Equal(t, pinnedPoolLogicsigTemplateAddress, crypto.AddressFromProgram(template).String())
+
+}
+
+func TestGetPoolAddress(t *testing.T) {
+
+	// mainnet ALGO/USDC pool
+	expectedAddress := "2PIFZW53RHCSFSYMCFUBW4XOCXOMB7XOYQSQ6KGT3KVGJTL4HM6COZRNMM"
+
+	poolAddress, err := GetPoolAddress(constants.MAINNET_VALIDATOR_APP_ID_V2, 0, 31566704)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedAddress, poolAddress)
+
+	poolAddress, err = GetPoolAddress(constants.MAINNET_VALIDATOR_APP_ID_V2, 31566704, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedAddress, poolAddress)
+
+}
+
+func TestParsePoolLogicsig(t *testing.T) {
+
+	lsig, err := GetPoolLogicsig(constants.MAINNET_VALIDATOR_APP_ID_V2, 0, 31566704)
+	assert.Nil(t, err)
+
+	variables, err := ParsePoolLogicsig(lsig.Logic)
+	assert.Nil(t, err)
+	assert.Equal(t, &PoolLogicsigVariables{ValidatorAppId: constants.MAINNET_VALIDATOR_APP_ID_V2, Asset1Id: 31566704, Asset2Id: 0}, variables)
+
+}
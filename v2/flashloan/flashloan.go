@@ -0,0 +1,124 @@
+package flashloan
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// PrepareFlashLoanTransactions borrows the loan amounts from the pool, runs the unsigned transactions of
+// innerGroup (may be nil) and pays back the payment amounts, the loans plus the flash loan fees, in one group.
+// The flash loan app call sends the loans and the verify app call at the end checks the payments.
+func PrepareFlashLoanTransactions(validatorAppId, asset1ID, asset2ID int, asset1LoanAmount, asset2LoanAmount, asset1PaymentAmount, asset2PaymentAmount string, innerGroup *utils.TransactionGroup, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	loans := []struct {
+		assetID int
+		loan    string
+		payment string
+	}{
+		{asset1ID, asset1LoanAmount, asset1PaymentAmount},
+		{asset2ID, asset2LoanAmount, asset2PaymentAmount},
+	}
+
+	paymentCount := 0
+	for _, l := range loans {
+		if utils.NewBigIntString(l.loan).Sign() > 0 {
+			paymentCount++
+		}
+	}
+
+	if paymentCount == 0 {
+		err = fmt.Errorf("flash loan amounts are zero")
+		return
+	}
+
+	var transactions []algoTypes.Transaction
+	if innerGroup != nil {
+		for _, txn := range innerGroup.GetTransactions() {
+			txn.Group = algoTypes.Digest{}
+			transactions = append(transactions, txn)
+		}
+	}
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppId, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	// the flash loan call references the verify call and the verify call references the first payment by their distance
+	indexDiff := len(transactions) + paymentCount + 1
+
+	appArgs := [][]byte{
+		[]byte(constants.FLASH_LOAN_APP_ARGUMENT),
+		utils.IntToBytes(indexDiff),
+		utils.IntToBytes(int(utils.NewBigIntString(asset1LoanAmount).Uint64())),
+		utils.IntToBytes(int(utils.NewBigIntString(asset2LoanAmount).Uint64())),
+	}
+
+	flashLoanTxn, err := future.MakeApplicationNoOpTx(uint64(validatorAppId), appArgs, []string{poolAddress}, nil, []uint64{uint64(asset1ID), uint64(asset2ID)}, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	txns := []algoTypes.Transaction{flashLoanTxn}
+	txns = append(txns, transactions...)
+
+	for _, l := range loans {
+
+		if utils.NewBigIntString(l.loan).Sign() == 0 {
+			continue
+		}
+
+		var paymentTxn algoTypes.Transaction
+		paymentTxn, err = utils.MakeTransferTxn(sender.String(), poolAddress, l.assetID, l.payment, nil, algoSuggestedParams)
+		if err != nil {
+			return
+		}
+
+		txns = append(txns, paymentTxn)
+
+	}
+
+	verifyAppArgs := [][]byte{
+		[]byte(constants.VERIFY_FLASH_LOAN_APP_ARGUMENT),
+		utils.IntToBytes(paymentCount + 1),
+	}
+
+	verifyTxn, err := future.MakeApplicationNoOpTx(uint64(validatorAppId), verifyAppArgs, []string{poolAddress}, nil, nil, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	txns = append(txns, verifyTxn)
+
+	if len(txns) > algoTypes.MaxTxGroupSize {
+		err = fmt.Errorf("flash loan group has %d transactions, max group size is %d", len(txns), algoTypes.MaxTxGroupSize)
+		return
+	}
+
+	txns, err = utils.ApplyTxnOptionsWithInnerTransactions(txns, sender, options, map[int]int{0: paymentCount})
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+
+	return
+
+}
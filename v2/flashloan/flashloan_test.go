@@ -0,0 +1,71 @@
+package flashloan
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareFlashLoanTransactions(t *testing.T) {
+
+	sender := crypto.GenerateAccount().Address.String()
+	suggestedParams := &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, 10, 0)
+	assert.Nil(t, err)
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, nil)
+	assert.Nil(t, err)
+
+	innerTxn, err := future.MakePaymentTxn(sender, sender, 0, nil, "", algoSuggestedParams)
+	assert.Nil(t, err)
+
+	innerGroup, err := utils.NewTransactionGroup([]algoTypes.Transaction{innerTxn})
+	assert.Nil(t, err)
+
+	txnGroup, err := PrepareFlashLoanTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, "1000", "2000", "1003", "2006", innerGroup, sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	// flash loan call, inner group, the two payments and the verify call
+	txns := txnGroup.GetTransactions()
+	assert.Equal(t, 5, len(txns))
+
+	assert.Equal(t, [][]byte{[]byte(constants.FLASH_LOAN_APP_ARGUMENT), utils.IntToBytes(4), utils.IntToBytes(1000), utils.IntToBytes(2000)}, txns[0].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{10, 0}, txns[0].ForeignAssets)
+	assert.Equal(t, poolAddress, txns[0].Accounts[0].String())
+	assert.Equal(t, algoTypes.MicroAlgos(3000), txns[0].Fee)
+
+	assert.Equal(t, innerTxn.Receiver, txns[1].Receiver)
+	assert.Equal(t, txns[0].Group, txns[1].Group)
+
+	assert.Equal(t, algoTypes.AssetIndex(10), txns[2].XferAsset)
+	assert.Equal(t, uint64(1003), txns[2].AssetAmount)
+	assert.Equal(t, poolAddress, txns[3].Receiver.String())
+	assert.Equal(t, algoTypes.MicroAlgos(2006), txns[3].Amount)
+
+	assert.Equal(t, [][]byte{[]byte(constants.VERIFY_FLASH_LOAN_APP_ARGUMENT), utils.IntToBytes(3)}, txns[4].ApplicationArgs)
+	assert.Equal(t, 0, len(txns[4].ForeignAssets))
+	assert.Equal(t, algoTypes.MicroAlgos(1000), txns[4].Fee)
+
+	// a loan of one asset has one inner transaction and one payment
+	txnGroup, err = PrepareFlashLoanTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, "0", "2000", "0", "2006", nil, sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, 3, len(txns))
+	assert.Equal(t, utils.IntToBytes(2), txns[0].ApplicationArgs[1])
+	assert.Equal(t, algoTypes.MicroAlgos(2000), txns[0].Fee)
+	assert.Equal(t, utils.IntToBytes(2), txns[2].ApplicationArgs[1])
+
+	_, err = PrepareFlashLoanTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, "0", "0", "0", "0", nil, sender, suggestedParams, nil)
+	assert.NotNil(t, err)
+
+}
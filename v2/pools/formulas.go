@@ -0,0 +1,210 @@
+package pools
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+)
+
+// The v2 pool math, in integers and rounded the way the validator app rounds.
+// Fees are in basis points of the input amount.
+
+var basisPoints = big.NewInt(10000)
+
+func calculateFixedInputFeeAmount(inputAmount *big.Int, totalFeeShare int) *big.Int {
+
+	fee := new(big.Int).Mul(inputAmount, big.NewInt(int64(totalFeeShare)))
+	return fee.Quo(fee, basisPoints)
+
+}
+
+// calculateFixedOutputFeeAmount is the fee on top of swapAmount, so that it is totalFeeShare of the whole input.
+func calculateFixedOutputFeeAmount(swapAmount *big.Int, totalFeeShare int) *big.Int {
+
+	inputAmount := new(big.Int).Mul(swapAmount, basisPoints)
+	inputAmount.Quo(inputAmount, new(big.Int).Sub(basisPoints, big.NewInt(int64(totalFeeShare))))
+
+	return inputAmount.Sub(inputAmount, swapAmount)
+
+}
+
+// calculateInternalSwapFeeAmount is the fee of the swaps the pool does while adding or removing liquidity.
+func calculateInternalSwapFeeAmount(swapAmount *big.Int, totalFeeShare int) *big.Int {
+
+	fee := new(big.Int).Mul(swapAmount, big.NewInt(int64(totalFeeShare)))
+	return fee.Quo(fee, new(big.Int).Sub(basisPoints, big.NewInt(int64(totalFeeShare))))
+
+}
+
+// calculateFixedInputSwap returns the output and the fee of swapping inputAmount.
+func calculateFixedInputSwap(inputSupply, outputSupply, inputAmount *big.Int, totalFeeShare int) (outputAmount, totalFeeAmount *big.Int, err error) {
+
+	totalFeeAmount = calculateFixedInputFeeAmount(inputAmount, totalFeeShare)
+	swapAmount := new(big.Int).Sub(inputAmount, totalFeeAmount)
+
+	k := new(big.Int).Mul(inputSupply, outputSupply)
+	newOutputSupply := new(big.Int).Quo(k, new(big.Int).Add(inputSupply, swapAmount))
+
+	// the output is rounded down by one more unit, in favor of the pool
+	outputAmount = new(big.Int).Sub(outputSupply, newOutputSupply)
+	outputAmount.Sub(outputAmount, big.NewInt(1))
+
+	if outputAmount.Sign() <= 0 {
+		err = fmt.Errorf("swap output amount is zero")
+	}
+
+	return
+
+}
+
+// calculateFixedOutputSwap returns the input and the fee of swapping for exactly outputAmount.
+func calculateFixedOutputSwap(inputSupply, outputSupply, outputAmount *big.Int, totalFeeShare int) (inputAmount, totalFeeAmount *big.Int, err error) {
+
+	if outputAmount.Cmp(outputSupply) >= 0 {
+		err = fmt.Errorf("swap output amount exceeds the pool reserves")
+		return
+	}
+
+	k := new(big.Int).Mul(inputSupply, outputSupply)
+	newInputSupply := new(big.Int).Quo(k, new(big.Int).Sub(outputSupply, outputAmount))
+
+	// the input is rounded up by one unit, in favor of the pool
+	swapAmount := new(big.Int).Sub(newInputSupply, inputSupply)
+	swapAmount.Add(swapAmount, big.NewInt(1))
+
+	totalFeeAmount = calculateFixedOutputFeeAmount(swapAmount, totalFeeShare)
+	inputAmount = new(big.Int).Add(swapAmount, totalFeeAmount)
+
+	return
+
+}
+
+// calculatePriceImpact is how much worse the swap price is than the pool price, e.g. 0.01 for 1%.
+func calculatePriceImpact(inputSupply, outputSupply, inputAmount, outputAmount *big.Int) float64 {
+
+	if inputAmount.Sign() == 0 || outputAmount.Sign() == 0 || outputSupply.Sign() == 0 {
+		return 0
+	}
+
+	poolPrice := new(big.Float).Quo(new(big.Float).SetInt(inputSupply), new(big.Float).SetInt(outputSupply))
+	swapPrice := new(big.Float).Quo(new(big.Float).SetInt(inputAmount), new(big.Float).SetInt(outputAmount))
+
+	impact := new(big.Float).Quo(poolPrice, swapPrice)
+	impact.Sub(big.NewFloat(1), impact)
+
+	priceImpact, _ := impact.Float64()
+	return priceImpact
+
+}
+
+// calculateInitialAddLiquidity returns the pool tokens of the first liquidity provider.
+func calculateInitialAddLiquidity(asset1Amount, asset2Amount *big.Int) (poolTokenAmount *big.Int, err error) {
+
+	poolTokenAmount = new(big.Int).Mul(asset1Amount, asset2Amount)
+	poolTokenAmount.Sqrt(poolTokenAmount)
+	poolTokenAmount.Sub(poolTokenAmount, big.NewInt(constants.LOCKED_POOL_TOKENS))
+
+	if poolTokenAmount.Sign() <= 0 {
+		err = fmt.Errorf("initial liquidity is too small, it must be worth more than %d pool tokens", constants.LOCKED_POOL_TOKENS)
+	}
+
+	return
+
+}
+
+// calculateSubsequentAddLiquidity returns the pool tokens of adding any amounts of both assets.
+// The surplus of one asset over the pool ratio is swapped internally and the fee of that swap is taken in pool tokens.
+// internalSwapAssetIndex is 1 or 2, the asset the pool swaps from, and 0 when the amounts already match the pool ratio.
+func calculateSubsequentAddLiquidity(asset1Reserves, asset2Reserves, issuedPoolTokens, asset1Amount, asset2Amount *big.Int, totalFeeShare int) (poolTokenAmount, internalSwapFeeAmount *big.Int, internalSwapAssetIndex int, err error) {
+
+	if asset1Reserves.Sign() == 0 || asset2Reserves.Sign() == 0 || issuedPoolTokens.Sign() == 0 {
+		err = fmt.Errorf("pool has no liquidity")
+		return
+	}
+
+	oldK := new(big.Int).Mul(asset1Reserves, asset2Reserves)
+
+	newAsset1Reserves := new(big.Int).Add(asset1Reserves, asset1Amount)
+	newAsset2Reserves := new(big.Int).Add(asset2Reserves, asset2Amount)
+	newK := new(big.Int).Mul(newAsset1Reserves, newAsset2Reserves)
+
+	newIssuedPoolTokens := new(big.Int).Mul(issuedPoolTokens, issuedPoolTokens)
+	newIssuedPoolTokens.Mul(newIssuedPoolTokens, newK)
+	newIssuedPoolTokens.Quo(newIssuedPoolTokens, oldK)
+	newIssuedPoolTokens.Sqrt(newIssuedPoolTokens)
+
+	poolTokenAmount = new(big.Int).Sub(newIssuedPoolTokens, issuedPoolTokens)
+
+	calculatedAsset1Amount := new(big.Int).Mul(poolTokenAmount, newAsset1Reserves)
+	calculatedAsset1Amount.Quo(calculatedAsset1Amount, newIssuedPoolTokens)
+	calculatedAsset2Amount := new(big.Int).Mul(poolTokenAmount, newAsset2Reserves)
+	calculatedAsset2Amount.Quo(calculatedAsset2Amount, newIssuedPoolTokens)
+
+	asset1SwapAmount := new(big.Int).Sub(asset1Amount, calculatedAsset1Amount)
+	asset2SwapAmount := new(big.Int).Sub(asset2Amount, calculatedAsset2Amount)
+
+	var swapInAmount, swapInReserves *big.Int
+	if asset1SwapAmount.Cmp(asset2SwapAmount) > 0 {
+		swapInAmount, swapInReserves, internalSwapAssetIndex = asset1SwapAmount, newAsset1Reserves, 1
+	} else {
+		swapInAmount, swapInReserves, internalSwapAssetIndex = asset2SwapAmount, newAsset2Reserves, 2
+	}
+
+	internalSwapFeeAmount = big.NewInt(0)
+
+	if swapInAmount.Sign() > 0 {
+
+		internalSwapFeeAmount = calculateInternalSwapFeeAmount(swapInAmount, totalFeeShare)
+
+		// the fee is worth its share of the reserves of its asset, i.e. half of the pool value
+		feeAsPoolTokens := new(big.Int).Mul(internalSwapFeeAmount, newIssuedPoolTokens)
+		feeAsPoolTokens.Quo(feeAsPoolTokens, new(big.Int).Mul(swapInReserves, big.NewInt(2)))
+
+		poolTokenAmount.Sub(poolTokenAmount, feeAsPoolTokens)
+
+	} else {
+		internalSwapAssetIndex = 0
+	}
+
+	if poolTokenAmount.Sign() <= 0 {
+		err = fmt.Errorf("liquidity amount is too small")
+	}
+
+	return
+
+}
+
+// calculateRemoveLiquidityOutputAmounts returns the share of the reserves of poolTokenAmount.
+func calculateRemoveLiquidityOutputAmounts(asset1Reserves, asset2Reserves, issuedPoolTokens, poolTokenAmount *big.Int) (asset1Amount, asset2Amount *big.Int, err error) {
+
+	if issuedPoolTokens.Sign() == 0 {
+		err = fmt.Errorf("pool has no liquidity")
+		return
+	}
+
+	if poolTokenAmount.Cmp(issuedPoolTokens) > 0 {
+		err = fmt.Errorf("pool token amount exceeds the issued pool tokens")
+		return
+	}
+
+	asset1Amount = new(big.Int).Mul(poolTokenAmount, asset1Reserves)
+	asset1Amount.Quo(asset1Amount, issuedPoolTokens)
+
+	asset2Amount = new(big.Int).Mul(poolTokenAmount, asset2Reserves)
+	asset2Amount.Quo(asset2Amount, issuedPoolTokens)
+
+	return
+
+}
+
+// calculateFlashLoanPaymentAmount returns the loan plus its fee, rounded up.
+func calculateFlashLoanPaymentAmount(loanAmount *big.Int, totalFeeShare int) *big.Int {
+
+	fee := new(big.Int).Mul(loanAmount, big.NewInt(int64(totalFeeShare)))
+	fee.Add(fee, new(big.Int).Sub(basisPoints, big.NewInt(1)))
+	fee.Quo(fee, basisPoints)
+
+	return fee.Add(fee, loanAmount)
+
+}
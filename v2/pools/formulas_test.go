@@ -0,0 +1,54 @@
+package pools
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwapFormulas(t *testing.T) {
+
+	asset1Reserves := big.NewInt(1000000)
+	asset2Reserves := big.NewInt(2000000)
+
+	outputAmount, feeAmount, err := calculateFixedInputSwap(asset1Reserves, asset2Reserves, big.NewInt(10000), 30)
+	assert.Nil(t, err)
+	assert.Equal(t, "19743", outputAmount.String())
+	assert.Equal(t, "30", feeAmount.String())
+
+	inputAmount, feeAmount, err := calculateFixedOutputSwap(asset1Reserves, asset2Reserves, big.NewInt(10000), 30)
+	assert.Nil(t, err)
+	assert.Equal(t, "5041", inputAmount.String())
+	assert.Equal(t, "15", feeAmount.String())
+
+	_, _, err = calculateFixedOutputSwap(asset1Reserves, asset2Reserves, asset2Reserves, 30)
+	assert.NotNil(t, err)
+
+	assert.InDelta(t, 0.0128, calculatePriceImpact(asset1Reserves, asset2Reserves, big.NewInt(10000), outputAmount), 0.0001)
+
+}
+
+func TestLiquidityFormulas(t *testing.T) {
+
+	poolTokenAmount, err := calculateInitialAddLiquidity(big.NewInt(1000000), big.NewInt(2000000))
+	assert.Nil(t, err)
+	assert.Equal(t, "1413213", poolTokenAmount.String())
+
+	_, err = calculateInitialAddLiquidity(big.NewInt(10), big.NewInt(10))
+	assert.NotNil(t, err)
+
+	poolTokenAmount, feeAmount, assetIndex, err := calculateSubsequentAddLiquidity(big.NewInt(1000000), big.NewInt(2000000), big.NewInt(1414213), big.NewInt(10000), big.NewInt(0), 30)
+	assert.Nil(t, err)
+	assert.Equal(t, "7043", poolTokenAmount.String())
+	assert.Equal(t, "15", feeAmount.String())
+	assert.Equal(t, 1, assetIndex)
+
+	asset1Amount, asset2Amount, err := calculateRemoveLiquidityOutputAmounts(big.NewInt(1000000), big.NewInt(2000000), big.NewInt(1414213), big.NewInt(14142))
+	assert.Nil(t, err)
+	assert.Equal(t, "9999", asset1Amount.String())
+	assert.Equal(t, "19999", asset2Amount.String())
+
+	assert.Equal(t, "10030", calculateFlashLoanPaymentAmount(big.NewInt(10000), 30).String())
+
+}
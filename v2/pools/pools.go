@@ -0,0 +1,926 @@
+package pools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/addliquidity"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/bootstrap"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/flashloan"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/removeliquidity"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/swap"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+type PoolInfo struct {
+	Address            string `json:"address"`
+	Asset1Id           int    `json:"asset1-id"`
+	Asset2Id           int    `json:"asset2-id"`
+	PoolTokenAssetId   int    `json:"pool-token-asset-id"`
+	Asset1Reserves     string `json:"asset1-reserves"`
+	Asset2Reserves     string `json:"asset2-reserves"`
+	IssuedPoolTokens   string `json:"issued-pool-tokens"`
+	Asset1ProtocolFees string `json:"asset1-protocol-fees"`
+	Asset2ProtocolFees string `json:"asset2-protocol-fees"`
+	TotalFeeShare      int    `json:"total-fee-share"`
+	ProtocolFeeRatio   int    `json:"protocol-fee-ratio"`
+	ValidatorAppId     int    `json:"validator-app-id"`
+	AlgoBalance        string `json:"algo-balance"`
+	Round              int    `json:"round"`
+}
+
+func GetPoolInfo(client *client.TinymanClient, validatorAppID, asset1ID, asset2ID int) (poolInfo *PoolInfo, err error) {
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppID, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	accountInfo, err := client.AccountInformation(poolAddress)
+	if err != nil {
+		return
+	}
+
+	return GetPoolInfoFromAccountInfo(accountInfo, validatorAppID)
+
+}
+
+// GetPoolInfoFromAccountInfo reads the pool state from the local state of the pool account in the validator app.
+// poolInfo is nil when the pool is not bootstrapped.
+// not compatible with go-mobile
+func GetPoolInfoFromAccountInfo(accountInfo models.Account, validatorAppID int) (poolInfo *PoolInfo, err error) {
+
+	var localState *models.ApplicationLocalState
+	for i, a := range accountInfo.AppsLocalState {
+		if a.Id == uint64(validatorAppID) {
+			localState = &accountInfo.AppsLocalState[i]
+		}
+	}
+
+	if localState == nil {
+		return
+	}
+
+	state := make(map[string]models.TealValue)
+	for _, x := range localState.KeyValue {
+		state[x.Key] = x.Value
+	}
+
+	asset1ID := utils.GetStateInt(state, "asset_1_id")
+	asset2ID := utils.GetStateInt(state, "asset_2_id")
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppID, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	if accountInfo.Address != poolAddress {
+		err = fmt.Errorf("accountInfo address is not equal to poolAddress")
+		return
+	}
+
+	stateString := func(key string) string {
+		return new(big.Int).SetUint64(uint64(utils.GetStateInt(state, key))).String()
+	}
+
+	poolInfo = &PoolInfo{
+		Address:            poolAddress,
+		Asset1Id:           asset1ID,
+		Asset2Id:           asset2ID,
+		PoolTokenAssetId:   utils.GetStateInt(state, "pool_token_asset_id"),
+		Asset1Reserves:     stateString("asset_1_reserves"),
+		Asset2Reserves:     stateString("asset_2_reserves"),
+		IssuedPoolTokens:   stateString("issued_pool_tokens"),
+		Asset1ProtocolFees: stateString("asset_1_protocol_fees"),
+		Asset2ProtocolFees: stateString("asset_2_protocol_fees"),
+		TotalFeeShare:      utils.GetStateInt(state, "total_fee_share"),
+		ProtocolFeeRatio:   utils.GetStateInt(state, "protocol_fee_ratio"),
+		ValidatorAppId:     validatorAppID,
+		AlgoBalance:        new(big.Int).SetUint64(accountInfo.Amount).String(),
+		Round:              int(accountInfo.Round),
+	}
+
+	return
+
+}
+
+type SwapQuote struct {
+	SwapType    string             `json:"swap-type"`
+	AmountIn    *types.AssetAmount `json:"amount-in"`
+	AmountOut   *types.AssetAmount `json:"amount-out"`
+	SwapFees    *types.AssetAmount `json:"swap-fees"`
+	Slippage    float64            `json:"slippage"`
+	PriceImpact float64            `json:"price-impact"`
+}
+
+func (s *SwapQuote) AmountOutWithSlippage() (assetAmount *types.AssetAmount, err error) {
+
+	if s.SwapType == constants.FIXED_OUTPUT_SWAP_TYPE {
+		return s.AmountOut, nil
+	}
+
+	return s.AmountOut.Sub(s.AmountOut.Mul(s.Slippage))
+
+}
+
+func (s *SwapQuote) AmountInWithSlippage() (assetAmount *types.AssetAmount, err error) {
+
+	if s.SwapType == constants.FIXED_INPUT_SWAP_TYPE {
+		return s.AmountIn, nil
+	}
+
+	return s.AmountIn.Add(s.AmountIn.Mul(s.Slippage))
+
+}
+
+func (s *SwapQuote) Price() float64 {
+
+	price := new(big.Float).Quo(utils.NewBigFloatString(s.AmountOut.Amount), utils.NewBigFloatString(s.AmountIn.Amount))
+	priceFloat64, _ := price.Float64()
+
+	return priceFloat64
+
+}
+
+const (
+	ADD_LIQUIDITY_INITIAL  = "initial"
+	ADD_LIQUIDITY_FLEXIBLE = "flexible"
+	ADD_LIQUIDITY_SINGLE   = "single"
+)
+
+type AddLiquidityQuote struct {
+	Mode                 string             `json:"mode"`
+	amountsIn            map[int]string     // map[asset.id][assetAmount.Amount]
+	PoolTokenAssetAmount *types.AssetAmount `json:"pool-token-asset-amount"`
+	// InternalSwapFees is the fee of the swap the pool does to match its ratio, nil when there is none.
+	InternalSwapFees *types.AssetAmount `json:"internal-swap-fees,omitempty"`
+	Slippage         float64            `json:"slippage"`
+}
+
+func (s *AddLiquidityQuote) GetAmountsIn() map[int]string {
+	return s.amountsIn
+}
+
+func (s *AddLiquidityQuote) GetAmountsInStr() (string, error) {
+
+	amountsIn, err := json.Marshal(s.amountsIn)
+	return string(amountsIn), err
+
+}
+
+func (s *AddLiquidityQuote) PoolTokenAssetAmountWithSlippage() (assetAmount *types.AssetAmount, err error) {
+
+	if s.Mode == ADD_LIQUIDITY_INITIAL {
+		return s.PoolTokenAssetAmount, nil
+	}
+
+	return s.PoolTokenAssetAmount.Sub(s.PoolTokenAssetAmount.Mul(s.Slippage))
+
+}
+
+type RemoveLiquidityQuote struct {
+	PoolTokenAssetAmount *types.AssetAmount `json:"pool-token-asset-amount"`
+	amountsOut           map[int]string     // map[asset.id][assetAmount.Amount]
+	// Single is set when the whole output is in AssetOutId, the pool swaps the share of the other asset.
+	Single     bool    `json:"single"`
+	AssetOutId int     `json:"asset-out-id"`
+	Slippage   float64 `json:"slippage"`
+}
+
+func (s *RemoveLiquidityQuote) GetAmountsOut() map[int]string {
+	return s.amountsOut
+}
+
+func (s *RemoveLiquidityQuote) GetAmountsOutStr() (string, error) {
+
+	amountsOut, err := json.Marshal(s.amountsOut)
+	return string(amountsOut), err
+
+}
+
+func (s *RemoveLiquidityQuote) AmountsOutWithSlippage() (amountsOutWithSlippage map[int]string) {
+
+	amountsOutWithSlippage = make(map[int]string)
+
+	for assetID, amount := range s.amountsOut {
+
+		amountWithSlippage := new(big.Float).Mul(utils.NewBigFloatString(amount), big.NewFloat(1-s.Slippage))
+		amountWithSlippageInt, _ := amountWithSlippage.Int(nil)
+
+		amountsOutWithSlippage[assetID] = amountWithSlippageInt.String()
+
+	}
+
+	return
+
+}
+
+type FlashLoanQuote struct {
+	Asset1Loan    *types.AssetAmount `json:"asset1-loan"`
+	Asset2Loan    *types.AssetAmount `json:"asset2-loan"`
+	Asset1Payment *types.AssetAmount `json:"asset1-payment"`
+	Asset2Payment *types.AssetAmount `json:"asset2-payment"`
+}
+
+type Pool struct {
+	Client             *client.TinymanClient `json:"client"`
+	ValidatorAppId     int                   `json:"validator-app-id"`
+	Asset1             *types.Asset          `json:"asset1"`
+	Asset2             *types.Asset          `json:"asset2"`
+	Exists             bool                  `json:"exists"`
+	PoolTokenAsset     *types.Asset          `json:"pool-token-asset"`
+	Asset1Reserves     string                `json:"asset1-reserves"`
+	Asset2Reserves     string                `json:"asset2-reserves"`
+	IssuedPoolTokens   string                `json:"issued-pool-tokens"`
+	Asset1ProtocolFees string                `json:"asset1-protocol-fees"`
+	Asset2ProtocolFees string                `json:"asset2-protocol-fees"`
+	TotalFeeShare      int                   `json:"total-fee-share"`
+	ProtocolFeeRatio   int                   `json:"protocol-fee-ratio"`
+	AlgoBalance        string                `json:"algo-balance"`
+	LastRefreshedRound int                   `json:"last-refreshed-round"`
+}
+
+func NewPool(client *client.TinymanClient, assetA, assetB *types.Asset, info *PoolInfo, fetch bool, validatorAppId int) (pool *Pool, err error) {
+
+	if assetA == nil || assetB == nil {
+		err = fmt.Errorf("assetA and assetB are required")
+		return
+	}
+
+	pool = &Pool{
+		Client:           client,
+		ValidatorAppId:   validatorAppId,
+		TotalFeeShare:    constants.DEFAULT_TOTAL_FEE_SHARE,
+		ProtocolFeeRatio: constants.DEFAULT_PROTOCOL_FEE_RATIO,
+		Asset1Reserves:   "0",
+		Asset2Reserves:   "0",
+		IssuedPoolTokens: "0",
+		AlgoBalance:      "0",
+	}
+
+	if validatorAppId == 0 {
+		pool.ValidatorAppId = client.ValidatorAppId
+	}
+
+	if assetA.Id > assetB.Id {
+		pool.Asset1, pool.Asset2 = assetA, assetB
+	} else {
+		pool.Asset1, pool.Asset2 = assetB, assetA
+	}
+
+	if fetch {
+		err = pool.Refresh()
+	} else if info != nil {
+		pool.UpdateFromInfo(info)
+	}
+
+	return
+
+}
+
+// not compatible with go-mobile
+func NewPoolFromAccountInfo(accountInfo models.Account, client *client.TinymanClient, validatorAppId int) (pool *Pool, err error) {
+
+	info, err := GetPoolInfoFromAccountInfo(accountInfo, validatorAppId)
+	if err != nil {
+		return
+	}
+
+	if info == nil {
+		err = fmt.Errorf("account is not a bootstrapped pool")
+		return
+	}
+
+	asset1, err := client.FetchAsset(info.Asset1Id)
+	if err != nil {
+		return
+	}
+
+	asset2, err := client.FetchAsset(info.Asset2Id)
+	if err != nil {
+		return
+	}
+
+	return NewPool(client, asset1, asset2, info, false, validatorAppId)
+
+}
+
+func (s *Pool) Refresh() (err error) {
+
+	info, err := GetPoolInfo(s.Client, s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id)
+	if err != nil || info == nil {
+		return
+	}
+
+	s.UpdateFromInfo(info)
+
+	return
+
+}
+
+func (s *Pool) UpdateFromInfo(info *PoolInfo) {
+
+	if info.PoolTokenAssetId != 0 {
+		s.Exists = true
+	}
+
+	s.PoolTokenAsset = &types.Asset{Id: info.PoolTokenAssetId, Name: fmt.Sprintf("TinymanPool2.0 %s-%s", s.Asset1.UnitName, s.Asset2.UnitName), UnitName: constants.POOL_TOKEN_UNIT_NAME, Decimals: 6}
+	s.Asset1Reserves = info.Asset1Reserves
+	s.Asset2Reserves = info.Asset2Reserves
+	s.IssuedPoolTokens = info.IssuedPoolTokens
+	s.Asset1ProtocolFees = info.Asset1ProtocolFees
+	s.Asset2ProtocolFees = info.Asset2ProtocolFees
+	s.TotalFeeShare = info.TotalFeeShare
+	s.ProtocolFeeRatio = info.ProtocolFeeRatio
+	s.AlgoBalance = info.AlgoBalance
+	s.LastRefreshedRound = info.Round
+
+}
+
+func (s *Pool) Address() (poolAddress string, err error) {
+	return contracts.GetPoolAddress(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id)
+}
+
+func (s *Pool) Asset1Price() float64 {
+
+	price := new(big.Float).Quo(utils.NewBigFloatString(s.Asset2Reserves), utils.NewBigFloatString(s.Asset1Reserves))
+	priceFloat64, _ := price.Float64()
+
+	return priceFloat64
+
+}
+
+func (s *Pool) Asset2Price() float64 {
+
+	price := new(big.Float).Quo(utils.NewBigFloatString(s.Asset1Reserves), utils.NewBigFloatString(s.Asset2Reserves))
+	priceFloat64, _ := price.Float64()
+
+	return priceFloat64
+
+}
+
+func (s *Pool) Info() *PoolInfo {
+
+	address, _ := s.Address()
+
+	poolTokenAssetId := 0
+	if s.PoolTokenAsset != nil {
+		poolTokenAssetId = s.PoolTokenAsset.Id
+	}
+
+	return &PoolInfo{
+		Address:            address,
+		Asset1Id:           s.Asset1.Id,
+		Asset2Id:           s.Asset2.Id,
+		PoolTokenAssetId:   poolTokenAssetId,
+		Asset1Reserves:     s.Asset1Reserves,
+		Asset2Reserves:     s.Asset2Reserves,
+		IssuedPoolTokens:   s.IssuedPoolTokens,
+		Asset1ProtocolFees: s.Asset1ProtocolFees,
+		Asset2ProtocolFees: s.Asset2ProtocolFees,
+		TotalFeeShare:      s.TotalFeeShare,
+		ProtocolFeeRatio:   s.ProtocolFeeRatio,
+		ValidatorAppId:     s.ValidatorAppId,
+		AlgoBalance:        s.AlgoBalance,
+		Round:              s.LastRefreshedRound,
+	}
+
+}
+
+// supplies returns the reserves of the input and the output asset of a swap from assetIn.
+func (s *Pool) supplies(assetIn *types.Asset) (inputSupply, outputSupply *big.Int, assetOut *types.Asset, err error) {
+
+	if *assetIn == *s.Asset1 {
+		return utils.NewBigIntString(s.Asset1Reserves), utils.NewBigIntString(s.Asset2Reserves), s.Asset2, nil
+	}
+
+	if *assetIn == *s.Asset2 {
+		return utils.NewBigIntString(s.Asset2Reserves), utils.NewBigIntString(s.Asset1Reserves), s.Asset1, nil
+	}
+
+	err = fmt.Errorf("asset %d does not belong to the pool", assetIn.Id)
+	return
+
+}
+
+func (s *Pool) refreshExisting() (err error) {
+
+	err = s.Refresh()
+	if err != nil {
+		return
+	}
+
+	if !s.Exists {
+		err = fmt.Errorf("pool has not been bootstrapped yet")
+	}
+
+	return
+
+}
+
+func (s *Pool) FetchFixedInputSwapQuote(amountIn *types.AssetAmount, slippage float64) (quote *SwapQuote, err error) {
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	inputSupply, outputSupply, assetOut, err := s.supplies(amountIn.Asset)
+	if err != nil {
+		return
+	}
+
+	inputAmount := utils.NewBigIntString(amountIn.Amount)
+
+	outputAmount, totalFeeAmount, err := calculateFixedInputSwap(inputSupply, outputSupply, inputAmount, s.TotalFeeShare)
+	if err != nil {
+		return
+	}
+
+	quote = &SwapQuote{
+		SwapType:    constants.FIXED_INPUT_SWAP_TYPE,
+		AmountIn:    amountIn,
+		AmountOut:   assetOut.Call(outputAmount.String()),
+		SwapFees:    amountIn.Asset.Call(totalFeeAmount.String()),
+		Slippage:    slippage,
+		PriceImpact: calculatePriceImpact(inputSupply, outputSupply, inputAmount, outputAmount),
+	}
+
+	return
+
+}
+
+func (s *Pool) FetchFixedOutputSwapQuote(amountOut *types.AssetAmount, slippage float64) (quote *SwapQuote, err error) {
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	outputSupply, inputSupply, assetIn, err := s.supplies(amountOut.Asset)
+	if err != nil {
+		return
+	}
+
+	outputAmount := utils.NewBigIntString(amountOut.Amount)
+
+	inputAmount, totalFeeAmount, err := calculateFixedOutputSwap(inputSupply, outputSupply, outputAmount, s.TotalFeeShare)
+	if err != nil {
+		return
+	}
+
+	quote = &SwapQuote{
+		SwapType:    constants.FIXED_OUTPUT_SWAP_TYPE,
+		AmountIn:    assetIn.Call(inputAmount.String()),
+		AmountOut:   amountOut,
+		SwapFees:    assetIn.Call(totalFeeAmount.String()),
+		Slippage:    slippage,
+		PriceImpact: calculatePriceImpact(inputSupply, outputSupply, inputAmount, outputAmount),
+	}
+
+	return
+
+}
+
+// FetchAddLiquidityQuote quotes adding amountA and amountB, either may be nil for a single asset add.
+// The first liquidity of a pool needs both amounts and sets its price.
+func (s *Pool) FetchAddLiquidityQuote(amountA, amountB *types.AssetAmount, slippage float64) (quote *AddLiquidityQuote, err error) {
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	amount1 := s.Asset1.Call("0")
+	amount2 := s.Asset2.Call("0")
+
+	for _, amount := range []*types.AssetAmount{amountA, amountB} {
+
+		if amount == nil {
+			continue
+		}
+
+		if *amount.Asset == *s.Asset1 {
+			amount1 = amount
+		} else if *amount.Asset == *s.Asset2 {
+			amount2 = amount
+		} else {
+			err = fmt.Errorf("asset %d does not belong to the pool", amount.Asset.Id)
+			return
+		}
+
+	}
+
+	asset1Amount := utils.NewBigIntString(amount1.Amount)
+	asset2Amount := utils.NewBigIntString(amount2.Amount)
+
+	quote = &AddLiquidityQuote{
+		amountsIn: map[int]string{
+			s.Asset1.Id: asset1Amount.String(),
+			s.Asset2.Id: asset2Amount.String(),
+		},
+		Slippage: slippage,
+	}
+
+	var poolTokenAmount *big.Int
+
+	if utils.NewBigIntString(s.IssuedPoolTokens).Sign() == 0 {
+
+		if asset1Amount.Sign() == 0 || asset2Amount.Sign() == 0 {
+			err = fmt.Errorf("amounts required for both assets for the initial liquidity")
+			return
+		}
+
+		quote.Mode = ADD_LIQUIDITY_INITIAL
+		quote.Slippage = 0
+
+		poolTokenAmount, err = calculateInitialAddLiquidity(asset1Amount, asset2Amount)
+		if err != nil {
+			return
+		}
+
+	} else {
+
+		quote.Mode = ADD_LIQUIDITY_FLEXIBLE
+		if asset1Amount.Sign() == 0 || asset2Amount.Sign() == 0 {
+			quote.Mode = ADD_LIQUIDITY_SINGLE
+		}
+
+		var internalSwapFeeAmount *big.Int
+		var internalSwapAssetIndex int
+		poolTokenAmount, internalSwapFeeAmount, internalSwapAssetIndex, err = calculateSubsequentAddLiquidity(
+			utils.NewBigIntString(s.Asset1Reserves),
+			utils.NewBigIntString(s.Asset2Reserves),
+			utils.NewBigIntString(s.IssuedPoolTokens),
+			asset1Amount,
+			asset2Amount,
+			s.TotalFeeShare,
+		)
+		if err != nil {
+			return
+		}
+
+		if internalSwapAssetIndex == 1 {
+			quote.InternalSwapFees = s.Asset1.Call(internalSwapFeeAmount.String())
+		} else if internalSwapAssetIndex == 2 {
+			quote.InternalSwapFees = s.Asset2.Call(internalSwapFeeAmount.String())
+		}
+
+	}
+
+	quote.PoolTokenAssetAmount = s.PoolTokenAsset.Call(poolTokenAmount.String())
+
+	return
+
+}
+
+// FetchRemoveLiquidityQuote quotes removing poolTokenAssetIn for the share of both assets.
+func (s *Pool) FetchRemoveLiquidityQuote(poolTokenAssetIn *types.AssetAmount, slippage float64) (quote *RemoveLiquidityQuote, err error) {
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	asset1Amount, asset2Amount, err := calculateRemoveLiquidityOutputAmounts(
+		utils.NewBigIntString(s.Asset1Reserves),
+		utils.NewBigIntString(s.Asset2Reserves),
+		utils.NewBigIntString(s.IssuedPoolTokens),
+		utils.NewBigIntString(poolTokenAssetIn.Amount),
+	)
+	if err != nil {
+		return
+	}
+
+	quote = &RemoveLiquidityQuote{
+		PoolTokenAssetAmount: poolTokenAssetIn,
+		amountsOut: map[int]string{
+			s.Asset1.Id: asset1Amount.String(),
+			s.Asset2.Id: asset2Amount.String(),
+		},
+		Slippage: slippage,
+	}
+
+	return
+
+}
+
+// FetchSingleAssetRemoveLiquidityQuote quotes removing poolTokenAssetIn for assetOut only,
+// the share of the other asset is swapped to assetOut from the reserves left after the removal.
+func (s *Pool) FetchSingleAssetRemoveLiquidityQuote(poolTokenAssetIn *types.AssetAmount, assetOut *types.Asset, slippage float64) (quote *RemoveLiquidityQuote, err error) {
+
+	quote, err = s.FetchRemoveLiquidityQuote(poolTokenAssetIn, slippage)
+	if err != nil {
+		return
+	}
+
+	outputSupply, inputSupply, assetIn, err := s.supplies(assetOut)
+	if err != nil {
+		return
+	}
+
+	outputAmount := utils.NewBigIntString(quote.amountsOut[assetOut.Id])
+	swapInputAmount := utils.NewBigIntString(quote.amountsOut[assetIn.Id])
+
+	outputSupply.Sub(outputSupply, outputAmount)
+	inputSupply.Sub(inputSupply, swapInputAmount)
+
+	swapOutputAmount, _, err := calculateFixedInputSwap(inputSupply, outputSupply, swapInputAmount, s.TotalFeeShare)
+	if err != nil {
+		return
+	}
+
+	quote.Single = true
+	quote.AssetOutId = assetOut.Id
+	quote.amountsOut = map[int]string{
+		assetOut.Id: new(big.Int).Add(outputAmount, swapOutputAmount).String(),
+	}
+
+	return
+
+}
+
+// FetchFlashLoanQuote quotes borrowing the loan amounts, either may be nil.
+// The payments are the loans plus the flash loan fee of TotalFeeShare, rounded up.
+func (s *Pool) FetchFlashLoanQuote(loanAmountA, loanAmountB *types.AssetAmount) (quote *FlashLoanQuote, err error) {
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	quote = &FlashLoanQuote{
+		Asset1Loan: s.Asset1.Call("0"),
+		Asset2Loan: s.Asset2.Call("0"),
+	}
+
+	for _, amount := range []*types.AssetAmount{loanAmountA, loanAmountB} {
+
+		if amount == nil {
+			continue
+		}
+
+		reserves, _, _, suppliesErr := s.supplies(amount.Asset)
+		if suppliesErr != nil {
+			err = suppliesErr
+			return
+		}
+
+		if utils.NewBigIntString(amount.Amount).Cmp(reserves) >= 0 {
+			err = fmt.Errorf("flash loan amount exceeds the pool reserves")
+			return
+		}
+
+		if *amount.Asset == *s.Asset1 {
+			quote.Asset1Loan = amount
+		} else {
+			quote.Asset2Loan = amount
+		}
+
+	}
+
+	quote.Asset1Payment = s.Asset1.Call(calculateFlashLoanPaymentAmount(utils.NewBigIntString(quote.Asset1Loan.Amount), s.TotalFeeShare).String())
+	quote.Asset2Payment = s.Asset2.Call(calculateFlashLoanPaymentAmount(utils.NewBigIntString(quote.Asset2Loan.Amount), s.TotalFeeShare).String())
+
+	return
+
+}
+
+func (s *Pool) userAddress(address string) (string, error) {
+
+	if len(address) == 0 {
+		address = s.Client.UserAddress
+	}
+
+	user, err := algoTypes.DecodeAddress(address)
+	return user.String(), err
+
+}
+
+// PrepareBootstrapTransactions creates the pool, funding the pool account with what it is missing of its min balance
+// and of the bootstrap app call fee.
+func (s *Pool) PrepareBootstrapTransactions(userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	user, err := s.userAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	poolAddress, err := s.Address()
+	if err != nil {
+		return
+	}
+
+	poolAccount, err := s.Client.AccountInformation(poolAddress)
+	if err != nil {
+		return
+	}
+
+	requiredAlgo := bootstrap.GetPoolMinBalance(s.Asset2.Id, suggestedParams.ConsensusVersion) + bootstrap.GetBootstrapAppCallFee(s.Asset2.Id, suggestedParams) - int(poolAccount.Amount)
+	if requiredAlgo < 0 {
+		requiredAlgo = 0
+	}
+
+	return bootstrap.PrepareBootstrapTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, fmt.Sprint(requiredAlgo), user, suggestedParams, options)
+
+}
+
+func (s *Pool) PrepareSwapTransactionsFromQuote(quote *SwapQuote, swapperAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	swapper, err := s.userAddress(swapperAddress)
+	if err != nil {
+		return
+	}
+
+	amountIn, err := quote.AmountInWithSlippage()
+	if err != nil {
+		return
+	}
+
+	amountOut, err := quote.AmountOutWithSlippage()
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	return swap.PrepareSwapTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, amountIn.Asset.Id, amountIn.Amount, amountOut.Amount, quote.SwapType, swapper, suggestedParams, options)
+
+}
+
+func (s *Pool) PrepareAddLiquidityTransactionsFromQuote(quote *AddLiquidityQuote, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	user, err := s.userAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	poolTokenAmount, err := quote.PoolTokenAssetAmountWithSlippage()
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	asset1Amount := quote.amountsIn[s.Asset1.Id]
+	asset2Amount := quote.amountsIn[s.Asset2.Id]
+
+	switch quote.Mode {
+
+	case ADD_LIQUIDITY_INITIAL:
+		return addliquidity.PrepareInitialAddLiquidityTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, s.PoolTokenAsset.Id, asset1Amount, asset2Amount, user, suggestedParams, options)
+
+	case ADD_LIQUIDITY_FLEXIBLE:
+		return addliquidity.PrepareFlexibleAddLiquidityTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, s.PoolTokenAsset.Id, asset1Amount, asset2Amount, poolTokenAmount.Amount, user, suggestedParams, options)
+
+	case ADD_LIQUIDITY_SINGLE:
+
+		assetInID, assetInAmount := s.Asset1.Id, asset1Amount
+		if utils.NewBigIntString(asset1Amount).Sign() == 0 {
+			assetInID, assetInAmount = s.Asset2.Id, asset2Amount
+		}
+
+		return addliquidity.PrepareSingleAssetAddLiquidityTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, s.PoolTokenAsset.Id, assetInID, assetInAmount, poolTokenAmount.Amount, user, suggestedParams, options)
+
+	}
+
+	err = fmt.Errorf("unsupported add liquidity mode %s", quote.Mode)
+	return
+
+}
+
+func (s *Pool) PrepareRemoveLiquidityTransactionsFromQuote(quote *RemoveLiquidityQuote, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	user, err := s.userAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	amountsOut := quote.AmountsOutWithSlippage()
+
+	if quote.Single {
+		return removeliquidity.PrepareSingleAssetRemoveLiquidityTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, s.PoolTokenAsset.Id, quote.AssetOutId, quote.PoolTokenAssetAmount.Amount, amountsOut[quote.AssetOutId], user, suggestedParams, options)
+	}
+
+	return removeliquidity.PrepareRemoveLiquidityTransactions(s.ValidatorAppId, s.Asset1.Id, s.Asset2.Id, s.PoolTokenAsset.Id, quote.PoolTokenAssetAmount.Amount, amountsOut[s.Asset1.Id], amountsOut[s.Asset2.Id], user, suggestedParams, options)
+
+}
+
+// PrepareFlashLoanTransactionsFromQuote wraps the unsigned transactions of innerGroup in a flash loan of quote.
+func (s *Pool) PrepareFlashLoanTransactionsFromQuote(quote *FlashLoanQuote, innerGroup *utils.TransactionGroup, userAddress string, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	user, err := s.userAddress(userAddress)
+	if err != nil {
+		return
+	}
+
+	suggestedParams, err := s.Client.GetSuggestedParams(options)
+	if err != nil {
+		return
+	}
+
+	return flashloan.PrepareFlashLoanTransactions(
+		s.ValidatorAppId,
+		s.Asset1.Id,
+		s.Asset2.Id,
+		quote.Asset1Loan.Amount,
+		quote.Asset2Loan.Amount,
+		quote.Asset1Payment.Amount,
+		quote.Asset2Payment.Amount,
+		innerGroup,
+		user,
+		suggestedParams,
+		options,
+	)
+
+}
+
+// FetchPoolPosition returns the pool tokens of poolerAddress and their share of the pool assets.
+func (s *Pool) FetchPoolPosition(poolerAddress string) (poolPosition map[string]string, err error) {
+
+	pooler, err := s.userAddress(poolerAddress)
+	if err != nil {
+		return
+	}
+
+	err = s.refreshExisting()
+	if err != nil {
+		return
+	}
+
+	accountInfo, err := s.Client.AccountInformation(pooler)
+	if err != nil {
+		return
+	}
+
+	poolTokenAmount := big.NewInt(0)
+	for _, a := range accountInfo.Assets {
+		if a.AssetId == uint64(s.PoolTokenAsset.Id) {
+			poolTokenAmount.SetUint64(a.Amount)
+		}
+	}
+
+	asset1Amount, asset2Amount, err := calculateRemoveLiquidityOutputAmounts(
+		utils.NewBigIntString(s.Asset1Reserves),
+		utils.NewBigIntString(s.Asset2Reserves),
+		utils.NewBigIntString(s.IssuedPoolTokens),
+		poolTokenAmount,
+	)
+	if err != nil {
+		return
+	}
+
+	share := new(big.Float).Quo(new(big.Float).SetInt(poolTokenAmount), utils.NewBigFloatString(s.IssuedPoolTokens))
+
+	poolPosition = map[string]string{
+		strconv.Itoa(s.Asset1.Id):         asset1Amount.String(),
+		strconv.Itoa(s.Asset2.Id):         asset2Amount.String(),
+		strconv.Itoa(s.PoolTokenAsset.Id): poolTokenAmount.String(),
+		"share":                           share.String(),
+	}
+
+	return
+
+}
+
+func (s *Pool) FetchPoolPositionStr(poolerAddress string) (poolPositionStr string, err error) {
+
+	poolPosition, err := s.FetchPoolPosition(poolerAddress)
+	if err != nil {
+		return
+	}
+
+	poolPositionBytes, err := json.Marshal(poolPosition)
+	if err != nil {
+		return
+	}
+
+	poolPositionStr = string(poolPositionBytes)
+	return
+
+}
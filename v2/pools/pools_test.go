@@ -0,0 +1,169 @@
+package pools
+
+import (
+	b64 "encoding/base64"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/client"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/client/v2/common/models"
+	"github.com/algorand/go-algorand-sdk/crypto"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// mockPool serves the pool account of asset1ID and asset2ID with the given local state from algodURL.
+func mockPool(t *testing.T, algodURL string, asset1ID, asset2ID int, state map[string]uint64) {
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, asset1ID, asset2ID)
+	assert.Nil(t, err)
+
+	account := models.Account{Address: poolAddress, Amount: 1000000, Round: 100}
+
+	if state != nil {
+
+		keyValues := []models.TealKeyValue{
+			{Key: b64.StdEncoding.EncodeToString([]byte("asset_1_id")), Value: models.TealValue{Type: 2, Uint: uint64(asset1ID)}},
+			{Key: b64.StdEncoding.EncodeToString([]byte("asset_2_id")), Value: models.TealValue{Type: 2, Uint: uint64(asset2ID)}},
+		}
+
+		for key, value := range state {
+			keyValues = append(keyValues, models.TealKeyValue{Key: b64.StdEncoding.EncodeToString([]byte(key)), Value: models.TealValue{Type: 2, Uint: value}})
+		}
+
+		account.AppsLocalState = []models.ApplicationLocalState{{Id: constants.TESTNET_VALIDATOR_APP_ID, KeyValue: keyValues}}
+
+	}
+
+	gock.New(algodURL).Get(fmt.Sprintf("/v2/accounts/%s", poolAddress)).Persist().Reply(200).JSON(account)
+
+}
+
+func TestPoolQuotes(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	tinymanClient, err := client.NewTinymanTestnetClient(algodURL, indexerURL, crypto.GenerateAccount().Address.String())
+	assert.Nil(t, err)
+
+	mockPool(t, algodURL, 10, 0, map[string]uint64{
+		"pool_token_asset_id": 20,
+		"asset_1_reserves":    1000000,
+		"asset_2_reserves":    2000000,
+		"issued_pool_tokens":  1414213,
+		"total_fee_share":     30,
+		"protocol_fee_ratio":  6,
+	})
+
+	asset1 := &types.Asset{Id: 10, Name: "Test", UnitName: "TEST", Decimals: 6}
+	asset2 := &types.Asset{Id: 0, Name: "Algo", UnitName: "ALGO", Decimals: 6}
+
+	pool, err := NewPool(tinymanClient, asset2, asset1, nil, false, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, asset1, pool.Asset1)
+	assert.Equal(t, asset2, pool.Asset2)
+
+	swapQuote, err := pool.FetchFixedInputSwapQuote(asset1.Call("10000"), 0.01)
+	assert.Nil(t, err)
+	assert.True(t, pool.Exists)
+	assert.Equal(t, 20, pool.PoolTokenAsset.Id)
+	assert.Equal(t, 100, pool.LastRefreshedRound)
+
+	assert.Equal(t, constants.FIXED_INPUT_SWAP_TYPE, swapQuote.SwapType)
+	assert.Equal(t, asset2.Call("19743"), swapQuote.AmountOut)
+	assert.Equal(t, asset1.Call("30"), swapQuote.SwapFees)
+
+	amountOut, err := swapQuote.AmountOutWithSlippage()
+	assert.Nil(t, err)
+	assert.Equal(t, "19546", amountOut.Amount)
+
+	amountIn, err := swapQuote.AmountInWithSlippage()
+	assert.Nil(t, err)
+	assert.Equal(t, "10000", amountIn.Amount)
+
+	swapQuote, err = pool.FetchFixedOutputSwapQuote(asset2.Call("10000"), 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, constants.FIXED_OUTPUT_SWAP_TYPE, swapQuote.SwapType)
+	assert.Equal(t, asset1.Call("5041"), swapQuote.AmountIn)
+	assert.Equal(t, asset1.Call("15"), swapQuote.SwapFees)
+
+	amountIn, err = swapQuote.AmountInWithSlippage()
+	assert.Nil(t, err)
+	assert.Equal(t, "5091", amountIn.Amount)
+
+	_, err = pool.FetchFixedInputSwapQuote((&types.Asset{Id: 30}).Call("10000"), 0.01)
+	assert.NotNil(t, err)
+
+	addQuote, err := pool.FetchAddLiquidityQuote(asset1.Call("10000"), nil, 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, ADD_LIQUIDITY_SINGLE, addQuote.Mode)
+	assert.Equal(t, map[int]string{10: "10000", 0: "0"}, addQuote.GetAmountsIn())
+	assert.Equal(t, "7043", addQuote.PoolTokenAssetAmount.Amount)
+	assert.Equal(t, asset1.Call("15"), addQuote.InternalSwapFees)
+
+	poolTokenAmount, err := addQuote.PoolTokenAssetAmountWithSlippage()
+	assert.Nil(t, err)
+	assert.Equal(t, "6973", poolTokenAmount.Amount)
+
+	addQuote, err = pool.FetchAddLiquidityQuote(asset1.Call("10000"), asset2.Call("20000"), 0.01)
+	assert.Nil(t, err)
+	assert.Equal(t, ADD_LIQUIDITY_FLEXIBLE, addQuote.Mode)
+
+	removeQuote, err := pool.FetchRemoveLiquidityQuote(pool.PoolTokenAsset.Call("14142"), 0.01)
+	assert.Nil(t, err)
+	assert.False(t, removeQuote.Single)
+	assert.Equal(t, map[int]string{10: "9999", 0: "19999"}, removeQuote.GetAmountsOut())
+	assert.Equal(t, map[int]string{10: "9899", 0: "19799"}, removeQuote.AmountsOutWithSlippage())
+
+	// the asset 1 share is swapped to ALGO from the reserves left after the removal
+	swapOutputAmount, _, err := calculateFixedInputSwap(big.NewInt(990001), big.NewInt(1980001), big.NewInt(9999), 30)
+	assert.Nil(t, err)
+
+	removeQuote, err = pool.FetchSingleAssetRemoveLiquidityQuote(pool.PoolTokenAsset.Call("14142"), asset2, 0.01)
+	assert.Nil(t, err)
+	assert.True(t, removeQuote.Single)
+	assert.Equal(t, 0, removeQuote.AssetOutId)
+	assert.Equal(t, map[int]string{0: new(big.Int).Add(big.NewInt(19999), swapOutputAmount).String()}, removeQuote.GetAmountsOut())
+
+	flashLoanQuote, err := pool.FetchFlashLoanQuote(asset1.Call("10000"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, asset1.Call("10030"), flashLoanQuote.Asset1Payment)
+	assert.Equal(t, asset2.Call("0"), flashLoanQuote.Asset2Loan)
+	assert.Equal(t, asset2.Call("0"), flashLoanQuote.Asset2Payment)
+
+	_, err = pool.FetchFlashLoanQuote(nil, asset2.Call("2000000"))
+	assert.NotNil(t, err)
+
+}
+
+func TestPoolQuotesNotBootstrapped(t *testing.T) {
+
+	defer gock.Off()
+
+	algodURL := "https://algod.mockserver.com"
+	indexerURL := "https://indexer.mockserver.com"
+
+	tinymanClient, err := client.NewTinymanTestnetClient(algodURL, indexerURL, crypto.GenerateAccount().Address.String())
+	assert.Nil(t, err)
+
+	mockPool(t, algodURL, 10, 0, nil)
+
+	pool, err := NewPool(tinymanClient, &types.Asset{Id: 10}, &types.Asset{Id: 0}, nil, false, 0)
+	assert.Nil(t, err)
+
+	_, err = pool.FetchFixedInputSwapQuote(pool.Asset1.Call("10000"), 0.01)
+	assert.NotNil(t, err)
+	assert.False(t, pool.Exists)
+
+	_, err = pool.FetchFlashLoanQuote(pool.Asset1.Call("10000"), nil)
+	assert.NotNil(t, err)
+
+}
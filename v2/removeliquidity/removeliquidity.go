@@ -0,0 +1,105 @@
+package removeliquidity
+
+import (
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// PrepareRemoveLiquidityTransactions returns pool tokens to the pool for at least the minimum amounts of both assets.
+func PrepareRemoveLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID int, poolTokenAssetAmount, minAsset1Amount, minAsset2Amount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	return prepareRemoveLiquidityTransactions(
+		validatorAppId,
+		asset1ID,
+		asset2ID,
+		poolTokenAssetID,
+		poolTokenAssetAmount,
+		minAsset1Amount,
+		minAsset2Amount,
+		[]uint64{uint64(asset1ID), uint64(asset2ID)},
+		constants.REMOVE_LIQUIDITY_INNER_TRANSACTIONS,
+		senderAddress,
+		suggestedParams,
+		options,
+	)
+
+}
+
+// PrepareSingleAssetRemoveLiquidityTransactions returns pool tokens to the pool for at least minAssetOutAmount of assetOutID,
+// the pool swaps the share of the other asset internally.
+func PrepareSingleAssetRemoveLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID, assetOutID int, poolTokenAssetAmount, minAssetOutAmount, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	minAsset1Amount, minAsset2Amount := "0", "0"
+	if assetOutID == asset1ID {
+		minAsset1Amount = minAssetOutAmount
+	} else {
+		minAsset2Amount = minAssetOutAmount
+	}
+
+	return prepareRemoveLiquidityTransactions(
+		validatorAppId,
+		asset1ID,
+		asset2ID,
+		poolTokenAssetID,
+		poolTokenAssetAmount,
+		minAsset1Amount,
+		minAsset2Amount,
+		[]uint64{uint64(assetOutID)},
+		constants.SINGLE_REMOVE_LIQUIDITY_INNER_TRANSACTIONS,
+		senderAddress,
+		suggestedParams,
+		options,
+	)
+
+}
+
+func prepareRemoveLiquidityTransactions(validatorAppId, asset1ID, asset2ID, poolTokenAssetID int, poolTokenAssetAmount, minAsset1Amount, minAsset2Amount string, foreignAssets []uint64, innerTransactions int, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppId, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	poolTokenTransferTxn, err := utils.MakeTransferTxn(sender.String(), poolAddress, poolTokenAssetID, poolTokenAssetAmount, nil, algoSuggestedParams)
+	if err != nil {
+		return
+	}
+
+	appArgs := [][]byte{
+		[]byte(constants.REMOVE_LIQUIDITY_APP_ARGUMENT),
+		utils.IntToBytes(int(utils.NewBigIntString(minAsset1Amount).Uint64())),
+		utils.IntToBytes(int(utils.NewBigIntString(minAsset2Amount).Uint64())),
+	}
+
+	applicationNoOpTxn, err := future.MakeApplicationNoOpTx(uint64(validatorAppId), appArgs, []string{poolAddress}, nil, foreignAssets, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	txns := []algoTypes.Transaction{poolTokenTransferTxn, applicationNoOpTxn}
+
+	txns, err = utils.ApplyTxnOptionsWithInnerTransactions(txns, sender, options, map[int]int{1: innerTransactions})
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+
+	return
+
+}
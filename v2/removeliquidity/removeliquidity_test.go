@@ -0,0 +1,50 @@
+package removeliquidity
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareRemoveLiquidityTransactions(t *testing.T) {
+
+	sender := crypto.GenerateAccount().Address.String()
+	suggestedParams := &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, 10, 0)
+	assert.Nil(t, err)
+
+	txnGroup, err := PrepareRemoveLiquidityTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 20, "1000", "400", "800", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns := txnGroup.GetTransactions()
+	assert.Equal(t, 2, len(txns))
+
+	assert.Equal(t, algoTypes.AssetTransferTx, txns[0].Type)
+	assert.Equal(t, algoTypes.AssetIndex(20), txns[0].XferAsset)
+	assert.Equal(t, poolAddress, txns[0].AssetReceiver.String())
+	assert.Equal(t, uint64(1000), txns[0].AssetAmount)
+
+	assert.Equal(t, [][]byte{[]byte(constants.REMOVE_LIQUIDITY_APP_ARGUMENT), utils.IntToBytes(400), utils.IntToBytes(800)}, txns[1].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{10, 0}, txns[1].ForeignAssets)
+	assert.Equal(t, poolAddress, txns[1].Accounts[0].String())
+	assert.Equal(t, algoTypes.MicroAlgos((constants.REMOVE_LIQUIDITY_INNER_TRANSACTIONS+1)*1000), txns[1].Fee)
+
+	// a single asset removal only references the asset out
+	txnGroup, err = PrepareSingleAssetRemoveLiquidityTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 20, 0, "1000", "1500", sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, 2, len(txns))
+	assert.Equal(t, [][]byte{[]byte(constants.REMOVE_LIQUIDITY_APP_ARGUMENT), utils.IntToBytes(0), utils.IntToBytes(1500)}, txns[1].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{0}, txns[1].ForeignAssets)
+	assert.Equal(t, algoTypes.MicroAlgos((constants.SINGLE_REMOVE_LIQUIDITY_INNER_TRANSACTIONS+1)*1000), txns[1].Fee)
+
+}
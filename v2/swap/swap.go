@@ -0,0 +1,74 @@
+package swap
+
+import (
+	"fmt"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/future"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+)
+
+// PrepareSwapTransactions sends assetInAmount to the pool and calls the validator, which sends the output from the pool.
+// assetOutAmount is the minimum output of a fixed-input swap and the exact output of a fixed-output swap,
+// the pool refunds the unused input of a fixed-output swap.
+func PrepareSwapTransactions(validatorAppId, asset1ID, asset2ID, assetInID int, assetInAmount, assetOutAmount, swapType, senderAddress string, suggestedParams *types.SuggestedParams, options *types.TxnOptions) (txnGroup *utils.TransactionGroup, err error) {
+
+	sender, err := algoTypes.DecodeAddress(senderAddress)
+	if err != nil {
+		return
+	}
+
+	var innerTransactions int
+
+	switch swapType {
+	case constants.FIXED_INPUT_SWAP_TYPE:
+		innerTransactions = constants.FIXED_INPUT_SWAP_INNER_TRANSACTIONS
+	case constants.FIXED_OUTPUT_SWAP_TYPE:
+		innerTransactions = constants.FIXED_OUTPUT_SWAP_INNER_TRANSACTIONS
+	default:
+		err = fmt.Errorf("unsupported swap type %s", swapType)
+		return
+	}
+
+	poolAddress, err := contracts.GetPoolAddress(validatorAppId, asset1ID, asset2ID)
+	if err != nil {
+		return
+	}
+
+	algoSuggestedParams, err := utils.ToAlgoSuggestedParams(suggestedParams, options)
+	if err != nil {
+		return
+	}
+
+	assetTransferInTxn, err := utils.MakeTransferTxn(sender.String(), poolAddress, assetInID, assetInAmount, nil, algoSuggestedParams)
+	if err != nil {
+		return
+	}
+
+	appArgs := [][]byte{
+		[]byte(constants.SWAP_APP_ARGUMENT),
+		[]byte(swapType),
+		utils.IntToBytes(int(utils.NewBigIntString(assetOutAmount).Uint64())),
+	}
+
+	applicationNoOpTxn, err := future.MakeApplicationNoOpTx(uint64(validatorAppId), appArgs, []string{poolAddress}, nil, []uint64{uint64(asset1ID), uint64(asset2ID)}, algoSuggestedParams, sender, nil, algoTypes.Digest{}, [32]byte{}, algoTypes.Address{})
+	if err != nil {
+		return
+	}
+
+	txns := []algoTypes.Transaction{assetTransferInTxn, applicationNoOpTxn}
+
+	txns, err = utils.ApplyTxnOptionsWithInnerTransactions(txns, sender, options, map[int]int{1: innerTransactions})
+	if err != nil {
+		return
+	}
+
+	txnGroup, err = utils.NewTransactionGroup(txns)
+
+	return
+
+}
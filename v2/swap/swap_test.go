@@ -0,0 +1,58 @@
+package swap
+
+import (
+	"testing"
+
+	"github.com/soheil555/tinyman-mobile-sdk/types"
+	"github.com/soheil555/tinyman-mobile-sdk/utils"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/constants"
+	"github.com/soheil555/tinyman-mobile-sdk/v2/contracts"
+
+	"github.com/algorand/go-algorand-sdk/crypto"
+	algoTypes "github.com/algorand/go-algorand-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareSwapTransactions(t *testing.T) {
+
+	sender := crypto.GenerateAccount().Address.String()
+	suggestedParams := &types.SuggestedParams{MinFee: 1000, GenesisHash: make([]byte, 32), FirstRoundValid: 1, LastRoundValid: 1000}
+
+	poolAddress, err := contracts.GetPoolAddress(constants.TESTNET_VALIDATOR_APP_ID, 10, 0)
+	assert.Nil(t, err)
+
+	// the app call pays for the inner transaction that sends the output
+	txnGroup, err := PrepareSwapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 0, "1000", "900", constants.FIXED_INPUT_SWAP_TYPE, sender, suggestedParams, nil)
+	assert.Nil(t, err)
+
+	txns := txnGroup.GetTransactions()
+	assert.Equal(t, 2, len(txns))
+
+	assert.Equal(t, algoTypes.PaymentTx, txns[0].Type)
+	assert.Equal(t, poolAddress, txns[0].Receiver.String())
+	assert.Equal(t, algoTypes.MicroAlgos(1000), txns[0].Amount)
+	assert.Equal(t, algoTypes.MicroAlgos(1000), txns[0].Fee)
+
+	assert.Equal(t, algoTypes.ApplicationCallTx, txns[1].Type)
+	assert.Equal(t, algoTypes.AppIndex(constants.TESTNET_VALIDATOR_APP_ID), txns[1].ApplicationID)
+	assert.Equal(t, [][]byte{[]byte(constants.SWAP_APP_ARGUMENT), []byte(constants.FIXED_INPUT_SWAP_TYPE), utils.IntToBytes(900)}, txns[1].ApplicationArgs)
+	assert.Equal(t, []algoTypes.AssetIndex{10, 0}, txns[1].ForeignAssets)
+	assert.Equal(t, poolAddress, txns[1].Accounts[0].String())
+	assert.Equal(t, algoTypes.MicroAlgos(2000), txns[1].Fee)
+
+	// the fixed output swap also refunds the unused input
+	txnGroup, err = PrepareSwapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 10, "1000", "900", constants.FIXED_OUTPUT_SWAP_TYPE, sender, suggestedParams, &types.TxnOptions{FeeMultiplier: 2})
+	assert.Nil(t, err)
+
+	txns = txnGroup.GetTransactions()
+	assert.Equal(t, algoTypes.AssetTransferTx, txns[0].Type)
+	assert.Equal(t, algoTypes.AssetIndex(10), txns[0].XferAsset)
+	assert.Equal(t, uint64(1000), txns[0].AssetAmount)
+	assert.Equal(t, algoTypes.MicroAlgos(2000), txns[0].Fee)
+	assert.Equal(t, []byte(constants.FIXED_OUTPUT_SWAP_TYPE), txns[1].ApplicationArgs[1])
+	assert.Equal(t, algoTypes.MicroAlgos(6000), txns[1].Fee)
+
+	_, err = PrepareSwapTransactions(constants.TESTNET_VALIDATOR_APP_ID, 10, 0, 10, "1000", "900", "fixed", sender, suggestedParams, nil)
+	assert.NotNil(t, err)
+
+}